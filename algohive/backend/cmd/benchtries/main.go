@@ -0,0 +1,232 @@
+// Command benchtries measures the try-heavy queries of the API before and after the try indexes.
+//
+// It seeds a realistic dataset in a dedicated schema of the configured database, so the real
+// tables are never touched, runs every query without indexes, creates the indexes declared by
+// models.Try and database.CreateTryConstraints, and runs the queries again.
+//
+//	go run ./cmd/benchtries -competitions 20 -users 300 -puzzles 10
+package main
+
+import (
+	"api/config"
+	"api/database"
+	"api/models"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// benchSchema is the schema the dataset is seeded in
+const benchSchema = "bench_tries"
+
+// benchQuery is a query of an endpoint, run with a sampled competition and user
+type benchQuery struct {
+	name string
+	sql  string
+	args func(sample) []interface{}
+}
+
+// sample is a competition and one of its participants
+type sample struct {
+	CompetitionID string
+	UserID        string
+}
+
+var queries = []benchQuery{
+	{
+		name: "GetCompetitionTries",
+		sql:  `SELECT * FROM tries WHERE competition_id = ?`,
+		args: func(s sample) []interface{} { return []interface{}{s.CompetitionID} },
+	},
+	{
+		name: "GetUserCompetitionTries",
+		sql:  `SELECT * FROM tries WHERE competition_id = ? AND user_id = ?`,
+		args: func(s sample) []interface{} { return []interface{}{s.CompetitionID, s.UserID} },
+	},
+	{
+		name: "GetCompetitionStatistics",
+		sql: `SELECT COUNT(DISTINCT user_id), COUNT(DISTINCT user_id) FILTER (WHERE end_time IS NOT NULL),
+				COALESCE(AVG(score) FILTER (WHERE end_time IS NOT NULL), 0), COALESCE(MAX(score) FILTER (WHERE end_time IS NOT NULL), 0)
+			FROM tries WHERE competition_id = ?`,
+		args: func(s sample) []interface{} { return []interface{}{s.CompetitionID} },
+	},
+	{
+		name: "GetCompetitionLeaderboard",
+		sql: `SELECT user_id, SUM(score) FILTER (WHERE end_time IS NOT NULL), COUNT(*) FILTER (WHERE end_time IS NOT NULL), MAX(end_time)
+			FROM tries WHERE competition_id = ? GROUP BY user_id ORDER BY 2 DESC, 4`,
+		args: func(s sample) []interface{} { return []interface{}{s.CompetitionID} },
+	},
+	{
+		name: "GetUserProfileStats",
+		sql:  `SELECT puzzle_lvl, COUNT(*) FROM tries WHERE user_id = ? AND end_time IS NOT NULL GROUP BY puzzle_lvl`,
+		args: func(s sample) []interface{} { return []interface{}{s.UserID} },
+	},
+	{
+		name: "StartCompetitionTry (existing try lookup)",
+		sql:  `SELECT id FROM tries WHERE user_id = ? AND competition_id = ? AND puzzle_index = 0 AND step = 1`,
+		args: func(s sample) []interface{} { return []interface{}{s.UserID, s.CompetitionID} },
+	},
+}
+
+// seed creates the tries table in the bench schema and fills it.
+// Users are drawn from a shared pool so that they play several competitions
+func seed(db *gorm.DB, competitions int, users int, pool int, puzzles int) error {
+	if err := db.Exec("DROP SCHEMA IF EXISTS " + benchSchema + " CASCADE").Error; err != nil {
+		return err
+	}
+	if err := db.Exec("CREATE SCHEMA " + benchSchema).Error; err != nil {
+		return err
+	}
+	if err := db.Migrator().CreateTable(&models.Try{}); err != nil {
+		return err
+	}
+
+	return db.Exec(`
+		INSERT INTO tries (puzzle_id, puzzle_index, puzzle_lvl, step, start_time, end_time, attempts, score, competition_id, user_id)
+		SELECT 'puzzle-' || p, p, (ARRAY['EASY', 'MEDIUM', 'HARD'])[1 + p % 3], s, t.started,
+			CASE WHEN random() < 0.8 THEN t.started + random() * INTERVAL '1 hour' END,
+			1 + FLOOR(random() * 5)::int, ROUND((random() * 100)::numeric, 2),
+			MD5('competition-' || c)::uuid, MD5('user-' || ((c * 7 + u) % ?))::uuid
+		FROM generate_series(1, ?) c, generate_series(1, ?) u, generate_series(0, ? - 1) p, generate_series(1, 2) s,
+			LATERAL (SELECT TIMESTAMP '2025-01-01' + c * INTERVAL '7 days' + random() * INTERVAL '3 hours' AS started) t
+	`, pool, competitions, users, puzzles).Error
+}
+
+// dropIndexes removes every index of the bench tries table except its primary key
+func dropIndexes(db *gorm.DB) error {
+	var names []string
+	if err := db.Raw(`
+		SELECT indexname FROM pg_indexes
+		WHERE schemaname = ? AND tablename = 'tries' AND indexname <> 'tries_pkey'
+	`, benchSchema).Scan(&names).Error; err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := db.Exec("DROP INDEX " + benchSchema + "." + name).Error; err != nil {
+			return err
+		}
+	}
+	return db.Exec("ANALYZE tries").Error
+}
+
+// createIndexes creates the indexes declared on models.Try and the unique constraints of the API
+func createIndexes(db *gorm.DB) error {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(&models.Try{}); err != nil {
+		return err
+	}
+
+	for _, index := range stmt.Schema.ParseIndexes() {
+		if err := db.Migrator().CreateIndex(&models.Try{}, index.Name); err != nil {
+			return err
+		}
+	}
+
+	if err := database.CreateTryConstraints(db); err != nil {
+		return err
+	}
+	return db.Exec("ANALYZE tries").Error
+}
+
+// measure returns the median duration of a query over several runs, rows included
+func measure(db *gorm.DB, query benchQuery, samples []sample, runs int) (time.Duration, error) {
+	durations := make([]time.Duration, 0, runs)
+	for i := 0; i < runs; i++ {
+		args := query.args(samples[i%len(samples)])
+
+		start := time.Now()
+		rows, err := db.Raw(query.sql, args...).Rows()
+		if err != nil {
+			return 0, err
+		}
+		for rows.Next() {
+		}
+		rows.Close()
+		durations = append(durations, time.Since(start))
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	return durations[len(durations)/2], nil
+}
+
+// measureAll measures every query
+func measureAll(db *gorm.DB, samples []sample, runs int) ([]time.Duration, error) {
+	results := make([]time.Duration, 0, len(queries))
+	for _, query := range queries {
+		duration, err := measure(db, query, samples, runs)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", query.name, err)
+		}
+		results = append(results, duration)
+	}
+	return results, nil
+}
+
+func main() {
+	competitions := flag.Int("competitions", 20, "number of competitions")
+	users := flag.Int("users", 300, "number of participants per competition")
+	pool := flag.Int("pool", 1000, "number of distinct users shared by the competitions")
+	puzzles := flag.Int("puzzles", 10, "number of puzzles per competition, each with two steps")
+	runs := flag.Int("runs", 50, "number of runs per query")
+	keep := flag.Bool("keep", false, "keep the bench schema after the run")
+	flag.Parse()
+
+	if *users > *pool {
+		log.Fatal("the user pool must be at least as large as the number of participants per competition")
+	}
+
+	config.LoadConfig()
+
+	db, err := gorm.Open(postgres.Open(database.DSN()+" search_path="+benchSchema), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+		Logger:                                   logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		log.Fatal("failed to connect database: ", err)
+	}
+
+	log.Printf("Seeding %d tries", *competitions**users**puzzles*2)
+	if err := seed(db, *competitions, *users, *pool, *puzzles); err != nil {
+		log.Fatal("failed to seed: ", err)
+	}
+	if !*keep {
+		defer db.Exec("DROP SCHEMA IF EXISTS " + benchSchema + " CASCADE")
+	}
+
+	var samples []sample
+	if err := db.Raw(`SELECT competition_id, user_id FROM tries ORDER BY random() LIMIT 100`).Scan(&samples).Error; err != nil || len(samples) == 0 {
+		log.Fatal("failed to sample tries: ", err)
+	}
+
+	if err := dropIndexes(db); err != nil {
+		log.Fatal("failed to drop indexes: ", err)
+	}
+	before, err := measureAll(db, samples, *runs)
+	if err != nil {
+		log.Fatal("failed to measure: ", err)
+	}
+
+	if err := createIndexes(db); err != nil {
+		log.Fatal("failed to create indexes: ", err)
+	}
+	after, err := measureAll(db, samples, *runs)
+	if err != nil {
+		log.Fatal("failed to measure: ", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "QUERY\tWITHOUT INDEXES\tWITH INDEXES\tSPEEDUP")
+	for i, query := range queries {
+		fmt.Fprintf(w, "%s\t%s\t%s\tx%.1f\n", query.name, before[i], after[i], float64(before[i])/float64(after[i]))
+	}
+	w.Flush()
+}
@@ -24,6 +24,13 @@ var (
     RedisDB          int
     JWTSecret        string
     JWTExpiration    int
+    TrashRetentionDays int
+    AppURL           string
+    SMTPHost         string
+    SMTPPort         string
+    SMTPUsername     string
+    SMTPPassword     string
+    SMTPFrom         string
 )
 
 func LoadConfig() {
@@ -44,6 +51,13 @@ func LoadConfig() {
     RedisDB = getEnvAsInt("CACHE_DB", 0)
     JWTSecret = getEnv("JWT_SECRET", "your_secret_key")
     JWTExpiration = getEnvAsInt("JWT_EXPIRATION", 86400)
+    TrashRetentionDays = getEnvAsInt("TRASH_RETENTION_DAYS", 30)
+    AppURL = getEnv("APP_URL", "http://localhost:5173")
+    SMTPHost = getEnv("SMTP_HOST", "")
+    SMTPPort = getEnv("SMTP_PORT", "587")
+    SMTPUsername = getEnv("SMTP_USERNAME", "")
+    SMTPPassword = getEnv("SMTP_PASSWORD", "")
+    SMTPFrom = getEnv("SMTP_FROM", "no-reply@algohive.dev")
 
     // Only log a warning if .env file couldn't be loaded
     if err != nil {
@@ -22,31 +22,67 @@ var DB *gorm.DB
 var AdminRole = "Owner"
 var DefaultPassword = "admin"
 
-// InitDB initializes the database connection and migrates the models and populates the database with default values if needed
-func InitDB() {
-    dsn := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=disable TimeZone=Europe/Paris", config.PostgresHost, config.PostgresPort, config.PostgresUser, config.PostgresDB, config.PostgresPassword)
-    
+// DSN returns the connection string of the configured Postgres database
+func DSN() string {
+    return fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=disable TimeZone=Europe/Paris", config.PostgresHost, config.PostgresPort, config.PostgresUser, config.PostgresDB, config.PostgresPassword)
+}
+
+// schemaModels lists the models backing the database schema, in creation order
+var schemaModels = []interface{}{
+    &models.User{},
+    &models.Role{},
+    // &models.Input{},
+    &models.Catalog{},
+    &models.Scope{},
+    &models.Group{},
+    &models.Competition{},
+    &models.CompetitionPuzzle{},
+    &models.Team{},
+    &models.Try{},
+    &models.PracticeSession{},
+    &models.CompetitionTemplate{},
+    &models.CompetitionTemplatePuzzle{},
+    &models.CompetitionTimeOverride{},
+    &models.Submission{},
+    &models.CheatFlag{},
+    &models.TrashedItem{},
+    &models.AuditLog{},
+    &models.GroupJoinCode{},
+    &models.RegistrationSettings{},
+    &models.RegistrationDomain{},
+    &models.RegistrationInvite{},
+    &models.EmailVerification{},
+}
+
+// Connect opens the database connection without touching the schema
+func Connect() {
     var err error
-    DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+    DB, err = gorm.Open(postgres.Open(DSN()), &gorm.Config{})
     if err != nil {
         log.Fatal("failed to connect database: ", err)
     }
+    if err := setupJoinTables(DB); err != nil {
+        log.Fatal("failed to set up the join tables: ", err)
+    }
+}
 
-    err = DB.AutoMigrate(
-        &models.User{},
-        &models.Role{},
-        // &models.Input{},
-        &models.Catalog{},
-        &models.Scope{},
-        &models.Group{},
-        &models.Competition{},
-        &models.Try{},
-    )
+// setupJoinTables registers the join tables carrying their own columns
+func setupJoinTables(db *gorm.DB) error {
+    if err := db.SetupJoinTable(&models.Role{}, "Scopes", &models.RoleScope{}); err != nil {
+        return err
+    }
+    return db.SetupJoinTable(&models.Scope{}, "Roles", &models.RoleScope{})
+}
 
-    Populate()
-    if err != nil {
-        log.Fatal("failed to migrate database: ", err)
+// InitDB initializes the database connection, checks that the schema is migrated and populates the database with default values if needed
+func InitDB() {
+    Connect()
+
+    if err := CheckMigrated(); err != nil {
+        log.Fatal(err)
     }
+
+    Populate()
 }
 
 // Populate populates the database with default values if needed
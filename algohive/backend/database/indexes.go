@@ -0,0 +1,92 @@
+package database
+
+import (
+	"log"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// userStepIndex allows a single try per user and puzzle step
+	userStepIndex = "idx_tries_user_step"
+	// teamStepIndex allows a single try per team and puzzle step in team competitions
+	teamStepIndex = "idx_tries_team_step"
+	// legacyOpenTryIndex only forbade duplicate open tries, it is replaced by userStepIndex
+	legacyOpenTryIndex = "idx_tries_open_step"
+)
+
+// CreateTryConstraints creates the unique indexes of tries that GORM tags cannot express
+// db: the connection to create the indexes with, its search path selects the schema
+func CreateTryConstraints(db *gorm.DB) error {
+	if err := db.Exec(`
+		CREATE UNIQUE INDEX IF NOT EXISTS ` + userStepIndex + `
+		ON tries (user_id, competition_id, puzzle_index, step)
+	`).Error; err != nil {
+		return err
+	}
+
+	return db.Exec(`
+		CREATE UNIQUE INDEX IF NOT EXISTS ` + teamStepIndex + `
+		ON tries (team_id, competition_id, puzzle_index, step)
+		WHERE team_id IS NOT NULL
+	`).Error
+}
+
+// dedupeTries keeps one try per participant and puzzle step, the first finished one or else the oldest,
+// moving the submissions of the duplicates to it
+// participant: the column identifying the participant, user_id or team_id
+func dedupeTries(participant string) error {
+	ranked := `
+		WITH ranked AS (
+			SELECT id, FIRST_VALUE(id) OVER (
+				PARTITION BY ` + participant + `, competition_id, puzzle_index, step
+				ORDER BY end_time IS NULL, end_time, start_time, id
+			) AS keep_id
+			FROM tries
+			WHERE ` + participant + ` IS NOT NULL
+		)`
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(ranked + `
+			UPDATE submissions s SET try_id = r.keep_id
+			FROM ranked r
+			WHERE s.try_id = r.id AND r.id <> r.keep_id
+		`).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(ranked + `
+			DELETE FROM tries t
+			USING ranked r
+			WHERE t.id = r.id AND r.id <> r.keep_id
+		`)
+		if result.RowsAffected > 0 {
+			log.Println("Removed", result.RowsAffected, "duplicate tries by", participant)
+		}
+		return result.Error
+	})
+}
+
+// ensureTryIndexes creates the unique indexes of tries, removing the duplicates they forbid first
+func ensureTryIndexes() error {
+	migrator := DB.Migrator()
+	if migrator.HasIndex("tries", userStepIndex) && migrator.HasIndex("tries", teamStepIndex) {
+		return nil
+	}
+
+	if err := dedupeTries("user_id"); err != nil {
+		return err
+	}
+	if err := dedupeTries("team_id"); err != nil {
+		return err
+	}
+
+	if err := CreateTryConstraints(DB); err != nil {
+		return err
+	}
+
+	if migrator.HasIndex("tries", legacyOpenTryIndex) {
+		return migrator.DropIndex("tries", legacyOpenTryIndex)
+	}
+	return nil
+}
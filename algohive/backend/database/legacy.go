@@ -0,0 +1,97 @@
+package database
+
+import (
+	"api/models"
+	"api/utils/beeapi"
+	"log"
+	"time"
+)
+
+// prepareCompetitionPuzzles adds the catalog and theme columns to puzzle snapshots taken
+// when competitions were still bound to a single theme, before AutoMigrate enforces them
+func prepareCompetitionPuzzles() error {
+	migrator := DB.Migrator()
+	if !migrator.HasTable("competition_puzzles") || migrator.HasColumn("competition_puzzles", "catalog_id") {
+		return nil
+	}
+
+	if err := DB.Exec(`ALTER TABLE competition_puzzles ADD COLUMN catalog_id uuid, ADD COLUMN theme varchar(50)`).Error; err != nil {
+		return err
+	}
+
+	return DB.Exec(`
+		UPDATE competition_puzzles cp
+		SET catalog_id = c.catalog_id, theme = c.catalog_theme
+		FROM competitions c
+		WHERE c.id = cp.competition_id
+	`).Error
+}
+
+// migrateLegacyCompetitions builds the puzzle set of competitions created with a single catalog theme
+// and drops the legacy columns once every competition has been converted
+func migrateLegacyCompetitions() error {
+	migrator := DB.Migrator()
+	if !migrator.HasColumn("competitions", "catalog_theme") {
+		return nil
+	}
+
+	var legacy []struct {
+		ID           string
+		CatalogID    string
+		CatalogTheme string
+	}
+	if err := DB.Raw(`
+		SELECT c.id, c.catalog_id, c.catalog_theme
+		FROM competitions c
+		WHERE NOT EXISTS (SELECT 1 FROM competition_puzzles cp WHERE cp.competition_id = c.id)
+	`).Scan(&legacy).Error; err != nil {
+		return err
+	}
+
+	skipped := 0
+	for _, competition := range legacy {
+		var catalog models.Catalog
+		if err := DB.First(&catalog, "id = ?", competition.CatalogID).Error; err != nil {
+			log.Println("Skipping puzzle set of competition", competition.ID, ": catalog not found")
+			skipped++
+			continue
+		}
+
+		theme, err := beeapi.GetTheme(catalog.Address, competition.CatalogTheme)
+		if err != nil {
+			log.Println("Skipping puzzle set of competition", competition.ID, ":", err)
+			skipped++
+			continue
+		}
+
+		now := time.Now()
+		for i, p := range theme.Puzzles {
+			puzzle := models.CompetitionPuzzle{
+				CompetitionID: competition.ID,
+				PuzzleIndex:   i,
+				CatalogID:     catalog.ID,
+				Theme:         theme.Name,
+				PuzzleID:      p.ID,
+				Weight:        1,
+				Name:          p.Name,
+				Difficulty:    p.Difficulty,
+				StatementHash: p.StatementHash(),
+				SnapshotAt:    now,
+			}
+			if err := DB.Create(&puzzle).Error; err != nil {
+				return err
+			}
+		}
+		log.Println("Puzzle set created for legacy competition", competition.ID)
+	}
+
+	// Keep the legacy columns of unconverted competitions for the next start, but stop requiring them
+	if skipped > 0 {
+		return DB.Exec(`ALTER TABLE competitions ALTER COLUMN catalog_theme DROP NOT NULL, ALTER COLUMN catalog_id DROP NOT NULL`).Error
+	}
+
+	if err := migrator.DropColumn("competitions", "catalog_theme"); err != nil {
+		return err
+	}
+	return migrator.DropColumn("competitions", "catalog_id")
+}
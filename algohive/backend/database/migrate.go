@@ -0,0 +1,328 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationsTable tracks the applied migrations
+const migrationsTable = "schema_migrations"
+
+// migrationFileName matches the migration files: <version>_<name>.<up|down>.sql
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a versioned change of the schema with the SQL to apply and to revert it
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationState is a migration with the time it was applied, nil if it is pending
+type MigrationState struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// appliedMigration is a row of the migrations table
+type appliedMigration struct {
+	Version   int       `gorm:"primary_key;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(255);not null"`
+	AppliedAt time.Time `gorm:"type:timestamp;not null"`
+}
+
+// TableName sets the table of applied migrations
+func (appliedMigration) TableName() string {
+	return migrationsTable
+}
+
+// loadMigrations reads the embedded migration files, ordered by version
+func loadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		content, err := migrationFiles.ReadFile("migrations/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// appliedMigrations returns the applied migrations by version
+func appliedMigrations() (map[int]time.Time, error) {
+	applied := make(map[int]time.Time)
+	if !DB.Migrator().HasTable(migrationsTable) {
+		return applied, nil
+	}
+
+	var rows []appliedMigration
+	if err := DB.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		applied[row.Version] = row.AppliedAt
+	}
+	return applied, nil
+}
+
+// MigrationStatus returns every known migration with the time it was applied
+func MigrationStatus() ([]MigrationState, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]MigrationState, 0, len(migrations))
+	for _, migration := range migrations {
+		state := MigrationState{Migration: migration}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			state.AppliedAt = &appliedAt
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// CheckMigrated returns an error if the schema is missing migrations, the API must not run against it
+func CheckMigrated() error {
+	states, err := MigrationStatus()
+	if err != nil {
+		return err
+	}
+
+	var pending []string
+	for _, state := range states {
+		if state.AppliedAt == nil {
+			pending = append(pending, fmt.Sprintf("%04d_%s", state.Version, state.Name))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("database schema is not migrated, pending migrations: %s (run the migrate up command)", strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+// adoptLegacySchema brings a schema created by AutoMigrate to the baseline and records the baseline as applied,
+// so existing installations switch to versioned migrations without losing data
+func adoptLegacySchema(baseline Migration) error {
+	log.Println("Existing schema without migrations found, upgrading it to the baseline")
+
+	if err := prepareCompetitionPuzzles(); err != nil {
+		return err
+	}
+	if err := DB.AutoMigrate(schemaModels...); err != nil {
+		return err
+	}
+	if err := migrateLegacyCompetitions(); err != nil {
+		return err
+	}
+	if err := ensureTryIndexes(); err != nil {
+		return err
+	}
+
+	return DB.Create(&appliedMigration{Version: baseline.Version, Name: baseline.Name, AppliedAt: time.Now()}).Error
+}
+
+// MigrateUp applies every pending migration, each one in its own transaction
+// returns: the number of applied migrations
+func MigrateUp() (int, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return 0, err
+	}
+
+	migrator := DB.Migrator()
+	legacy := !migrator.HasTable(migrationsTable) && migrator.HasTable("users")
+
+	if err := migrator.AutoMigrate(&appliedMigration{}); err != nil {
+		return 0, err
+	}
+
+	if legacy && len(migrations) > 0 {
+		if err := adoptLegacySchema(migrations[0]); err != nil {
+			return 0, err
+		}
+	}
+
+	applied, err := appliedMigrations()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		if err := DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return err
+			}
+			return tx.Create(&appliedMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+		}); err != nil {
+			return count, fmt.Errorf("migration %04d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+
+		log.Printf("Applied migration %04d_%s", migration.Version, migration.Name)
+		count++
+	}
+
+	return count, nil
+}
+
+// MigrateDown reverts the last applied migrations
+// steps: the number of migrations to revert
+// returns: the number of reverted migrations
+func MigrateDown(steps int) (int, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return 0, err
+	}
+
+	applied, err := appliedMigrations()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(migrations) - 1; i >= 0 && count < steps; i-- {
+		migration := migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		if err := DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.Down).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&appliedMigration{}, "version = ?", migration.Version).Error
+		}); err != nil {
+			return count, fmt.Errorf("reverting migration %04d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+
+		log.Printf("Reverted migration %04d_%s", migration.Version, migration.Name)
+		count++
+	}
+
+	return count, nil
+}
+
+// sqlRecorder is a GORM logger collecting the statements of a dry run
+type sqlRecorder struct {
+	logger.Interface
+	statements []string
+}
+
+// Trace records the statement instead of logging it
+func (r *sqlRecorder) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	statement, _ := fc()
+	r.statements = append(r.statements, statement+";")
+}
+
+// SchemaSQL returns the statements creating the schema of the current models, join tables included.
+// It does not connect to the database and is the starting point of new migrations
+func SchemaSQL() (string, error) {
+	recorder := &sqlRecorder{Interface: logger.Discard}
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: DSN()}), &gorm.Config{
+		DisableAutomaticPing: true,
+		DryRun:               true,
+		Logger:               recorder,
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := setupJoinTables(db); err != nil {
+		return "", err
+	}
+
+	var joinTables []func() error
+	seen := make(map[string]bool)
+	for _, model := range schemaModels {
+		if err := db.Migrator().CreateTable(model); err != nil {
+			return "", err
+		}
+
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return "", err
+		}
+		for _, rel := range stmt.Schema.Relationships.Relations {
+			if rel.JoinTable == nil || seen[rel.JoinTable.Table] {
+				continue
+			}
+			seen[rel.JoinTable.Table] = true
+
+			table, joinModel := rel.JoinTable.Table, reflect.New(rel.JoinTable.ModelType).Interface()
+			joinTables = append(joinTables, func() error {
+				return db.Table(table).Migrator().CreateTable(joinModel)
+			})
+		}
+	}
+
+	// Join tables reference both sides, so they come last
+	for _, create := range joinTables {
+		if err := create(); err != nil {
+			return "", err
+		}
+	}
+
+	return strings.Join(recorder.statements, "\n"), nil
+}
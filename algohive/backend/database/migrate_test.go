@@ -0,0 +1,47 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("loadMigrations() found no migration")
+	}
+
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			t.Errorf("migration %d_%s, want version %d: versions must follow each other from 1", migration.Version, migration.Name, i+1)
+		}
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			t.Errorf("migration %d_%s has an empty up or down file", migration.Version, migration.Name)
+		}
+	}
+}
+
+func TestMigrationFileName(t *testing.T) {
+	tests := []struct {
+		name  string
+		match bool
+	}{
+		{"0001_baseline.up.sql", true},
+		{"0012_group_join_codes.down.sql", true},
+		{"0001_baseline.sql", false},
+		{"baseline.up.sql", false},
+		{"0001_baseline.sideways.sql", false},
+		{"0001_base-line.up.sql", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := migrationFileName.MatchString(tt.name); got != tt.match {
+				t.Errorf("migrationFileName.MatchString(%q) = %v, want %v", tt.name, got, tt.match)
+			}
+		})
+	}
+}
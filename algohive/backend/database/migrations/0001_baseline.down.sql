@@ -0,0 +1,21 @@
+DROP TABLE IF EXISTS "team_members";
+DROP TABLE IF EXISTS "competition_groups";
+DROP TABLE IF EXISTS "user_groups";
+DROP TABLE IF EXISTS "scope_catalogs";
+DROP TABLE IF EXISTS "role_scopes";
+DROP TABLE IF EXISTS "user_roles";
+DROP TABLE IF EXISTS "cheat_flags";
+DROP TABLE IF EXISTS "submissions";
+DROP TABLE IF EXISTS "competition_time_overrides";
+DROP TABLE IF EXISTS "competition_template_puzzles";
+DROP TABLE IF EXISTS "competition_templates";
+DROP TABLE IF EXISTS "practice_sessions";
+DROP TABLE IF EXISTS "tries";
+DROP TABLE IF EXISTS "teams";
+DROP TABLE IF EXISTS "competition_puzzles";
+DROP TABLE IF EXISTS "competitions";
+DROP TABLE IF EXISTS "groups";
+DROP TABLE IF EXISTS "scopes";
+DROP TABLE IF EXISTS "catalogs";
+DROP TABLE IF EXISTS "roles";
+DROP TABLE IF EXISTS "users";
@@ -0,0 +1,38 @@
+-- Baseline schema generated from the models (database.SchemaSQL), with the constraints GORM tags cannot express
+
+CREATE TABLE "users" ("id" uuid DEFAULT gen_random_uuid(),"firstname" varchar(50) NOT NULL,"lastname" varchar(50) NOT NULL,"email" varchar(255) NOT NULL,"password" varchar(255) NOT NULL,"last_connected" timestamp,"blocked" boolean NOT NULL DEFAULT false,PRIMARY KEY ("id"),CONSTRAINT "uni_users_email" UNIQUE ("email"));
+CREATE TABLE "roles" ("id" uuid DEFAULT gen_random_uuid(),"name" varchar(50) NOT NULL,"permissions" bigint NOT NULL DEFAULT 0,PRIMARY KEY ("id"),CONSTRAINT "uni_roles_name" UNIQUE ("name"));
+CREATE TABLE "catalogs" ("id" uuid DEFAULT gen_random_uuid(),"address" varchar(255) NOT NULL,"name" varchar(100) NOT NULL,"description" varchar(255) NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "uni_catalogs_name" UNIQUE ("name"));
+CREATE TABLE "scopes" ("id" uuid DEFAULT gen_random_uuid(),"name" varchar(50) NOT NULL,"description" varchar(255),PRIMARY KEY ("id"),CONSTRAINT "uni_scopes_name" UNIQUE ("name"));
+CREATE TABLE "groups" ("id" uuid DEFAULT gen_random_uuid(),"name" varchar(50) NOT NULL,"description" varchar(255),"scope_id" uuid NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_scopes_groups" FOREIGN KEY ("scope_id") REFERENCES "scopes"("id"));
+CREATE TABLE "competitions" ("id" uuid DEFAULT gen_random_uuid(),"title" varchar(100) NOT NULL,"description" text NOT NULL,"finished" boolean NOT NULL,"show" boolean NOT NULL,"team_mode" boolean NOT NULL DEFAULT false,"starts_at" timestamp,"ends_at" timestamp,"duration" integer,PRIMARY KEY ("id"),CONSTRAINT "uni_competitions_title" UNIQUE ("title"));
+CREATE TABLE "competition_puzzles" ("id" uuid DEFAULT gen_random_uuid(),"competition_id" uuid NOT NULL,"puzzle_index" integer NOT NULL,"catalog_id" uuid NOT NULL,"theme" varchar(50) NOT NULL,"puzzle_id" varchar(50) NOT NULL,"weight" numeric(10,2) NOT NULL DEFAULT 1,"time_limit" integer,"name" varchar(100) NOT NULL,"difficulty" varchar(50) NOT NULL,"statement_hash" varchar(64) NOT NULL,"snapshot_at" timestamp NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_competition_puzzles_catalog" FOREIGN KEY ("catalog_id") REFERENCES "catalogs"("id"),CONSTRAINT "fk_competitions_puzzles" FOREIGN KEY ("competition_id") REFERENCES "competitions"("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_competition_puzzle_index" ON "competition_puzzles" ("competition_id","puzzle_index");
+CREATE TABLE "teams" ("id" uuid DEFAULT gen_random_uuid(),"name" varchar(100) NOT NULL,"competition_id" uuid NOT NULL,"group_id" uuid,PRIMARY KEY ("id"),CONSTRAINT "fk_teams_group" FOREIGN KEY ("group_id") REFERENCES "groups"("id"),CONSTRAINT "fk_competitions_teams" FOREIGN KEY ("competition_id") REFERENCES "competitions"("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_team_competition_name" ON "teams" ("name","competition_id");
+CREATE TABLE "tries" ("id" uuid DEFAULT gen_random_uuid(),"puzzle_id" varchar(50) NOT NULL,"puzzle_index" integer NOT NULL,"puzzle_lvl" varchar(50) NOT NULL,"step" integer NOT NULL,"start_time" timestamp NOT NULL,"end_time" timestamp,"attempts" integer NOT NULL,"score" numeric(15,2) NOT NULL,"competition_id" uuid NOT NULL,"user_id" uuid NOT NULL,"team_id" uuid,PRIMARY KEY ("id"),CONSTRAINT "fk_tries_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),CONSTRAINT "fk_tries_team" FOREIGN KEY ("team_id") REFERENCES "teams"("id"),CONSTRAINT "fk_competitions_tries" FOREIGN KEY ("competition_id") REFERENCES "competitions"("id"));
+CREATE INDEX IF NOT EXISTS "idx_tries_user_end" ON "tries" ("user_id","end_time");
+CREATE INDEX IF NOT EXISTS "idx_tries_competition_team" ON "tries" ("competition_id","team_id");
+CREATE INDEX IF NOT EXISTS "idx_tries_competition_user" ON "tries" ("competition_id","user_id");
+CREATE TABLE "practice_sessions" ("id" uuid DEFAULT gen_random_uuid(),"user_id" uuid NOT NULL,"catalog_id" uuid NOT NULL,"theme" varchar(50) NOT NULL,"puzzle_id" varchar(50) NOT NULL,"puzzle_name" varchar(100) NOT NULL,"difficulty" varchar(50) NOT NULL,"start_time" timestamp NOT NULL,"first_solved_at" timestamp,"second_solved_at" timestamp,"attempts" integer NOT NULL DEFAULT 0,PRIMARY KEY ("id"),CONSTRAINT "fk_practice_sessions_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),CONSTRAINT "fk_practice_sessions_catalog" FOREIGN KEY ("catalog_id") REFERENCES "catalogs"("id"));
+CREATE INDEX IF NOT EXISTS "idx_practice_sessions_user_id" ON "practice_sessions" ("user_id");
+CREATE TABLE "competition_templates" ("id" uuid DEFAULT gen_random_uuid(),"name" varchar(100) NOT NULL,"title" varchar(100) NOT NULL,"description" text NOT NULL,"team_mode" boolean NOT NULL DEFAULT false,"duration" integer,PRIMARY KEY ("id"),CONSTRAINT "uni_competition_templates_name" UNIQUE ("name"));
+CREATE TABLE "competition_template_puzzles" ("id" uuid DEFAULT gen_random_uuid(),"template_id" uuid NOT NULL,"puzzle_index" integer NOT NULL,"catalog_id" uuid NOT NULL,"theme" varchar(50) NOT NULL,"puzzle_id" varchar(50) NOT NULL,"weight" numeric(10,2) NOT NULL DEFAULT 1,"time_limit" integer,PRIMARY KEY ("id"),CONSTRAINT "fk_competition_templates_puzzles" FOREIGN KEY ("template_id") REFERENCES "competition_templates"("id"),CONSTRAINT "fk_competition_template_puzzles_catalog" FOREIGN KEY ("catalog_id") REFERENCES "catalogs"("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_template_puzzle_index" ON "competition_template_puzzles" ("template_id","puzzle_index");
+CREATE TABLE "competition_time_overrides" ("id" uuid DEFAULT gen_random_uuid(),"competition_id" uuid NOT NULL,"user_id" uuid,"group_id" uuid,"starts_at" timestamp,"ends_at" timestamp,"duration" integer,PRIMARY KEY ("id"),CONSTRAINT "fk_competition_time_overrides_competition" FOREIGN KEY ("competition_id") REFERENCES "competitions"("id"),CONSTRAINT "fk_competition_time_overrides_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),CONSTRAINT "fk_competition_time_overrides_group" FOREIGN KEY ("group_id") REFERENCES "groups"("id"));
+CREATE INDEX IF NOT EXISTS "idx_competition_time_overrides_competition_id" ON "competition_time_overrides" ("competition_id");
+CREATE TABLE "submissions" ("id" uuid DEFAULT gen_random_uuid(),"try_id" uuid NOT NULL,"competition_id" uuid NOT NULL,"user_id" uuid NOT NULL,"team_id" uuid,"puzzle_index" integer NOT NULL,"step" integer NOT NULL,"answer" text NOT NULL,"correct" boolean NOT NULL,"ip" varchar(45),"user_agent" text,"submitted_at" timestamp NOT NULL,"latency_ms" bigint NOT NULL DEFAULT 0,PRIMARY KEY ("id"),CONSTRAINT "fk_submissions_try" FOREIGN KEY ("try_id") REFERENCES "tries"("id"),CONSTRAINT "fk_submissions_competition" FOREIGN KEY ("competition_id") REFERENCES "competitions"("id"),CONSTRAINT "fk_submissions_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+CREATE INDEX IF NOT EXISTS "idx_submissions_competition_id" ON "submissions" ("competition_id");
+CREATE INDEX IF NOT EXISTS "idx_submissions_try_id" ON "submissions" ("try_id");
+CREATE TABLE "cheat_flags" ("id" uuid DEFAULT gen_random_uuid(),"competition_id" uuid NOT NULL,"user_id" uuid NOT NULL,"other_user_id" uuid NOT NULL,"kind" varchar(50) NOT NULL,"puzzle_index" integer,"step" integer,"evidence" text NOT NULL,"detected_at" timestamp NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_cheat_flags_competition" FOREIGN KEY ("competition_id") REFERENCES "competitions"("id"));
+CREATE INDEX IF NOT EXISTS "idx_cheat_flags_competition_id" ON "cheat_flags" ("competition_id");
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_tries_user_step" ON "tries" ("user_id","competition_id","puzzle_index","step");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_tries_team_step" ON "tries" ("team_id","competition_id","puzzle_index","step") WHERE "team_id" IS NOT NULL;
+
+CREATE TABLE "user_roles" ("user_id" uuid,"role_id" uuid,PRIMARY KEY ("user_id","role_id"),CONSTRAINT "fk_user_roles_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),CONSTRAINT "fk_user_roles_role" FOREIGN KEY ("role_id") REFERENCES "roles"("id"));
+CREATE TABLE "role_scopes" ("role_id" uuid,"scope_id" uuid,PRIMARY KEY ("role_id","scope_id"),CONSTRAINT "fk_role_scopes_role" FOREIGN KEY ("role_id") REFERENCES "roles"("id"),CONSTRAINT "fk_role_scopes_scope" FOREIGN KEY ("scope_id") REFERENCES "scopes"("id"));
+CREATE TABLE "scope_catalogs" ("catalog_id" uuid,"scope_id" uuid,PRIMARY KEY ("catalog_id","scope_id"),CONSTRAINT "fk_scope_catalogs_catalog" FOREIGN KEY ("catalog_id") REFERENCES "catalogs"("id"),CONSTRAINT "fk_scope_catalogs_scope" FOREIGN KEY ("scope_id") REFERENCES "scopes"("id"));
+CREATE TABLE "user_groups" ("user_id" uuid,"group_id" uuid,PRIMARY KEY ("user_id","group_id"),CONSTRAINT "fk_user_groups_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),CONSTRAINT "fk_user_groups_group" FOREIGN KEY ("group_id") REFERENCES "groups"("id"));
+CREATE TABLE "competition_groups" ("group_id" uuid,"competition_id" uuid,PRIMARY KEY ("group_id","competition_id"),CONSTRAINT "fk_competition_groups_group" FOREIGN KEY ("group_id") REFERENCES "groups"("id"),CONSTRAINT "fk_competition_groups_competition" FOREIGN KEY ("competition_id") REFERENCES "competitions"("id"));
+CREATE TABLE "team_members" ("team_id" uuid,"user_id" uuid,PRIMARY KEY ("team_id","user_id"),CONSTRAINT "fk_team_members_team" FOREIGN KEY ("team_id") REFERENCES "teams"("id"),CONSTRAINT "fk_team_members_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
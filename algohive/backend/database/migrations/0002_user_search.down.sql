@@ -0,0 +1,3 @@
+-- The pg_trgm extension is kept, other objects of the database may rely on it
+DROP INDEX IF EXISTS "idx_groups_name_trgm";
+DROP INDEX IF EXISTS "idx_users_search";
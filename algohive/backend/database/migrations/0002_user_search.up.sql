@@ -0,0 +1,5 @@
+-- Trigram indexes backing the user search, the indexed expressions must match the queries of handlers/users/user_search.go
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS "idx_users_search" ON "users" USING gin ((firstname || ' ' || lastname || ' ' || email) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS "idx_groups_name_trgm" ON "groups" USING gin ("name" gin_trgm_ops);
@@ -0,0 +1,16 @@
+-- Trashed rows become live again without the associations recorded in the trash, purge the trash first to drop them
+DROP TABLE IF EXISTS "trashed_items";
+
+DROP INDEX IF EXISTS "idx_scopes_name";
+ALTER TABLE "scopes" ADD CONSTRAINT "uni_scopes_name" UNIQUE ("name");
+
+DROP INDEX IF EXISTS "idx_competitions_title";
+ALTER TABLE "competitions" ADD CONSTRAINT "uni_competitions_title" UNIQUE ("title");
+
+DROP INDEX IF EXISTS "idx_users_email";
+ALTER TABLE "users" ADD CONSTRAINT "uni_users_email" UNIQUE ("email");
+
+ALTER TABLE "scopes" DROP COLUMN IF EXISTS "deleted_at";
+ALTER TABLE "competitions" DROP COLUMN IF EXISTS "deleted_at";
+ALTER TABLE "groups" DROP COLUMN IF EXISTS "deleted_at";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "deleted_at";
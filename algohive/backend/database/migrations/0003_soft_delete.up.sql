@@ -0,0 +1,27 @@
+-- Users, groups, competitions and scopes are soft deleted and kept in the trash until restored or purged
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "deleted_at" timestamp;
+ALTER TABLE "groups" ADD COLUMN IF NOT EXISTS "deleted_at" timestamp;
+ALTER TABLE "competitions" ADD COLUMN IF NOT EXISTS "deleted_at" timestamp;
+ALTER TABLE "scopes" ADD COLUMN IF NOT EXISTS "deleted_at" timestamp;
+
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_groups_deleted_at" ON "groups" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_competitions_deleted_at" ON "competitions" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_scopes_deleted_at" ON "scopes" ("deleted_at");
+
+-- Unique values only apply to live rows, a trashed row must not block its replacement
+ALTER TABLE "users" DROP CONSTRAINT IF EXISTS "uni_users_email";
+ALTER TABLE "users" DROP CONSTRAINT IF EXISTS "users_email_key";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email") WHERE deleted_at IS NULL;
+
+ALTER TABLE "competitions" DROP CONSTRAINT IF EXISTS "uni_competitions_title";
+ALTER TABLE "competitions" DROP CONSTRAINT IF EXISTS "competitions_title_key";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_competitions_title" ON "competitions" ("title") WHERE deleted_at IS NULL;
+
+ALTER TABLE "scopes" DROP CONSTRAINT IF EXISTS "uni_scopes_name";
+ALTER TABLE "scopes" DROP CONSTRAINT IF EXISTS "scopes_name_key";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_scopes_name" ON "scopes" ("name") WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS "trashed_items" ("id" uuid DEFAULT gen_random_uuid(),"entity_type" varchar(20) NOT NULL,"entity_id" uuid NOT NULL,"label" varchar(255) NOT NULL,"associations" text NOT NULL,"deleted_by" uuid,"deleted_at" timestamp NOT NULL,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_trashed_entity" ON "trashed_items" ("entity_type","entity_id");
+CREATE INDEX IF NOT EXISTS "idx_trashed_items_deleted_at" ON "trashed_items" ("deleted_at");
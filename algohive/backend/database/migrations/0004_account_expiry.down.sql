@@ -0,0 +1,7 @@
+-- Accounts blocked by the expiry job stay blocked
+DROP INDEX IF EXISTS "idx_groups_expires_at";
+DROP INDEX IF EXISTS "idx_users_expires_at";
+
+ALTER TABLE "groups" DROP COLUMN IF EXISTS "expires_at";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "expired_at";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "expires_at";
@@ -0,0 +1,8 @@
+-- Accounts expire on their own date or when every group of a student has expired,
+-- expired_at records when the expiry job blocked the account so extending a group can reactivate it
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "expires_at" timestamp;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "expired_at" timestamp;
+ALTER TABLE "groups" ADD COLUMN IF NOT EXISTS "expires_at" timestamp;
+
+CREATE INDEX IF NOT EXISTS "idx_users_expires_at" ON "users" ("expires_at");
+CREATE INDEX IF NOT EXISTS "idx_groups_expires_at" ON "groups" ("expires_at");
@@ -0,0 +1,6 @@
+-- Audit trail of the sensitive operations, such as the export and the anonymization of personal data
+CREATE TABLE IF NOT EXISTS "audit_logs" ("id" uuid DEFAULT gen_random_uuid(),"action" varchar(50) NOT NULL,"actor_id" uuid,"target_id" uuid,"details" text NOT NULL,"ip" varchar(45),"created_at" timestamp NOT NULL,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_action" ON "audit_logs" ("action");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_actor_id" ON "audit_logs" ("actor_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_target_id" ON "audit_logs" ("target_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_created_at" ON "audit_logs" ("created_at");
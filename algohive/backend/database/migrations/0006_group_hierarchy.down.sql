@@ -0,0 +1,4 @@
+-- Subgroups become top-level groups
+ALTER TABLE "groups" DROP CONSTRAINT IF EXISTS "fk_groups_children";
+DROP INDEX IF EXISTS "idx_groups_parent_id";
+ALTER TABLE "groups" DROP COLUMN IF EXISTS "parent_id";
@@ -0,0 +1,10 @@
+-- Groups nest under a parent group (campus, promotion, class, lab group), see utils/hierarchy for the recursive queries
+ALTER TABLE "groups" ADD COLUMN IF NOT EXISTS "parent_id" uuid;
+CREATE INDEX IF NOT EXISTS "idx_groups_parent_id" ON "groups" ("parent_id");
+
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_groups_children') THEN
+		ALTER TABLE "groups" ADD CONSTRAINT "fk_groups_children" FOREIGN KEY ("parent_id") REFERENCES "groups"("id");
+	END IF;
+END $$;
@@ -0,0 +1,4 @@
+-- Codes letting users enroll themselves in a group, one per group
+CREATE TABLE IF NOT EXISTS "group_join_codes" ("id" uuid DEFAULT gen_random_uuid(),"group_id" uuid NOT NULL,"code" varchar(16) NOT NULL,"expires_at" timestamp,"max_uses" integer,"uses" integer NOT NULL DEFAULT 0,"created_by" uuid,"created_at" timestamp NOT NULL,PRIMARY KEY ("id"),CONSTRAINT "fk_group_join_codes_group" FOREIGN KEY ("group_id") REFERENCES "groups"("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_group_join_codes_code" ON "group_join_codes" ("code");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_group_join_codes_group_id" ON "group_join_codes" ("group_id");
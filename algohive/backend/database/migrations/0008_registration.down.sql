@@ -0,0 +1,6 @@
+-- Unverified accounts can log in again
+DROP TABLE IF EXISTS "email_verifications";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "email_unverified";
+DROP TABLE IF EXISTS "registration_invites";
+DROP TABLE IF EXISTS "registration_domains";
+DROP TABLE IF EXISTS "registration_settings";
@@ -0,0 +1,18 @@
+-- Self-registration: runtime settings, allowed email domains, invitations and email verification.
+-- Self-registration stays open as before, with the email address to verify before logging in
+CREATE TABLE IF NOT EXISTS "registration_settings" ("id" bigint NOT NULL,"mode" varchar(20) NOT NULL,"require_email_verification" boolean NOT NULL,"updated_by" uuid,"updated_at" timestamp NOT NULL,PRIMARY KEY ("id"));
+INSERT INTO "registration_settings" ("id","mode","require_email_verification","updated_at") VALUES (1,'open',true,now()) ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS "registration_domains" ("id" uuid DEFAULT gen_random_uuid(),"domain" varchar(255) NOT NULL,"group_id" uuid,"scope_id" uuid,"created_at" timestamp NOT NULL,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_registration_domains_domain" ON "registration_domains" ("domain");
+
+CREATE TABLE IF NOT EXISTS "registration_invites" ("id" uuid DEFAULT gen_random_uuid(),"email" varchar(255) NOT NULL,"token_hash" varchar(64) NOT NULL,"group_id" uuid,"expires_at" timestamp NOT NULL,"used_at" timestamp,"created_by" uuid,"created_at" timestamp NOT NULL,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_registration_invites_token_hash" ON "registration_invites" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_registration_invites_email" ON "registration_invites" ("email");
+
+-- Existing accounts are considered verified
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "email_unverified" boolean NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS "email_verifications" ("id" uuid DEFAULT gen_random_uuid(),"user_id" uuid NOT NULL,"token_hash" varchar(64) NOT NULL,"expires_at" timestamp NOT NULL,"used_at" timestamp,"created_at" timestamp NOT NULL,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_email_verifications_token_hash" ON "email_verifications" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_email_verifications_user_id" ON "email_verifications" ("user_id");
@@ -0,0 +1,2 @@
+-- Roles grant their own permissions in every scope again
+ALTER TABLE "role_scopes" DROP COLUMN IF EXISTS "permissions";
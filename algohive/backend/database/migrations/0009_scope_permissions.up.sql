@@ -0,0 +1,3 @@
+-- Each role to scope binding can carry the permission mask the role grants in the scope.
+-- Existing bindings keep granting the permissions of their role
+ALTER TABLE "role_scopes" ADD COLUMN IF NOT EXISTS "permissions" integer;
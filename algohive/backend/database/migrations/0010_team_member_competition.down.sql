@@ -0,0 +1,5 @@
+-- Only the API checks that a user belongs to one team per competition again
+DROP INDEX IF EXISTS "idx_team_members_competition_user";
+DROP TRIGGER IF EXISTS "trg_team_members_competition" ON "team_members";
+DROP FUNCTION IF EXISTS "team_members_set_competition"();
+ALTER TABLE "team_members" DROP COLUMN IF EXISTS "competition_id";
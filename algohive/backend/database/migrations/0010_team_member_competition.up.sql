@@ -0,0 +1,22 @@
+-- A user belongs to at most one team per competition. team_members carries the competition of the team,
+-- filled by a trigger so every insert path (association appends, trash restore) sets it, and a unique index enforces the rule.
+ALTER TABLE "team_members" ADD COLUMN IF NOT EXISTS "competition_id" uuid;
+UPDATE "team_members" tm SET "competition_id" = t."competition_id" FROM "teams" t WHERE t."id" = tm."team_id" AND tm."competition_id" IS NULL;
+
+-- Memberships breaking the rule before it was enforced keep only the team with the smallest ID
+DELETE FROM "team_members" tm USING "team_members" other
+WHERE other."competition_id" = tm."competition_id" AND other."user_id" = tm."user_id" AND other."team_id" < tm."team_id";
+
+ALTER TABLE "team_members" ALTER COLUMN "competition_id" SET NOT NULL;
+
+CREATE OR REPLACE FUNCTION "team_members_set_competition"() RETURNS trigger AS $$
+BEGIN
+	NEW."competition_id" := (SELECT "competition_id" FROM "teams" WHERE "id" = NEW."team_id");
+	RETURN NEW;
+END $$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS "trg_team_members_competition" ON "team_members";
+CREATE TRIGGER "trg_team_members_competition" BEFORE INSERT OR UPDATE OF "team_id" ON "team_members"
+FOR EACH ROW EXECUTE FUNCTION "team_members_set_competition"();
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_team_members_competition_user" ON "team_members" ("competition_id","user_id");
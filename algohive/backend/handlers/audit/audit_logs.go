@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/utils/pagination"
+	"api/utils/permissions"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// auditSortKeys are the sort keys accepted by the audit trail
+var auditSortKeys = map[string]string{
+	"created_at": "created_at",
+	"action":     "action",
+}
+
+// GetAuditLogs retrieves the audit trail
+// @Summary Get the audit trail
+// @Description Get a page of the audited operations, only accessible to owners
+// @Tags Audit
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param sort query string false "Sort key (created_at, action), prefixed by - to sort descending"
+// @Param action query string false "Only the operations of this action"
+// @Param actor_id query string false "Only the operations done by this user"
+// @Param target_id query string false "Only the operations on this entity"
+// @Success 200 {object} pagination.Page{items=[]models.AuditLog}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /audit [get]
+// @Security Bearer
+func GetAuditLogs(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	if !permissions.RolesHavePermission(user.Roles, permissions.OWNER) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionView)
+		return
+	}
+
+	params, err := pagination.Parse(c, auditSortKeys, "-created_at")
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidListParams)
+		return
+	}
+
+	query := database.DB.Model(&models.AuditLog{})
+	if action := c.Query("action"); action != "" {
+		query = query.Where("action = ?", action)
+	}
+	if actorID := c.Query("actor_id"); actorID != "" {
+		query = query.Where("actor_id = ?", actorID)
+	}
+	if targetID := c.Query("target_id"); targetID != "" {
+		query = query.Where("target_id = ?", targetID)
+	}
+
+	var logs []models.AuditLog
+	page, err := pagination.Find(query, params, &logs)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedGetAudit)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
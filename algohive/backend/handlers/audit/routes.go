@@ -0,0 +1,17 @@
+package audit
+
+import (
+	"api/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes registers all routes related to the audit trail
+// r: the RouterGroup to which the routes are added
+func RegisterRoutes(r *gin.RouterGroup) {
+	audit := r.Group("/audit")
+	audit.Use(middleware.AuthMiddleware())
+	{
+		audit.GET("/", GetAuditLogs)
+	}
+}
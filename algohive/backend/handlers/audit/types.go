@@ -0,0 +1,17 @@
+package audit
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// Error messages constants
+const (
+	ErrNoPermissionView  = "User does not have permission to view the audit trail"
+	ErrInvalidListParams = "Invalid pagination, sort or filter parameters"
+	ErrFailedGetAudit    = "Failed to get the audit trail"
+)
+
+// respondWithError sends a JSON response with an error message
+func respondWithError(c *gin.Context, status int, message string) {
+	c.JSON(status, gin.H{"error": message})
+}
@@ -4,6 +4,7 @@ import (
 	"api/database"
 	"api/models"
 	"api/utils"
+	"api/utils/lifecycle"
 	"api/utils/permissions"
 	"net/http"
 	"time"
@@ -21,6 +22,7 @@ import (
 // @Success 200 {object} AuthResponse
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Router /auth/login [post]
 func Login(c *gin.Context) {
 	var loginReq LoginRequest
@@ -36,7 +38,11 @@ func Login(c *gin.Context) {
 		return
 	}
 	
-	// Check if the user is blocked
+	// Check if the user is blocked, by hand or by the expiry of the account
+	if user.Blocked && user.ExpiredAt != nil {
+		respondWithError(c, http.StatusUnauthorized, ErrAccountExpired)
+		return
+	}
 	if user.Blocked {
 		respondWithError(c, http.StatusUnauthorized, ErrAccountBlocked)
 		return
@@ -47,6 +53,18 @@ func Login(c *gin.Context) {
 		respondWithError(c, http.StatusUnauthorized, ErrInvalidCredentials)
 		return
 	}
+
+	// Check that the email address of a self-registered account is verified
+	if user.EmailUnverified {
+		respondWithError(c, http.StatusForbidden, ErrEmailNotVerified)
+		return
+	}
+
+	// Check if the account or every group of the user has expired
+	if lifecycle.IsExpired(user.ID) {
+		respondWithError(c, http.StatusUnauthorized, ErrAccountExpired)
+		return
+	}
 	
 	// Generate a JWT token
 	token, err := utils.GenerateJWT(user.ID, user.Email)
@@ -5,20 +5,30 @@ import (
 	"api/models"
 	"api/utils"
 	"api/utils/permissions"
+	"api/utils/registration"
+	"errors"
+	"log"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // RegisterUser handles registration of a new user
 // @Summary User Register
-// @Description Register a new user
+// @Description Register a new user, as allowed by the registration mode: disabled, open, restricted to the email domains
+// @Description with a rule, or restricted to invited email addresses. The domain rule or the invitation can place the user in a group.
+// @Description When email verification is required, the account cannot log in before the link sent by email is opened
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param register body RegisterRequest true "Registration Details"
 // @Success 201 {object} AuthResponse
+// @Success 202 {object} RegistrationPendingResponse
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 409 {object} map[string]string
 // @Router /auth/register [post]
 func RegisterUser(c *gin.Context) {
@@ -29,6 +39,41 @@ func RegisterUser(c *gin.Context) {
 		return
 	}
 	
+	settings, err := registration.LoadSettings()
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedRegistration)
+		return
+	}
+	
+	// Check that the registration mode lets this email address register
+	var rule *models.RegistrationDomain
+	var invite *models.RegistrationInvite
+	switch settings.Mode {
+	case models.RegistrationModeDisabled:
+		respondWithError(c, http.StatusForbidden, ErrRegistrationClosed)
+		return
+	case models.RegistrationModeDomain:
+		if rule, err = registration.FindDomainRule(registerReq.Email); err != nil {
+			respondWithError(c, http.StatusInternalServerError, ErrFailedRegistration)
+			return
+		}
+		if rule == nil {
+			respondWithError(c, http.StatusForbidden, ErrDomainNotAllowed)
+			return
+		}
+	case models.RegistrationModeInvite:
+		if invite = findInvite(registerReq.InviteToken, registerReq.Email); invite == nil {
+			respondWithError(c, http.StatusForbidden, ErrInviteRequired)
+			return
+		}
+	default:
+		// In the open mode, a domain rule still places the users of its domain in a group
+		if rule, err = registration.FindDomainRule(registerReq.Email); err != nil {
+			respondWithError(c, http.StatusInternalServerError, ErrFailedRegistration)
+			return
+		}
+	}
+	
 	// Check if the email already exists
 	var existingUser models.User
 	if err := database.DB.Where("email = ?", registerReq.Email).First(&existingUser).Error; err == nil {
@@ -45,18 +90,85 @@ func RegisterUser(c *gin.Context) {
 	
 	// Create a new user
 	user := models.User{
-		Email:     registerReq.Email,
-		Password:  hashedPassword,
-		Firstname: registerReq.Firstname,
-		Lastname:  registerReq.Lastname,
-		Blocked:   false,
+		Email:           registerReq.Email,
+		Password:        hashedPassword,
+		Firstname:       registerReq.Firstname,
+		Lastname:        registerReq.Lastname,
+		Blocked:         false,
+		EmailUnverified: settings.RequireEmailVerification,
 	}
 	
-	if err := database.DB.Create(&user).Error; err != nil {
+	var verificationToken string
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		
+		var groupIDs []string
+		if rule != nil {
+			groupID, err := registration.DomainGroup(tx, rule)
+			if err != nil {
+				return err
+			}
+			if groupID != "" {
+				groupIDs = append(groupIDs, groupID)
+			}
+		}
+		if invite != nil {
+			// The invitation is consumed only if it is still unused, two registrations cannot share it
+			now := time.Now()
+			result := tx.Model(&models.RegistrationInvite{}).
+				Where("id = ? AND used_at IS NULL", invite.ID).
+				Update("used_at", now)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return errInviteUsed
+			}
+			if invite.GroupID != nil {
+				groupIDs = append(groupIDs, *invite.GroupID)
+			}
+		}
+		for _, groupID := range groupIDs {
+			if err := tx.Exec(
+				"INSERT INTO user_groups (user_id, group_id) SELECT ?, id FROM groups WHERE id = ? AND deleted_at IS NULL ON CONFLICT DO NOTHING",
+				user.ID, groupID,
+			).Error; err != nil {
+				return err
+			}
+		}
+		
+		if settings.RequireEmailVerification {
+			token, err := registration.CreateVerification(tx, user.ID)
+			if err != nil {
+				return err
+			}
+			verificationToken = token
+		}
+		return nil
+	}); err != nil {
+		if errors.Is(err, errInviteUsed) {
+			respondWithError(c, http.StatusForbidden, ErrInviteRequired)
+			return
+		}
 		respondWithError(c, http.StatusInternalServerError, ErrUserCreateFailed)
 		return
 	}
 	
+	// The account logs in once its email address is verified
+	if settings.RequireEmailVerification {
+		if err := registration.SendVerificationEmail(user, verificationToken); err != nil {
+			log.Printf("Failed to send the verification email to %s: %v", user.Email, err)
+		}
+		c.JSON(http.StatusAccepted, RegistrationPendingResponse{
+			UserID:  user.ID,
+			Email:   user.Email,
+			Message: "Check your inbox to verify your email address",
+		})
+		return
+	}
+	
 	// Generate a JWT token
 	token, err := utils.GenerateJWT(user.ID, user.Email)
 	if err != nil {
@@ -79,3 +191,24 @@ func RegisterUser(c *gin.Context) {
 		Groups:        utils.ConvertGroups(user.Groups),
 	})
 }
+
+// errInviteUsed aborts a registration whose invitation was used by a concurrent registration
+var errInviteUsed = errors.New("invitation already used")
+
+// findInvite looks up the pending invitation of a token, it must be sent to the email address registering
+// returns: the invitation, nil when the token is not valid for this address
+func findInvite(token, email string) *models.RegistrationInvite {
+	if token == "" {
+		return nil
+	}
+	var invite models.RegistrationInvite
+	if err := database.DB.
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", registration.HashToken(token), time.Now()).
+		First(&invite).Error; err != nil {
+		return nil
+	}
+	if !strings.EqualFold(invite.Email, email) {
+		return nil
+	}
+	return &invite
+}
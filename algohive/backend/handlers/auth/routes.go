@@ -13,6 +13,9 @@ func RegisterRoutes(r *gin.RouterGroup) {
 	{
 		auth.POST("/login", Login)
 		auth.POST("/register", RegisterUser)
+		auth.GET("/registration", GetRegistrationInfo)
+		auth.POST("/verify-email", VerifyEmail)
+		auth.POST("/verify-email/resend", ResendVerification)
 		auth.POST("/logout", middleware.AuthMiddleware(), Logout)
 		auth.GET("/check", middleware.AuthMiddleware(), CheckAuth)
 	}
@@ -12,6 +12,7 @@ import (
 const (
 	ErrInvalidCredentials  = "Invalid credentials"
 	ErrAccountBlocked      = "Your account has been blocked"
+	ErrAccountExpired      = "Your account has expired"
 	ErrEmailInUse          = "Email already in use"
 	ErrHashPasswordFailed  = "Failed to hash password"
 	ErrUserCreateFailed    = "Failed to create user"
@@ -22,6 +23,14 @@ const (
 	ErrUserNotFound        = "User not found"
 	ErrLogoutFailed        = "Failed to logout"
 	ErrLogoutSuccess       = "Successfully logged out"
+	ErrRegistrationClosed  = "Registration is disabled"
+	ErrDomainNotAllowed    = "Registration is not open to this email domain"
+	ErrInviteRequired      = "Registration requires a valid invitation for this email address"
+	ErrFailedRegistration  = "Failed to read the registration settings"
+	ErrEmailNotVerified    = "Your email address is not verified, check your inbox"
+	ErrInvalidVerification = "Invalid, expired or already used verification link"
+	ErrFailedVerification  = "Failed to verify the email address"
+	ErrFailedSendEmail     = "Failed to send the verification email"
 )
 
 // LoginRequest model for login endpoints
@@ -32,10 +41,34 @@ type LoginRequest struct {
 
 // RegisterRequest model for registration
 type RegisterRequest struct {
-	Email     string `json:"email" binding:"required,email"`
-	Password  string `json:"password" binding:"required,min=8"`
-	Firstname string `json:"firstname" binding:"required"`
-	Lastname  string `json:"lastname" binding:"required"`
+	Email       string `json:"email" binding:"required,email"`
+	Password    string `json:"password" binding:"required,min=8"`
+	Firstname   string `json:"firstname" binding:"required"`
+	Lastname    string `json:"lastname" binding:"required"`
+	InviteToken string `json:"invite_token"`
+}
+
+// RegistrationPendingResponse is returned by a registration waiting for the email address to be verified
+type RegistrationPendingResponse struct {
+	UserID  string `json:"user_id"`
+	Email   string `json:"email"`
+	Message string `json:"message"`
+}
+
+// RegistrationInfo tells the web application how users can register
+type RegistrationInfo struct {
+	Mode                     string `json:"mode"`
+	RequireEmailVerification bool   `json:"require_email_verification"`
+}
+
+// VerifyEmailRequest carries the token of a verification link
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ResendVerificationRequest asks for a new verification link
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
 }
 
 // AuthResponse model for authentication responses
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"api/database"
+	"api/models"
+	"api/utils/registration"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// errVerificationInvalid aborts the verification of an unknown, expired or used token
+var errVerificationInvalid = errors.New("invalid verification token")
+
+// GetRegistrationInfo tells how users can register
+// @Summary Registration mode
+// @Description Get the registration mode, disabled, open, domain or invite, and whether the email address must be verified
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} RegistrationInfo
+// @Router /auth/registration [get]
+func GetRegistrationInfo(c *gin.Context) {
+	settings, err := registration.LoadSettings()
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedRegistration)
+		return
+	}
+
+	c.JSON(http.StatusOK, RegistrationInfo{
+		Mode:                     settings.Mode,
+		RequireEmailVerification: settings.RequireEmailVerification,
+	})
+}
+
+// VerifyEmail confirms the email address of a registered user
+// @Summary Verify an email address
+// @Description Confirm the email address of a registered user with the token of the link sent by email, a token can be used once
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body VerifyEmailRequest true "Verification token"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /auth/verify-email [post]
+func VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var verification models.EmailVerification
+		if err := tx.Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", registration.HashToken(req.Token), time.Now()).
+			First(&verification).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errVerificationInvalid
+			}
+			return err
+		}
+
+		// Consume the token only if no concurrent request did
+		result := tx.Model(&models.EmailVerification{}).
+			Where("id = ? AND used_at IS NULL", verification.ID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errVerificationInvalid
+		}
+
+		return tx.Model(&models.User{}).Where("id = ?", verification.UserID).Update("email_unverified", false).Error
+	})
+	if errors.Is(err, errVerificationInvalid) {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidVerification)
+		return
+	}
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedVerification)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email address verified, you can log in"})
+}
+
+// ResendVerification sends a new verification link to an unverified account
+// @Summary Resend the verification email
+// @Description Send a new verification link to the email address of an unverified account, the previous links stop working.
+// @Description The response is the same whether the address is registered or not
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body ResendVerificationRequest true "Email address"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /auth/verify-email/resend [post]
+func ResendVerification(c *gin.Context) {
+	var req ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var user models.User
+	if err := database.DB.Where("email = ? AND email_unverified = ?", req.Email, true).First(&user).Error; err == nil {
+		var token string
+		if err := database.DB.Transaction(func(tx *gorm.DB) error {
+			var err error
+			token, err = registration.CreateVerification(tx, user.ID)
+			return err
+		}); err != nil {
+			respondWithError(c, http.StatusInternalServerError, ErrFailedSendEmail)
+			return
+		}
+		if err := registration.SendVerificationEmail(user, token); err != nil {
+			log.Printf("Failed to send the verification email to %s: %v", user.Email, err)
+		}
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the address belongs to an unverified account, a new link was sent"})
+}
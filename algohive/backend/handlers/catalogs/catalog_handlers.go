@@ -37,6 +37,7 @@ func GetAllCatalogs(c *gin.Context) {
 			SELECT DISTINCT c.*
 			FROM public.catalogs c
 			JOIN public.scope_catalogs sae ON sae.catalog_id = c.id
+			JOIN public.scopes s ON s.id = sae.scope_id AND s.deleted_at IS NULL
 			JOIN public.role_scopes rs ON rs.scope_id = sae.scope_id
 			JOIN public.user_roles ur ON ur.role_id = rs.role_id
 			WHERE ur.user_id = ?`, user.ID).Scan(&catalogs).Error; err != nil {
@@ -0,0 +1,194 @@
+package competitions
+
+import (
+	"api/database"
+	"api/models"
+	"api/utils/hierarchy"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// analyticsCacheTTL is how long the analytics of a finished competition are kept in Redis
+const analyticsCacheTTL = 24 * time.Hour
+
+// participantColumn identifies a participant: their team in team competitions, themselves otherwise
+const participantColumn = "COALESCE(t.team_id, t.user_id)"
+
+// analyticsCacheKey returns the Redis key of the analytics of a competition
+func analyticsCacheKey(competitionID string) string {
+	return "competition_analytics:" + competitionID
+}
+
+// computeCompetitionAnalytics aggregates the tries of a competition
+func computeCompetitionAnalytics(competition *models.Competition) (*CompetitionAnalyticsResponse, error) {
+	analytics := CompetitionAnalyticsResponse{
+		CompetitionID: competition.ID,
+		Puzzles:       []PuzzleStepAnalytics{},
+		Timeline:      []SolvesPerMinute{},
+		Groups:        []GroupAnalytics{},
+		GeneratedAt:   time.Now(),
+	}
+
+	var participants int64
+	if err := database.DB.Raw(`SELECT COUNT(DISTINCT `+participantColumn+`) FROM tries t WHERE t.competition_id = ?`,
+		competition.ID).Scan(&participants).Error; err != nil {
+		return nil, err
+	}
+	analytics.Participants = int(participants)
+
+	// Per puzzle step solve data, wrong attempts come from the submissions when the answer was checked by the server
+	if err := database.DB.Raw(`
+		SELECT t.puzzle_index, t.step,
+			COUNT(*) AS tries,
+			COUNT(DISTINCT `+participantColumn+`) FILTER (WHERE t.end_time IS NOT NULL) AS solve_count,
+			COALESCE(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM t.end_time - t.start_time))
+				FILTER (WHERE t.end_time IS NOT NULL), 0) AS median_solve_time,
+			COALESCE(PERCENTILE_CONT(0.9) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM t.end_time - t.start_time))
+				FILTER (WHERE t.end_time IS NOT NULL), 0) AS p90_solve_time,
+			COALESCE(AVG(COALESCE(w.wrong, GREATEST(t.attempts - CASE WHEN t.end_time IS NULL THEN 0 ELSE 1 END, 0))), 0)
+				AS average_wrong_attempts
+		FROM tries t
+		LEFT JOIN (
+			SELECT try_id, COUNT(*) FILTER (WHERE NOT correct) AS wrong
+			FROM submissions
+			WHERE competition_id = ?
+			GROUP BY try_id
+		) w ON w.try_id = t.id
+		WHERE t.competition_id = ?
+		GROUP BY t.puzzle_index, t.step
+		ORDER BY t.puzzle_index, t.step
+	`, competition.ID, competition.ID).Scan(&analytics.Puzzles).Error; err != nil {
+		return nil, err
+	}
+
+	var firstSolvers []struct {
+		PuzzleIndex int
+		Step        int
+		UserID      string
+		Name        string
+		EndTime     time.Time
+	}
+	if err := database.DB.Raw(`
+		SELECT DISTINCT ON (t.puzzle_index, t.step) t.puzzle_index, t.step, t.user_id,
+			u.firstname || ' ' || u.lastname AS name, t.end_time
+		FROM tries t
+		JOIN users u ON u.id = t.user_id AND u.deleted_at IS NULL
+		WHERE t.competition_id = ? AND t.end_time IS NOT NULL
+		ORDER BY t.puzzle_index, t.step, t.end_time
+	`, competition.ID).Scan(&firstSolvers).Error; err != nil {
+		return nil, err
+	}
+
+	puzzles, err := getCompetitionPuzzles(competition)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range analytics.Puzzles {
+		entry := &analytics.Puzzles[i]
+		if puzzle, ok := findCompetitionPuzzle(puzzles, entry.PuzzleIndex); ok {
+			entry.PuzzleID = puzzle.PuzzleID
+			entry.Name = puzzle.Name
+		}
+		if participants > 0 {
+			entry.SolveRate = float64(entry.SolveCount) / float64(participants) * 100
+		}
+		for _, solver := range firstSolvers {
+			if solver.PuzzleIndex == entry.PuzzleIndex && solver.Step == entry.Step {
+				userID, name, solvedAt := solver.UserID, solver.Name, solver.EndTime
+				entry.FirstSolverID = &userID
+				entry.FirstSolverName = &name
+				entry.FirstSolvedAt = &solvedAt
+				break
+			}
+		}
+	}
+
+	if err := database.DB.Raw(`
+		SELECT DATE_TRUNC('minute', t.end_time) AS minute, COUNT(*) AS solves
+		FROM tries t
+		WHERE t.competition_id = ? AND t.end_time IS NOT NULL
+		GROUP BY 1
+		ORDER BY 1
+	`, competition.ID).Scan(&analytics.Timeline).Error; err != nil {
+		return nil, err
+	}
+
+	// Students of subgroups count under the groups of the competition, as they take part through them
+	competitionGroups := database.DB.Table("competition_groups").Select("group_id").Where("competition_id = ?", competition.ID)
+	if err := database.DB.Raw(`
+		SELECT g.id AS group_id, g.name AS group_name,
+			COUNT(DISTINCT m.user_id) AS members,
+			COUNT(DISTINCT t.user_id) AS participants,
+			COUNT(DISTINCT t.user_id) FILTER (WHERE t.end_time IS NOT NULL) AS active_users,
+			COUNT(t.end_time) AS solves,
+			COALESCE(AVG(t.score) FILTER (WHERE t.end_time IS NOT NULL), 0) AS average_score
+		FROM groups g
+		JOIN (?) m ON m.group_id = g.id
+		LEFT JOIN tries t ON t.user_id = m.user_id AND t.competition_id = ?
+		GROUP BY g.id, g.name
+		ORDER BY g.name
+	`, hierarchy.SubtreeMembers(competitionGroups), competition.ID).Scan(&analytics.Groups).Error; err != nil {
+		return nil, err
+	}
+
+	return &analytics, nil
+}
+
+// GetCompetitionAnalytics retrieves the detailed analytics of a competition
+// @Summary Get the analytics of a competition
+// @Description Get per puzzle step solve rates and solve times, the solves per minute and a per group breakdown.
+// @Description The analytics of finished competitions are cached
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Success 200 {object} CompetitionAnalyticsResponse
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /competitions/{id}/analytics [get]
+// @Security Bearer
+func GetCompetitionAnalytics(c *gin.Context) {
+	if !requireCompetitionManager(c, ErrNoPermissionViewTries) {
+		return
+	}
+
+	var competition models.Competition
+	if err := database.DB.First(&competition, "id = ?", c.Param("id")).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrCompetitionNotFound)
+		return
+	}
+
+	ctx := context.Background()
+	cacheKey := analyticsCacheKey(competition.ID)
+
+	// Tries of a finished competition do not change anymore
+	if competition.Finished {
+		if cached, err := database.REDIS.Get(ctx, cacheKey).Result(); err == nil {
+			var analytics CompetitionAnalyticsResponse
+			if err := json.Unmarshal([]byte(cached), &analytics); err == nil {
+				c.JSON(http.StatusOK, analytics)
+				return
+			}
+		}
+	}
+
+	analytics, err := computeCompetitionAnalytics(&competition)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedFetchAnalytics)
+		return
+	}
+
+	if competition.Finished {
+		// Continue even if caching fails
+		if analyticsJSON, err := json.Marshal(analytics); err == nil {
+			database.REDIS.Set(ctx, cacheKey, analyticsJSON, analyticsCacheTTL)
+		}
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
@@ -0,0 +1,211 @@
+package competitions
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/utils/beeapi"
+	"api/utils/permissions"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errStepAlreadySolved aborts a submission for a try that a concurrent submission finished
+var errStepAlreadySolved = errors.New("step already solved")
+
+// SubmitTryAnswer checks an answer for a try and logs the submission
+// @Summary Submit the answer of a try
+// @Description Check the answer of an ongoing try against the input of the user (or their team).
+// @Description Every submission is logged with its IP and user agent, a correct answer finishes the try with the weight of the puzzle as score
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Param try_id path string true "Try ID"
+// @Param answer body SubmitAnswerRequest true "Answer"
+// @Success 200 {object} SubmitAnswerResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /competitions/{id}/tries/{try_id}/answer [post]
+// @Security Bearer
+func SubmitTryAnswer(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	competitionID := c.Param("id")
+
+	try, err := findParticipantTry(competitionID, c.Param("try_id"), user.ID)
+	if err != nil {
+		respondWithError(c, http.StatusNotFound, ErrTryNotFound)
+		return
+	}
+
+	if try.EndTime != nil {
+		respondWithError(c, http.StatusConflict, ErrStepAlreadySolved)
+		return
+	}
+
+	var req SubmitAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidRequest)
+		return
+	}
+
+	var competition models.Competition
+	if err := database.DB.First(&competition, "id = ?", competitionID).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrCompetitionNotFound)
+		return
+	}
+
+	// Scores are final once staff finished the competition, the anti-cheat analysis and the cached analytics rely on it
+	if competition.Finished {
+		respondWithError(c, http.StatusBadRequest, ErrCompetitionFinished)
+		return
+	}
+
+	now := time.Now()
+	if err := checkCompetitionSchedule(&competition, user.ID, try.TeamID, now); err != nil {
+		respondScheduleError(c, err)
+		return
+	}
+
+	var puzzle models.CompetitionPuzzle
+	if err := database.DB.Where("competition_id = ? AND puzzle_index = ?", competitionID, try.PuzzleIndex).
+		First(&puzzle).Error; err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrPuzzleNotInSnapshot)
+		return
+	}
+
+	if puzzle.TimeLimit != nil {
+		startTime, err := parseTryTime(try.StartTime)
+		if err == nil && now.Sub(startTime) > time.Duration(*puzzle.TimeLimit)*time.Second {
+			respondWithError(c, http.StatusBadRequest, ErrTimeLimitExceeded)
+			return
+		}
+	}
+
+	// The try belongs to the team in team competitions, so its input is the one of the team
+	ownerID := try.UserID
+	if try.TeamID != nil {
+		ownerID = *try.TeamID
+	}
+
+	generation, err := getPuzzleGeneration(c.Request.Context(), &competition, &puzzle, ownerID)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedGenerateInput)
+		return
+	}
+
+	correct := beeapi.CheckAnswer(req.Answer, generation.Solution(try.Step))
+
+	var latency time.Duration
+	if startTime, err := parseTryTime(try.StartTime); err == nil {
+		latency = now.Sub(startTime)
+	}
+
+	submission := models.Submission{
+		TryID:         try.ID,
+		CompetitionID: competitionID,
+		UserID:        user.ID,
+		TeamID:        try.TeamID,
+		PuzzleIndex:   try.PuzzleIndex,
+		Step:          try.Step,
+		Answer:        req.Answer,
+		Correct:       correct,
+		IP:            c.ClientIP(),
+		UserAgent:     c.Request.UserAgent(),
+		SubmittedAt:   now,
+		LatencyMs:     latency.Milliseconds(),
+	}
+
+	// The try is locked so concurrent submissions count every attempt and finish it only once
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(try, "id = ?", try.ID).Error; err != nil {
+			return err
+		}
+		if try.EndTime != nil {
+			return errStepAlreadySolved
+		}
+
+		if err := tx.Create(&submission).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{"attempts": gorm.Expr("attempts + 1")}
+		if correct {
+			updates["end_time"] = now.Format(time.RFC3339)
+			updates["score"] = puzzle.Weight
+		}
+		if err := tx.Model(try).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.First(try, "id = ?", try.ID).Error
+	})
+	if errors.Is(err, errStepAlreadySolved) {
+		respondWithError(c, http.StatusConflict, ErrStepAlreadySolved)
+		return
+	}
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedSaveSubmission)
+		return
+	}
+
+	c.JSON(http.StatusOK, SubmitAnswerResponse{
+		Correct: correct,
+		Try:     *try,
+	})
+}
+
+// GetTrySubmissions retrieves the answers submitted for a try
+// @Summary Get the submissions of a try
+// @Description Get every answer submitted for a try, oldest first. Available to the owner of the try and to staff
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Param try_id path string true "Try ID"
+// @Success 200 {array} models.Submission
+// @Failure 404 {object} map[string]string
+// @Router /competitions/{id}/tries/{try_id}/submissions [get]
+// @Security Bearer
+func GetTrySubmissions(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	competitionID := c.Param("id")
+	tryID := c.Param("try_id")
+
+	var try models.Try
+	if canManageCompetition(user, competitionID, permissions.COMPETITIONS) {
+		if err := database.DB.Where("id = ? AND competition_id = ?", tryID, competitionID).First(&try).Error; err != nil {
+			respondWithError(c, http.StatusNotFound, ErrTryNotFound)
+			return
+		}
+	} else {
+		participantTry, err := findParticipantTry(competitionID, tryID, user.ID)
+		if err != nil {
+			respondWithError(c, http.StatusNotFound, ErrTryNotFound)
+			return
+		}
+		try = *participantTry
+	}
+
+	var submissions []models.Submission
+	if err := database.DB.Where("try_id = ?", try.ID).Order("submitted_at").Find(&submissions).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedFetchSubmissions)
+		return
+	}
+
+	c.JSON(http.StatusOK, submissions)
+}
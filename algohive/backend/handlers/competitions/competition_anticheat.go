@@ -0,0 +1,338 @@
+package competitions
+
+import (
+	"api/database"
+	"api/models"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// timingWindow is the maximum delay between two correct submissions to count as simultaneous
+	timingWindow = 30 * time.Second
+	// timingMinMatches is the number of simultaneous solves from which a pair of users is flagged
+	timingMinMatches = 3
+	// analysisLockTTL bounds how long an analysis can hold the lock of a competition
+	analysisLockTTL = 10 * time.Minute
+)
+
+// sameInputHolders excludes pairs of submissions made by members of the same team, who share their input
+const sameInputHolders = `(a.team_id IS NULL OR a.team_id IS DISTINCT FROM b.team_id)`
+
+// findAnswerCollisions flags pairs of users that submitted the same wrong answer for a puzzle step
+func findAnswerCollisions(competitionID string, now time.Time) ([]models.CheatFlag, error) {
+	var rows []struct {
+		UserID      string
+		OtherUserID string
+		PuzzleIndex int
+		Step        int
+		Answer      string
+		FirstAt     time.Time
+		OtherAt     time.Time
+	}
+	if err := database.DB.Raw(`
+		SELECT a.user_id, b.user_id AS other_user_id, a.puzzle_index, a.step, a.answer,
+			MIN(a.submitted_at) AS first_at, MIN(b.submitted_at) AS other_at
+		FROM submissions a
+		JOIN submissions b ON b.competition_id = a.competition_id AND b.puzzle_index = a.puzzle_index
+			AND b.step = a.step AND b.answer = a.answer AND a.user_id < b.user_id
+		WHERE a.competition_id = ? AND NOT a.correct AND NOT b.correct AND `+sameInputHolders+`
+		GROUP BY a.user_id, b.user_id, a.puzzle_index, a.step, a.answer
+	`, competitionID).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	flags := make([]models.CheatFlag, 0, len(rows))
+	for _, row := range rows {
+		puzzleIndex, step := row.PuzzleIndex, row.Step
+		flags = append(flags, models.CheatFlag{
+			CompetitionID: competitionID,
+			UserID:        row.UserID,
+			OtherUserID:   row.OtherUserID,
+			Kind:          models.CheatWrongAnswerCollision,
+			PuzzleIndex:   &puzzleIndex,
+			Step:          &step,
+			Evidence: fmt.Sprintf("Same wrong answer %q submitted at %s and %s",
+				row.Answer, row.FirstAt.Format(time.RFC3339), row.OtherAt.Format(time.RFC3339)),
+			DetectedAt: now,
+		})
+	}
+	return flags, nil
+}
+
+// findCopiedSolutions flags users that submitted, as a wrong answer, the correct answer of another user
+func findCopiedSolutions(competitionID string, now time.Time) ([]models.CheatFlag, error) {
+	var rows []struct {
+		UserID      string
+		OtherUserID string
+		PuzzleIndex int
+		Step        int
+		SubmittedAt time.Time
+		SolvedAt    time.Time
+	}
+	if err := database.DB.Raw(`
+		SELECT a.user_id, b.user_id AS other_user_id, a.puzzle_index, a.step,
+			MIN(a.submitted_at) AS submitted_at, MIN(b.submitted_at) AS solved_at
+		FROM submissions a
+		JOIN submissions b ON b.competition_id = a.competition_id AND b.puzzle_index = a.puzzle_index
+			AND b.step = a.step AND b.answer = a.answer AND a.user_id <> b.user_id
+		WHERE a.competition_id = ? AND NOT a.correct AND b.correct AND `+sameInputHolders+`
+		GROUP BY a.user_id, b.user_id, a.puzzle_index, a.step
+	`, competitionID).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	flags := make([]models.CheatFlag, 0, len(rows))
+	for _, row := range rows {
+		puzzleIndex, step := row.PuzzleIndex, row.Step
+		flags = append(flags, models.CheatFlag{
+			CompetitionID: competitionID,
+			UserID:        row.UserID,
+			OtherUserID:   row.OtherUserID,
+			Kind:          models.CheatCopiedSolution,
+			PuzzleIndex:   &puzzleIndex,
+			Step:          &step,
+			Evidence: fmt.Sprintf("Submitted at %s the correct answer of the other user, who solved it at %s",
+				row.SubmittedAt.Format(time.RFC3339), row.SolvedAt.Format(time.RFC3339)),
+			DetectedAt: now,
+		})
+	}
+	return flags, nil
+}
+
+// findTimingPatterns flags pairs of users that repeatedly solved the same puzzle steps within a few seconds
+func findTimingPatterns(competitionID string, now time.Time) ([]models.CheatFlag, error) {
+	var rows []struct {
+		UserID      string
+		OtherUserID string
+		Matches     int
+		MaxGap      float64
+	}
+	if err := database.DB.Raw(`
+		SELECT a.user_id, b.user_id AS other_user_id, COUNT(*) AS matches,
+			MAX(ABS(EXTRACT(EPOCH FROM a.submitted_at - b.submitted_at))) AS max_gap
+		FROM submissions a
+		JOIN submissions b ON b.competition_id = a.competition_id AND b.puzzle_index = a.puzzle_index
+			AND b.step = a.step AND a.user_id < b.user_id
+		WHERE a.competition_id = ? AND a.correct AND b.correct AND `+sameInputHolders+`
+			AND ABS(EXTRACT(EPOCH FROM a.submitted_at - b.submitted_at)) <= ?
+		GROUP BY a.user_id, b.user_id
+		HAVING COUNT(*) >= ?
+	`, competitionID, timingWindow.Seconds(), timingMinMatches).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	flags := make([]models.CheatFlag, 0, len(rows))
+	for _, row := range rows {
+		flags = append(flags, models.CheatFlag{
+			CompetitionID: competitionID,
+			UserID:        row.UserID,
+			OtherUserID:   row.OtherUserID,
+			Kind:          models.CheatTimingPattern,
+			Evidence: fmt.Sprintf("%d puzzle steps solved at most %.0f seconds apart",
+				row.Matches, row.MaxGap),
+			DetectedAt: now,
+		})
+	}
+	return flags, nil
+}
+
+// findSharedIPs flags pairs of users that submitted answers from the same IP address
+func findSharedIPs(competitionID string, now time.Time) ([]models.CheatFlag, error) {
+	var rows []struct {
+		UserID      string
+		OtherUserID string
+		IP          string
+	}
+	if err := database.DB.Raw(`
+		WITH sources AS (
+			SELECT DISTINCT user_id, team_id, ip FROM submissions WHERE competition_id = ? AND ip <> ''
+		)
+		SELECT a.user_id, b.user_id AS other_user_id, a.ip
+		FROM sources a
+		JOIN sources b ON b.ip = a.ip AND a.user_id < b.user_id
+		WHERE `+sameInputHolders+`
+	`, competitionID).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	flags := make([]models.CheatFlag, 0, len(rows))
+	for _, row := range rows {
+		flags = append(flags, models.CheatFlag{
+			CompetitionID: competitionID,
+			UserID:        row.UserID,
+			OtherUserID:   row.OtherUserID,
+			Kind:          models.CheatSharedIP,
+			Evidence:      fmt.Sprintf("Answers submitted from the same IP address %s", row.IP),
+			DetectedAt:    now,
+		})
+	}
+	return flags, nil
+}
+
+// analyzeCompetition runs every detector on the submissions of a competition and replaces its flags
+func analyzeCompetition(competitionID string) error {
+	now := time.Now()
+	detectors := []func(string, time.Time) ([]models.CheatFlag, error){
+		findAnswerCollisions,
+		findCopiedSolutions,
+		findTimingPatterns,
+		findSharedIPs,
+	}
+
+	var flags []models.CheatFlag
+	for _, detect := range detectors {
+		found, err := detect(competitionID, now)
+		if err != nil {
+			return err
+		}
+		flags = append(flags, found...)
+	}
+
+	tx := database.DB.Begin()
+
+	if err := tx.Where("competition_id = ?", competitionID).Delete(&models.CheatFlag{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(flags) > 0 {
+		if err := tx.Create(&flags).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// startCompetitionAnalysis runs the anti-cheat analysis of a competition in the background,
+// unless one is already running for it
+// returns: false if an analysis is already running
+func startCompetitionAnalysis(competitionID string) bool {
+	ctx := context.Background()
+	lockKey := "anticheat_running:" + competitionID
+
+	acquired, err := database.REDIS.SetNX(ctx, lockKey, time.Now().Format(time.RFC3339), analysisLockTTL).Result()
+	if err != nil || !acquired {
+		return false
+	}
+
+	go func() {
+		defer database.REDIS.Del(ctx, lockKey)
+		if err := analyzeCompetition(competitionID); err != nil {
+			log.Printf("Anti-cheat analysis of competition %s failed: %v", competitionID, err)
+			return
+		}
+		log.Printf("Anti-cheat analysis of competition %s done", competitionID)
+	}()
+
+	return true
+}
+
+// AnalyzeCompetitionSubmissions starts the anti-cheat analysis of a competition
+// @Summary Analyze the submissions of a competition
+// @Description Start a background analysis flagging answer collisions, copied solutions, simultaneous solves and shared IPs between users
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Success 202 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /competitions/{id}/anticheat/analyze [post]
+// @Security Bearer
+func AnalyzeCompetitionSubmissions(c *gin.Context) {
+	if !requireCompetitionManager(c, ErrNoPermissionViewTries) {
+		return
+	}
+
+	var competition models.Competition
+	if err := database.DB.First(&competition, "id = ?", c.Param("id")).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrCompetitionNotFound)
+		return
+	}
+
+	if !startCompetitionAnalysis(competition.ID) {
+		respondWithError(c, http.StatusConflict, ErrAnalysisRunning)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Analysis started"})
+}
+
+// GetCompetitionCheatReport retrieves the pairs of users flagged by the anti-cheat analysis
+// @Summary Get the anti-cheat report of a competition
+// @Description Get the pairs of users flagged by the last analysis of a competition, with the evidence, most flagged pairs first
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Success 200 {array} CheatReportEntry
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /competitions/{id}/anticheat/report [get]
+// @Security Bearer
+func GetCompetitionCheatReport(c *gin.Context) {
+	if !requireCompetitionManager(c, ErrNoPermissionViewTries) {
+		return
+	}
+
+	var competition models.Competition
+	if err := database.DB.First(&competition, "id = ?", c.Param("id")).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrCompetitionNotFound)
+		return
+	}
+
+	var flags []models.CheatFlag
+	if err := database.DB.Where("competition_id = ?", competition.ID).
+		Order("detected_at, kind").Find(&flags).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedFetchReport)
+		return
+	}
+
+	// Group the flags by unordered pair of users
+	entries := []*CheatReportEntry{}
+	byPair := make(map[string]*CheatReportEntry)
+	userIDs := []string{}
+	for _, flag := range flags {
+		first, second := flag.UserID, flag.OtherUserID
+		if second < first {
+			first, second = second, first
+		}
+
+		entry, ok := byPair[first+":"+second]
+		if !ok {
+			entry = &CheatReportEntry{UserID: first, OtherUserID: second}
+			byPair[first+":"+second] = entry
+			entries = append(entries, entry)
+			userIDs = append(userIDs, first, second)
+		}
+		entry.Flags = append(entry.Flags, flag)
+	}
+
+	var users []models.User
+	database.DB.Where("id IN ?", userIDs).Find(&users)
+	names := make(map[string]string, len(users))
+	for _, u := range users {
+		names[u.ID] = u.Firstname + " " + u.Lastname
+	}
+
+	for _, entry := range entries {
+		entry.UserName = names[entry.UserID]
+		entry.OtherUserName = names[entry.OtherUserID]
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return len(entries[i].Flags) > len(entries[j].Flags)
+	})
+
+	c.JSON(http.StatusOK, entries)
+}
@@ -12,7 +12,7 @@ import (
 
 // AddGroupToCompetition adds a group to a competition
 // @Summary Add a group to a competition
-// @Description Allow users in a group to access a competition
+// @Description Allow users in a group and in all its subgroups to access a competition
 // @Tags Competitions
 // @Accept json
 // @Produce json
@@ -30,7 +30,7 @@ func AddGroupToCompetition(c *gin.Context) {
 		return
 	}
 
-	if !hasCompetitionPermission(user, permissions.COMPETITIONS) {
+	if !canManageCompetition(user, c.Param("id"), permissions.COMPETITIONS) {
 		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionManageGroups)
 		return
 	}
@@ -50,6 +50,11 @@ func AddGroupToCompetition(c *gin.Context) {
 		return
 	}
 
+	if !canGiveCompetitionToGroups(user, []string{groupID}) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionManageGroups)
+		return
+	}
+
 	// Add the group to the competition
 	if err := database.DB.Exec("INSERT INTO competition_groups (group_id, competition_id) VALUES (?, ?) ON CONFLICT DO NOTHING", 
 		groupID, competitionID).Error; err != nil {
@@ -80,7 +85,7 @@ func RemoveGroupFromCompetition(c *gin.Context) {
 		return
 	}
 
-	if !hasCompetitionPermission(user, permissions.COMPETITIONS) {
+	if !canManageCompetition(user, c.Param("id"), permissions.COMPETITIONS) {
 		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionManageGroups)
 		return
 	}
@@ -100,6 +105,11 @@ func RemoveGroupFromCompetition(c *gin.Context) {
 		return
 	}
 
+	if !canGiveCompetitionToGroups(user, []string{groupID}) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionManageGroups)
+		return
+	}
+
 	// Remove the group from the competition
 	if err := database.DB.Exec("DELETE FROM competition_groups WHERE group_id = ? AND competition_id = ?", 
 		groupID, competitionID).Error; err != nil {
@@ -108,7 +118,7 @@ func RemoveGroupFromCompetition(c *gin.Context) {
 	}
 
 	// Reload the competition with its associations
-	database.DB.Preload("Catalog").Preload("Groups").First(&competition, competition.ID)
+	database.DB.Preload("Puzzles", orderByPuzzleIndex).Preload("Groups").First(&competition, "id = ?", competition.ID)
 
 	c.JSON(http.StatusOK, competition)
 }
@@ -131,7 +141,7 @@ func GetCompetitionGroups(c *gin.Context) {
 		return
 	}
 
-	if !hasCompetitionPermission(user, permissions.COMPETITIONS) {
+	if !canManageCompetition(user, c.Param("id"), permissions.COMPETITIONS) {
 		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionManageGroups)
 		return
 	}
@@ -0,0 +1,151 @@
+package competitions
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/utils/beeapi"
+	"api/utils/permissions"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// inputCacheTTL is how long a generated competition input is kept in Redis
+const inputCacheTTL = time.Hour
+
+var errNoTeam = errors.New("user is not part of a team")
+
+// inputOwnerID returns the ID the input of a user is generated for:
+// their team in a team competition, themselves otherwise
+func inputOwnerID(competition *models.Competition, userID string) (string, error) {
+	if !competition.TeamMode {
+		return userID, nil
+	}
+
+	team := findUserTeam(competition.ID, userID)
+	if team == nil {
+		return "", errNoTeam
+	}
+	return team.ID, nil
+}
+
+// getPuzzleGeneration returns the input and solutions of a competition puzzle for an input owner
+func getPuzzleGeneration(ctx context.Context, competition *models.Competition, puzzle *models.CompetitionPuzzle, ownerID string) (*beeapi.Generation, error) {
+	cacheKey := fmt.Sprintf("competition_input:%s:%d:%s", competition.ID, puzzle.PuzzleIndex, ownerID)
+
+	if cached, err := database.REDIS.Get(ctx, cacheKey).Result(); err == nil {
+		var generation beeapi.Generation
+		if err := json.Unmarshal([]byte(cached), &generation); err == nil {
+			return &generation, nil
+		}
+	}
+
+	var catalog models.Catalog
+	if err := database.DB.First(&catalog, "id = ?", puzzle.CatalogID).Error; err != nil {
+		return nil, err
+	}
+
+	generation, err := beeapi.GeneratePuzzle(catalog.Address, puzzle.Theme, puzzle.Name, competition.ID+":"+ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Continue even if caching fails
+	if generationJSON, err := json.Marshal(generation); err == nil {
+		database.REDIS.Set(ctx, cacheKey, generationJSON, inputCacheTTL)
+	}
+
+	return generation, nil
+}
+
+// GetCompetitionPuzzleInput retrieves the input of a competition puzzle for the current user
+// @Summary Get the input of a competition puzzle
+// @Description Get the generated input of a puzzle, shared by all the members of a team in team competitions.
+// @Description Participants only get it while the competition runs, within their window and time allowed, overrides included
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Param puzzle_index path int true "Puzzle index"
+// @Success 200 {object} PuzzleInputResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /competitions/{id}/puzzles/{puzzle_index}/input [get]
+// @Security Bearer
+func GetCompetitionPuzzleInput(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	competitionID := c.Param("id")
+
+	manager := canManageCompetition(user, competitionID, permissions.COMPETITIONS)
+	if !manager && !userHasAccessToCompetition(user.ID, competitionID) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionView)
+		return
+	}
+
+	var competition models.Competition
+	if err := database.DB.First(&competition, "id = ?", competitionID).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrCompetitionNotFound)
+		return
+	}
+
+	puzzleIndex, err := strconv.Atoi(c.Param("puzzle_index"))
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidRequest)
+		return
+	}
+
+	var puzzle models.CompetitionPuzzle
+	if err := database.DB.Where("competition_id = ? AND puzzle_index = ?", competitionID, puzzleIndex).
+		First(&puzzle).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrPuzzleNotInSnapshot)
+		return
+	}
+
+	ownerID, err := inputOwnerID(&competition, user.ID)
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrNotInTeam)
+		return
+	}
+
+	// Participants get inputs under the same conditions as they start tries, managers can preview them at any time
+	if !manager {
+		if competition.Finished {
+			respondWithError(c, http.StatusBadRequest, ErrCompetitionFinished)
+			return
+		}
+
+		var teamID *string
+		if competition.TeamMode {
+			teamID = &ownerID
+		}
+		if err := checkCompetitionSchedule(&competition, user.ID, teamID, time.Now()); err != nil {
+			respondScheduleError(c, err)
+			return
+		}
+	}
+
+	generation, err := getPuzzleGeneration(c.Request.Context(), &competition, &puzzle, ownerID)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedGenerateInput)
+		return
+	}
+
+	c.JSON(http.StatusOK, PuzzleInputResponse{
+		PuzzleIndex: puzzle.PuzzleIndex,
+		PuzzleID:    puzzle.PuzzleID,
+		InputLines:  generation.InputLines,
+	})
+}
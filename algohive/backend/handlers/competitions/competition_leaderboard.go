@@ -0,0 +1,76 @@
+package competitions
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/utils/permissions"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCompetitionLeaderboard retrieves the ranking of a competition
+// @Summary Get the leaderboard of a competition
+// @Description Rank users, or teams in team competitions, by total score then by earliest last solve
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Success 200 {array} LeaderboardEntry
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /competitions/{id}/leaderboard [get]
+// @Security Bearer
+func GetCompetitionLeaderboard(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	competitionID := c.Param("id")
+
+	if !userHasAccessToCompetition(user.ID, competitionID) && !canManageCompetition(user, competitionID, permissions.COMPETITIONS) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionView)
+		return
+	}
+
+	var competition models.Competition
+	if err := database.DB.First(&competition, "id = ?", competitionID).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrCompetitionNotFound)
+		return
+	}
+
+	// Tries are aggregated per team in team competitions, per user otherwise, users in the trash are left out
+	entryColumn, nameQuery := "t.user_id", `SELECT u.firstname || ' ' || u.lastname FROM users u WHERE u.id = r.entry_id::uuid`
+	entryFilter := "t.user_id IN (SELECT id FROM users WHERE deleted_at IS NULL)"
+	if competition.TeamMode {
+		entryColumn, nameQuery = "t.team_id", `SELECT tm.name FROM teams tm WHERE tm.id = r.entry_id::uuid`
+		entryFilter = "t.team_id IS NOT NULL"
+	}
+
+	entries := []LeaderboardEntry{}
+	if err := database.DB.Raw(`
+		SELECT r.entry_id, r.score, r.solved, r.last_solve, (`+nameQuery+`) AS name
+		FROM (
+			SELECT `+entryColumn+`::text AS entry_id,
+				COALESCE(SUM(t.score) FILTER (WHERE t.end_time IS NOT NULL), 0) AS score,
+				COUNT(*) FILTER (WHERE t.end_time IS NOT NULL) AS solved,
+				MAX(t.end_time)::text AS last_solve
+			FROM tries t
+			WHERE t.competition_id = ? AND `+entryFilter+`
+			GROUP BY `+entryColumn+`
+		) r
+		ORDER BY r.score DESC, r.last_solve ASC NULLS LAST
+	`, competitionID).Scan(&entries).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedFetchLeaderboard)
+		return
+	}
+
+	for i := range entries {
+		entries[i].Rank = i + 1
+		entries[i].IsTeam = competition.TeamMode
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
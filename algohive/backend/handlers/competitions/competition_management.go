@@ -4,24 +4,155 @@ import (
 	"api/database"
 	"api/middleware"
 	"api/models"
+	"api/utils/hierarchy"
+	"api/utils/pagination"
 	"api/utils/permissions"
+	"api/utils/trash"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
-// hasCompetitionPermission checks if the user has a specific permission
+// hasCompetitionPermission checks if the user holds a permission in at least one live scope of their roles,
+// for the operations that do not target a scope yet: owners always do, the masks of the scope bindings apply to other users
+// user: the authenticated user
+// permission: the permission required
 func hasCompetitionPermission(user models.User, permission int) bool {
-	return permissions.IsStaff(user) || permissions.RolesHavePermission(user.Roles, permission)
+	if permissions.IsOwner(user) {
+		return true
+	}
+
+	var scopes int64
+	if err := database.DB.Raw("SELECT COUNT(*) FROM (?) bound", hierarchy.ScopesWith(user.ID, permission)).Scan(&scopes).Error; err != nil {
+		return false
+	}
+	return scopes > 0
+}
+
+// canManageCompetition checks if the user holds a permission over a competition: owners over every competition,
+// other users in the scope of one of the groups of the competition. A competition without groups belongs to no scope,
+// holding the permission in one of the scopes of the user is enough, see hasCompetitionPermission
+// user: the authenticated user
+// competitionID: ID of the competition
+// permission: the permission required
+func canManageCompetition(user models.User, competitionID string, permission int) bool {
+	if permissions.IsOwner(user) {
+		return true
+	}
+
+	var groups int64
+	if err := database.DB.Table("competition_groups").Where("competition_id = ?", competitionID).Count(&groups).Error; err != nil {
+		return false
+	}
+	if groups == 0 {
+		return hasCompetitionPermission(user, permission)
+	}
+
+	var managed int64
+	if err := database.DB.Table("competition_groups").
+		Where("competition_id = ? AND group_id IN (?)", competitionID, hierarchy.ManagedGroupsWith(user.ID, permission)).
+		Count(&managed).Error; err != nil {
+		return false
+	}
+	return managed > 0
+}
+
+// canGiveCompetitionToGroups checks if the user can open competitions to groups, which requires
+// the COMPETITIONS permission in the scope of each group
+// user: the authenticated user
+// groupIDs: IDs of the groups
+func canGiveCompetitionToGroups(user models.User, groupIDs []string) bool {
+	if permissions.IsOwner(user) || len(groupIDs) == 0 {
+		return true
+	}
+
+	var managed int64
+	if err := database.DB.Raw(
+		"SELECT COUNT(DISTINCT id) FROM (?) managed WHERE id IN ?",
+		hierarchy.ManagedGroupsWith(user.ID, permissions.COMPETITIONS), groupIDs,
+	).Scan(&managed).Error; err != nil {
+		return false
+	}
+
+	distinct := make(map[string]bool, len(groupIDs))
+	for _, groupID := range groupIDs {
+		distinct[groupID] = true
+	}
+	return int(managed) == len(distinct)
+}
+
+// managedCompetitions keeps the competitions the user holds the COMPETITIONS permission over, see canManageCompetition
+func managedCompetitions(user models.User, query *gorm.DB) *gorm.DB {
+	if permissions.IsOwner(user) {
+		return query
+	}
+	return query.Where(
+		"id NOT IN (SELECT competition_id FROM competition_groups) OR id IN (?)",
+		database.DB.Table("competition_groups").Select("competition_id").
+			Where("group_id IN (?)", hierarchy.ManagedGroupsWith(user.ID, permissions.COMPETITIONS)),
+	)
+}
+
+// competitionSortKeys are the sort keys accepted by the competition lists
+var competitionSortKeys = map[string]string{
+	"title":     "title",
+	"starts_at": "starts_at",
+	"ends_at":   "ends_at",
+}
+
+// preloadCompetitionAssociations loads the puzzles and groups of a page of competitions
+func preloadCompetitionAssociations(db *gorm.DB) *gorm.DB {
+	return db.Preload("Puzzles", orderByPuzzleIndex).Preload("Groups")
+}
+
+// respondCompetitionPage filters, sorts and paginates a competition query from the request parameters
+func respondCompetitionPage(c *gin.Context, query *gorm.DB) {
+	params, err := pagination.Parse(c, competitionSortKeys, "title")
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidListParams)
+		return
+	}
+
+	query = pagination.Search(query, c.Query("q"), "title", "description")
+
+	finished, err := pagination.Bool(c, "finished")
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidListParams)
+		return
+	}
+	if finished != nil {
+		query = query.Where("finished = ?", *finished)
+	}
+	if groupID := c.Query("group_id"); groupID != "" {
+		query = query.Where("id IN (?)", database.DB.Table("competition_groups").Select("competition_id").Where("group_id = ?", groupID))
+	}
+
+	var competitions []models.Competition
+	page, err := pagination.Find(query, params, &competitions, preloadCompetitionAssociations)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedFetchCompetitions)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
 }
 
 // GetAllCompetitions retrieves all competitions
 // @Summary Get all competitions
-// @Description Get all competitions, only accessible to users with the COMPETITIONS permission
+// @Description Get a page of the competitions the user manages: every competition for owners, otherwise those of the groups of the scopes where the user holds the COMPETITIONS permission and those without groups
 // @Tags Competitions
 // @Accept json
 // @Produce json
-// @Success 200 {array} models.Competition
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param sort query string false "Sort keys among title, starts_at, ends_at, prefixed by - to sort descending"
+// @Param q query string false "Text searched in the title and description"
+// @Param finished query bool false "Only finished or running competitions"
+// @Param show query bool false "Only visible or hidden competitions"
+// @Param group_id query string false "Only competitions of this group"
+// @Success 200 {object} pagination.Page{items=[]models.Competition}
+// @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Router /competitions [get]
 // @Security Bearer
@@ -36,22 +167,34 @@ func GetAllCompetitions(c *gin.Context) {
 		return
 	}
 
-	var competitions []models.Competition
-	if err := database.DB.Preload("Catalog").Preload("Groups").Find(&competitions).Error; err != nil {
-		respondWithError(c, http.StatusInternalServerError, ErrFailedFetchCompetitions)
+	query := managedCompetitions(user, database.DB.Model(&models.Competition{}))
+
+	show, err := pagination.Bool(c, "show")
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidListParams)
 		return
 	}
+	if show != nil {
+		query = query.Where("show = ?", *show)
+	}
 
-	c.JSON(http.StatusOK, competitions)
+	respondCompetitionPage(c, query)
 }
 
 // GetUserCompetitions retrieves competitions accessible to the current user
 // @Summary Get user accessible competitions
-// @Description Get all competitions accessible to the current user through their groups
+// @Description Get a page of the competitions accessible to the current user through their groups
 // @Tags Competitions
 // @Accept json
 // @Produce json
-// @Success 200 {array} models.Competition
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param sort query string false "Sort keys among title, starts_at, ends_at, prefixed by - to sort descending"
+// @Param q query string false "Text searched in the title and description"
+// @Param finished query bool false "Only finished or running competitions"
+// @Param group_id query string false "Only competitions of this group"
+// @Success 200 {object} pagination.Page{items=[]models.Competition}
+// @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Router /competitions/user [get]
 // @Security Bearer
@@ -67,25 +210,14 @@ func GetUserCompetitions(c *gin.Context) {
 		return
 	}
 
-	var competitions []models.Competition
-	// Retrieve competitions accessible through the user's groups
-	if err := database.DB.Raw(`
-		SELECT DISTINCT c.*
-		FROM competitions c
-		JOIN competition_groups cat ON c.id = cat.competition_id
-		JOIN user_groups ug ON cat.group_id = ug.group_id
-		WHERE ug.user_id = ? AND c.show = true
-	`, user.ID).Scan(&competitions).Error; err != nil {
-		respondWithError(c, http.StatusInternalServerError, ErrFailedFetchCompetitions)
-		return
-	}
-
-	// Preload associations
-	for i := range competitions {
-		database.DB.Preload("Catalog").Preload("Groups").First(&competitions[i], competitions[i].ID)
-	}
+	// Competitions accessible through the user's groups and their ancestors
+	query := database.DB.Model(&models.Competition{}).
+		Where("show = ?", true).
+		Where("id IN (?)", database.DB.Table("competition_groups").
+			Select("competition_id").
+			Where("group_id IN (?)", hierarchy.MemberGroups(user.ID)))
 
-	c.JSON(http.StatusOK, competitions)
+	respondCompetitionPage(c, query)
 }
 
 // GetCompetition retrieves a competition by ID
@@ -108,13 +240,13 @@ func GetCompetition(c *gin.Context) {
 	competitionID := c.Param("id")
 	var competition models.Competition
 
-	if err := database.DB.Preload("Catalog").Preload("Groups").Where("id = ?", competitionID).First(&competition).Error; err != nil {
+	if err := database.DB.Preload("Puzzles", orderByPuzzleIndex).Preload("Groups").Where("id = ?", competitionID).First(&competition).Error; err != nil {
 		respondWithError(c, http.StatusNotFound, ErrCompetitionNotFound)
 		return
 	}
 
 	// Check if the user has access to this competition
-	hasAccess := hasCompetitionPermission(user, permissions.COMPETITIONS) || 
+	hasAccess := canManageCompetition(user, competitionID, permissions.COMPETITIONS) || 
 				permissions.IsOwner(user) ||
 				userHasAccessToCompetition(user.ID, competitionID)
 
@@ -155,10 +287,21 @@ func CreateCompetition(c *gin.Context) {
 		return
 	}
 
-	// Check that the API environment exists
-	var apiEnv models.Catalog
-	if err := database.DB.First(&apiEnv, "id = ?", req.CatalogID).Error; err != nil {
-		respondWithError(c, http.StatusBadRequest, ErrCatalogNotFound)
+	// Validate the puzzle set against the live catalogs and snapshot it,
+	// so the competition is not affected by later catalog changes
+	puzzles, err := resolveCompetitionPuzzles(req.Puzzles)
+	if err != nil {
+		respondSnapshotError(c, err)
+		return
+	}
+
+	if !validSchedule(req.StartsAt, req.EndsAt, req.Duration) {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidSchedule)
+		return
+	}
+
+	if !canGiveCompetitionToGroups(user, req.GroupIds) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionManageGroups)
 		return
 	}
 
@@ -166,31 +309,45 @@ func CreateCompetition(c *gin.Context) {
 	competition := models.Competition{
 		Title:           req.Title,
 		Description:     req.Description,
-		CatalogTheme:        req.CatalogTheme,
-		CatalogID: req.CatalogID,
 		Finished:        false,
 		Show:            req.Show,
+		TeamMode:        req.TeamMode,
+		StartsAt:        req.StartsAt,
+		EndsAt:          req.EndsAt,
+		Duration:        req.Duration,
 	}
 
+	saveNewCompetition(c, &competition, puzzles, req.GroupIds, ErrFailedCreateCompetition)
+}
+
+// saveNewCompetition stores a new competition with its puzzle set and groups in a single transaction
+// failedMessage: error returned when the competition itself cannot be created
+func saveNewCompetition(c *gin.Context, competition *models.Competition, puzzles []models.CompetitionPuzzle, groupIDs []string, failedMessage string) {
 	// Transaction to ensure atomic operations
 	tx := database.DB.Begin()
 
-	if err := tx.Create(&competition).Error; err != nil {
+	if err := tx.Create(competition).Error; err != nil {
 		tx.Rollback()
-		respondWithError(c, http.StatusInternalServerError, ErrFailedCreateCompetition)
+		respondWithError(c, http.StatusInternalServerError, failedMessage)
+		return
+	}
+
+	if err := replaceCompetitionPuzzles(tx, competition.ID, puzzles); err != nil {
+		tx.Rollback()
+		respondWithError(c, http.StatusInternalServerError, ErrFailedSnapshot)
 		return
 	}
 
 	// Append groups if specified
-	if len(req.GroupIds) > 0 {
+	if len(groupIDs) > 0 {
 		var groups []models.Group
-		if err := database.DB.Where("id IN ?", req.GroupIds).Find(&groups).Error; err != nil {
+		if err := database.DB.Where("id IN ?", groupIDs).Find(&groups).Error; err != nil {
 			tx.Rollback()
 			respondWithError(c, http.StatusBadRequest, ErrGroupNotFound)
 			return
 		}
 
-		if err := tx.Model(&competition).Association("Groups").Append(groups); err != nil {
+		if err := tx.Model(competition).Association("Groups").Append(groups); err != nil {
 			tx.Rollback()
 			respondWithError(c, http.StatusInternalServerError, ErrFailedAddGroup)
 			return
@@ -200,7 +357,7 @@ func CreateCompetition(c *gin.Context) {
 	tx.Commit()
 
 	// Reload the competition with associations
-	database.DB.Preload("Catalog").Preload("Groups").Where("id = ?", competition.ID).First(&competition)
+	database.DB.Preload("Puzzles", orderByPuzzleIndex).Preload("Groups").Where("id = ?", competition.ID).First(competition)
 
 	c.JSON(http.StatusCreated, competition)
 }
@@ -225,7 +382,7 @@ func UpdateCompetition(c *gin.Context) {
 		return
 	}
 
-	if !hasCompetitionPermission(user, permissions.COMPETITIONS) {
+	if !canManageCompetition(user, c.Param("id"), permissions.COMPETITIONS) {
 		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionUpdate)
 		return
 	}
@@ -252,39 +409,101 @@ func UpdateCompetition(c *gin.Context) {
 	if req.Description != "" {
 		updateData["description"] = req.Description
 	}
-	if req.CatalogTheme != "" {
-		updateData["catalog_theme"] = req.CatalogTheme
-	}
-	if req.CatalogID != "" {
-		// Check that the API environment exists
-		var apiEnv models.Catalog
-		if err := database.DB.First(&apiEnv, "id = ?", req.CatalogID).Error; err != nil {
-			respondWithError(c, http.StatusBadRequest, ErrCatalogNotFound)
-			return
-		}
-		updateData["catalog_id"] = req.CatalogID
-	}
 	if req.Finished != nil {
 		updateData["finished"] = *req.Finished
 	}
 	if req.Show != nil {
 		updateData["show"] = *req.Show
 	}
+	if req.StartsAt != nil || req.EndsAt != nil || req.Duration != nil {
+		startsAt, endsAt, duration := competition.StartsAt, competition.EndsAt, competition.Duration
+		if req.StartsAt != nil {
+			startsAt = req.StartsAt
+			updateData["starts_at"] = *req.StartsAt
+		}
+		if req.EndsAt != nil {
+			endsAt = req.EndsAt
+			updateData["ends_at"] = *req.EndsAt
+		}
+		if req.Duration != nil {
+			duration = req.Duration
+			updateData["duration"] = *req.Duration
+		}
+		if !validSchedule(startsAt, endsAt, duration) {
+			respondWithError(c, http.StatusBadRequest, ErrInvalidSchedule)
+			return
+		}
+	}
+	if req.TeamMode != nil && *req.TeamMode != competition.TeamMode {
+		// Switching between individual and team play would orphan existing tries
+		if competitionHasTries(competition.ID) {
+			respondWithError(c, http.StatusConflict, ErrTeamModeHasTries)
+			return
+		}
+		updateData["team_mode"] = *req.TeamMode
+	}
 
-	if err := database.DB.Model(&competition).Updates(updateData).Error; err != nil {
+	// Changing the puzzle set is only allowed before anybody played
+	var puzzles []models.CompetitionPuzzle
+	if req.Puzzles != nil {
+		if competitionHasTries(competition.ID) {
+			respondWithError(c, http.StatusConflict, ErrCompetitionHasTries)
+			return
+		}
+
+		puzzles, err = resolveCompetitionPuzzles(req.Puzzles)
+		if err != nil {
+			respondSnapshotError(c, err)
+			return
+		}
+	} else if req.Show != nil && *req.Show && !competition.Show {
+		// Opening the competition refreshes the snapshot as long as nobody played yet
+		if err := snapshotOnOpen(&competition); err != nil {
+			respondSnapshotError(c, err)
+			return
+		}
+	}
+
+	// Updates writes the new values in the competition
+	wasFinished := competition.Finished
+
+	tx := database.DB.Begin()
+
+	if err := tx.Model(&competition).Updates(updateData).Error; len(updateData) > 0 && err != nil {
+		tx.Rollback()
 		respondWithError(c, http.StatusInternalServerError, ErrFailedUpdateCompetition)
 		return
 	}
 
+	if puzzles != nil {
+		if err := replaceCompetitionPuzzles(tx, competition.ID, puzzles); err != nil {
+			tx.Rollback()
+			respondWithError(c, http.StatusInternalServerError, ErrFailedSnapshot)
+			return
+		}
+	}
+
+	tx.Commit()
+
+	// Finishing or reopening the competition invalidates its cached analytics, as ToggleCompetitionFinished does
+	if req.Finished != nil {
+		database.REDIS.Del(c.Request.Context(), analyticsCacheKey(competition.ID))
+
+		// Look for cheating once the competition is over
+		if *req.Finished && !wasFinished {
+			startCompetitionAnalysis(competition.ID)
+		}
+	}
+
 	// Reload the competition with associations
-	database.DB.Preload("Catalog").Preload("Groups").Where("id = ?", competition.ID).First(&competition)
+	database.DB.Preload("Puzzles", orderByPuzzleIndex).Preload("Groups").Where("id = ?", competition.ID).First(&competition)
 
 	c.JSON(http.StatusOK, competition)
 }
 
-// DeleteCompetition deletes a competition and all related data
+// DeleteCompetition moves a competition to the trash
 // @Summary Delete a competition
-// @Description Delete a competition and all related data
+// @Description Move a competition to the trash, its tries, teams and puzzles are kept until it is purged
 // @Tags Competitions
 // @Accept json
 // @Produce json
@@ -300,7 +519,7 @@ func DeleteCompetition(c *gin.Context) {
 		return
 	}
 
-	if !hasCompetitionPermission(user, permissions.COMPETITIONS) {
+	if !canManageCompetition(user, c.Param("id"), permissions.COMPETITIONS) {
 		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionDelete)
 		return
 	}
@@ -312,32 +531,13 @@ func DeleteCompetition(c *gin.Context) {
 		return
 	}
 
-	// Transaction to ensure atomic operations
-	tx := database.DB.Begin()
-
-	// First, delete tries related to this competition
-	if err := tx.Where("competition_id = ?", competitionID).Delete(&models.Try{}).Error; err != nil {
-		tx.Rollback()
-		respondWithError(c, http.StatusInternalServerError, ErrFailedDeleteCompetition)
-		return
-	}
-
-	// Remove associations with groups
-	if err := tx.Exec("DELETE FROM competition_groups WHERE competition_id = ?", competitionID).Error; err != nil {
-		tx.Rollback()
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return trash.MoveToTrash(tx, models.TrashCompetition, competition.ID, competition.Title, user.ID)
+	}); err != nil {
 		respondWithError(c, http.StatusInternalServerError, ErrFailedDeleteCompetition)
 		return
 	}
 
-	// Delete the competition
-	if err := tx.Delete(&competition).Error; err != nil {
-		tx.Rollback()
-		respondWithError(c, http.StatusInternalServerError, ErrFailedDeleteCompetition)
-		return
-	}
-
-	tx.Commit()
-
 	c.Status(http.StatusNoContent)
 }
 
@@ -359,7 +559,7 @@ func FinishCompetition(c *gin.Context) {
 		return
 	}
 
-	if !hasCompetitionPermission(user, permissions.COMPETITIONS) {
+	if !canManageCompetition(user, c.Param("id"), permissions.COMPETITIONS) {
 		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionFinish)
 		return
 	}
@@ -379,6 +579,14 @@ func FinishCompetition(c *gin.Context) {
 		return
 	}
 
+	// Reopening the competition invalidates its cached analytics
+	database.REDIS.Del(c.Request.Context(), analyticsCacheKey(competition.ID))
+
+	// Look for cheating once the competition is over
+	if competition.Finished {
+		startCompetitionAnalysis(competition.ID)
+	}
+
 	c.JSON(http.StatusOK, competition)
 }
 
@@ -400,7 +608,7 @@ func ToggleCompetitionVisibility(c *gin.Context) {
 		return
 	}
 
-	if !hasCompetitionPermission(user, permissions.COMPETITIONS) {
+	if !canManageCompetition(user, c.Param("id"), permissions.COMPETITIONS) {
 		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionView)
 		return
 	}
@@ -415,6 +623,14 @@ func ToggleCompetitionVisibility(c *gin.Context) {
 	// Toggle the visibility status
 	competition.Show = !competition.Show
 
+	// Opening the competition refreshes the snapshot as long as nobody played yet
+	if competition.Show {
+		if err := snapshotOnOpen(&competition); err != nil {
+			respondSnapshotError(c, err)
+			return
+		}
+	}
+
 	if err := database.DB.Save(&competition).Error; err != nil {
 		respondWithError(c, http.StatusInternalServerError, ErrFailedToggleVisibility)
 		return
@@ -423,17 +639,17 @@ func ToggleCompetitionVisibility(c *gin.Context) {
 	c.JSON(http.StatusOK, competition)
 }
 
-// userHasAccessToCompetition checks if a user has access to a competition through their groups
+// userHasAccessToCompetition checks if a user has access to a competition through their groups,
+// a competition given to a group being open to its subgroups
 func userHasAccessToCompetition(userID string, competitionID string) bool {
 	var count int64
 	err := database.DB.Raw(`
 		SELECT COUNT(*)
 		FROM competition_groups cat
-		JOIN user_groups ug ON cat.group_id = ug.group_id
-		WHERE ug.user_id = ? AND cat.competition_id = ? AND (
-			SELECT show FROM competitions WHERE id = ?
+		WHERE cat.group_id IN (?) AND cat.competition_id = ? AND (
+			SELECT show FROM competitions WHERE id = ? AND deleted_at IS NULL
 		) = true
-	`, userID, competitionID, competitionID).Count(&count).Error
+	`, hierarchy.MemberGroups(userID), competitionID, competitionID).Count(&count).Error
 
 	if err != nil {
 		return false
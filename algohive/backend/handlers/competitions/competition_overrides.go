@@ -0,0 +1,249 @@
+package competitions
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/utils/permissions"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// loadOverrideCompetition loads the competition of an override route and checks the staff permission
+func loadOverrideCompetition(c *gin.Context) (*models.Competition, bool) {
+	if !requireCompetitionManager(c, ErrNoPermissionUpdate) {
+		return nil, false
+	}
+
+	var competition models.Competition
+	if err := database.DB.First(&competition, "id = ?", c.Param("id")).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrCompetitionNotFound)
+		return nil, false
+	}
+
+	return &competition, true
+}
+
+// saveOverride creates or replaces the override of a competition matching the given condition
+func saveOverride(c *gin.Context, override *models.CompetitionTimeOverride, column string, value string) {
+	var req TimeOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidRequest)
+		return
+	}
+
+	if !validSchedule(req.StartsAt, req.EndsAt, req.Duration) {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidSchedule)
+		return
+	}
+
+	// An existing override is replaced, a failed lookup must not be taken for a missing one and insert a duplicate
+	err := database.DB.Where("competition_id = ? AND "+column+" = ?", override.CompetitionID, value).First(override).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedSaveOverride)
+		return
+	}
+
+	override.StartsAt = req.StartsAt
+	override.EndsAt = req.EndsAt
+	override.Duration = req.Duration
+
+	if err := database.DB.Save(override).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedSaveOverride)
+		return
+	}
+
+	c.JSON(http.StatusOK, override)
+}
+
+// GetCompetitionOverrides retrieves the time overrides of a competition
+// @Summary Get the time overrides of a competition
+// @Description Get the per-user and per-group overrides of the start, end and duration of a competition
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Success 200 {array} models.CompetitionTimeOverride
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /competitions/{id}/overrides [get]
+// @Security Bearer
+func GetCompetitionOverrides(c *gin.Context) {
+	competition, ok := loadOverrideCompetition(c)
+	if !ok {
+		return
+	}
+
+	var overrides []models.CompetitionTimeOverride
+	if err := database.DB.Where("competition_id = ?", competition.ID).Find(&overrides).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedFetchOverrides)
+		return
+	}
+
+	c.JSON(http.StatusOK, overrides)
+}
+
+// SetUserOverride sets the time override of a user in a competition
+// @Summary Set the time override of a user
+// @Description Override the start, end or duration of a competition for a user taking part in it through one of its groups,
+// @Description the user override wins over group overrides
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Param user_id path string true "User ID"
+// @Param override body TimeOverrideRequest true "Override"
+// @Success 200 {object} models.CompetitionTimeOverride
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /competitions/{id}/overrides/users/{user_id} [put]
+// @Security Bearer
+func SetUserOverride(c *gin.Context) {
+	competition, ok := loadOverrideCompetition(c)
+	if !ok {
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, "id = ?", c.Param("user_id")).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrUserNotFound)
+		return
+	}
+
+	others, err := nonParticipants(competition.ID, []string{user.ID})
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedSaveOverride)
+		return
+	}
+	if len(others) > 0 {
+		respondWithError(c, http.StatusBadRequest, ErrOverrideNotParticipant)
+		return
+	}
+
+	override := models.CompetitionTimeOverride{
+		CompetitionID: competition.ID,
+		UserID:        &user.ID,
+	}
+	saveOverride(c, &override, "user_id", user.ID)
+}
+
+// SetGroupOverride sets the time override of a group in a competition
+// @Summary Set the time override of a group
+// @Description Override the start, end or duration of a competition for the members of a group.
+// @Description A user in several overridden groups gets the most lenient value of each field
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Param group_id path string true "Group ID"
+// @Param override body TimeOverrideRequest true "Override"
+// @Success 200 {object} models.CompetitionTimeOverride
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /competitions/{id}/overrides/groups/{group_id} [put]
+// @Security Bearer
+func SetGroupOverride(c *gin.Context) {
+	competition, ok := loadOverrideCompetition(c)
+	if !ok {
+		return
+	}
+
+	var group models.Group
+	if err := database.DB.First(&group, "id = ?", c.Param("group_id")).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrGroupNotFound)
+		return
+	}
+
+	override := models.CompetitionTimeOverride{
+		CompetitionID: competition.ID,
+		GroupID:       &group.ID,
+	}
+	saveOverride(c, &override, "group_id", group.ID)
+}
+
+// DeleteCompetitionOverride deletes a time override
+// @Summary Delete a time override
+// @Description Delete a time override, the user or group falls back to the competition schedule
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Param override_id path string true "Override ID"
+// @Success 204 {object} string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /competitions/{id}/overrides/{override_id} [delete]
+// @Security Bearer
+func DeleteCompetitionOverride(c *gin.Context) {
+	competition, ok := loadOverrideCompetition(c)
+	if !ok {
+		return
+	}
+
+	result := database.DB.Where("id = ? AND competition_id = ?", c.Param("override_id"), competition.ID).
+		Delete(&models.CompetitionTimeOverride{})
+	if result.Error != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedDeleteOverride)
+		return
+	}
+	if result.RowsAffected == 0 {
+		respondWithError(c, http.StatusNotFound, ErrOverrideNotFound)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetMyCompetitionSchedule retrieves the schedule that applies to the current user
+// @Summary Get my competition schedule
+// @Description Get the start, end and duration of a competition for the current user, overrides included
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Success 200 {object} ScheduleResponse
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /competitions/{id}/schedule [get]
+// @Security Bearer
+func GetMyCompetitionSchedule(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	competitionID := c.Param("id")
+
+	if !userHasAccessToCompetition(user.ID, competitionID) && !canManageCompetition(user, competitionID, permissions.COMPETITIONS) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionView)
+		return
+	}
+
+	var competition models.Competition
+	if err := database.DB.First(&competition, "id = ?", competitionID).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrCompetitionNotFound)
+		return
+	}
+
+	var teamID *string
+	if competition.TeamMode {
+		if team := findUserTeam(competition.ID, user.ID); team != nil {
+			teamID = &team.ID
+		}
+	}
+
+	s := effectiveSchedule(&competition, user.ID)
+	c.JSON(http.StatusOK, ScheduleResponse{
+		CompetitionID: competition.ID,
+		StartsAt:      s.StartsAt,
+		EndsAt:        s.EndsAt,
+		Duration:      s.Duration,
+		StartedAt:     firstTryStart(&competition, user.ID, teamID),
+		Overridden:    s.Overridden,
+	})
+}
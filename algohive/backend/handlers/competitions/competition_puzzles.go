@@ -0,0 +1,316 @@
+package competitions
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/utils/beeapi"
+	"api/utils/permissions"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var (
+	errCatalogNotFound     = errors.New("catalog not found")
+	errPuzzleNotInCatalog  = errors.New("puzzle not found in the catalog theme")
+	errInvalidPuzzleConfig = errors.New("invalid puzzle weight or time limit")
+)
+
+// orderByPuzzleIndex orders a puzzle preload by its position in the competition
+func orderByPuzzleIndex(db *gorm.DB) *gorm.DB {
+	return db.Order("puzzle_index")
+}
+
+// themeCache avoids fetching the same catalog theme several times while resolving a puzzle set
+type themeCache map[string]*beeapi.Theme
+
+// get returns the live theme of a catalog, fetching it on the first call
+func (cache themeCache) get(catalogID string, theme string) (*beeapi.Theme, error) {
+	key := catalogID + "/" + theme
+	if cached, ok := cache[key]; ok {
+		return cached, nil
+	}
+
+	var catalog models.Catalog
+	if err := database.DB.First(&catalog, "id = ?", catalogID).Error; err != nil {
+		return nil, errCatalogNotFound
+	}
+
+	liveTheme, err := beeapi.GetTheme(catalog.Address, theme)
+	if err != nil {
+		return nil, err
+	}
+
+	cache[key] = liveTheme
+	return liveTheme, nil
+}
+
+// snapshotPuzzle fills the metadata of a competition puzzle from the live catalog
+func (cache themeCache) snapshotPuzzle(puzzle *models.CompetitionPuzzle, at time.Time) error {
+	liveTheme, err := cache.get(puzzle.CatalogID, puzzle.Theme)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range liveTheme.Puzzles {
+		if p.ID == puzzle.PuzzleID {
+			puzzle.Name = p.Name
+			puzzle.Difficulty = p.Difficulty
+			puzzle.StatementHash = p.StatementHash()
+			puzzle.SnapshotAt = at
+			return nil
+		}
+	}
+
+	return errPuzzleNotInCatalog
+}
+
+// resolveCompetitionPuzzles validates a requested puzzle set against the live catalogs
+// requests: the ordered puzzles of the competition
+// returns: the snapshotted puzzle rows (without competition ID) and any error
+func resolveCompetitionPuzzles(requests []CompetitionPuzzleRequest) ([]models.CompetitionPuzzle, error) {
+	cache := themeCache{}
+	now := time.Now()
+
+	puzzles := make([]models.CompetitionPuzzle, 0, len(requests))
+	for i, req := range requests {
+		puzzle := models.CompetitionPuzzle{
+			PuzzleIndex: i,
+			CatalogID:   req.CatalogID,
+			Theme:       req.Theme,
+			PuzzleID:    req.PuzzleID,
+			Weight:      1,
+			TimeLimit:   req.TimeLimit,
+		}
+		if req.Weight != nil {
+			puzzle.Weight = *req.Weight
+		}
+		if puzzle.Weight <= 0 || (puzzle.TimeLimit != nil && *puzzle.TimeLimit <= 0) {
+			return nil, errInvalidPuzzleConfig
+		}
+
+		if err := cache.snapshotPuzzle(&puzzle, now); err != nil {
+			return nil, err
+		}
+
+		puzzles = append(puzzles, puzzle)
+	}
+
+	return puzzles, nil
+}
+
+// replaceCompetitionPuzzles replaces the stored puzzle set of a competition
+// tx: the transaction to run in
+// competitionID: ID of the competition
+// puzzles: the new puzzle rows
+func replaceCompetitionPuzzles(tx *gorm.DB, competitionID string, puzzles []models.CompetitionPuzzle) error {
+	if err := tx.Where("competition_id = ?", competitionID).Delete(&models.CompetitionPuzzle{}).Error; err != nil {
+		return err
+	}
+
+	if len(puzzles) == 0 {
+		return nil
+	}
+
+	for i := range puzzles {
+		puzzles[i].ID = ""
+		puzzles[i].CompetitionID = competitionID
+	}
+
+	return tx.Create(&puzzles).Error
+}
+
+// competitionHasTries checks if at least one try was recorded for the competition
+func competitionHasTries(competitionID string) bool {
+	var count int64
+	database.DB.Model(&models.Try{}).Where("competition_id = ?", competitionID).Count(&count)
+	return count > 0
+}
+
+// snapshotOnOpen refreshes the puzzle metadata when a competition is opened, as long as nobody played yet.
+// Puzzles that cannot be reached in their catalog keep their previous snapshot.
+func snapshotOnOpen(competition *models.Competition) error {
+	if competitionHasTries(competition.ID) {
+		return nil
+	}
+
+	puzzles, err := getCompetitionPuzzles(competition)
+	if err != nil {
+		return err
+	}
+
+	cache := themeCache{}
+	now := time.Now()
+	for i := range puzzles {
+		if err := cache.snapshotPuzzle(&puzzles[i], now); err != nil {
+			log.Printf("Keeping previous snapshot of puzzle %s in competition %s: %v", puzzles[i].PuzzleID, competition.ID, err)
+			continue
+		}
+		if err := database.DB.Save(&puzzles[i]).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getCompetitionPuzzles returns the puzzle set of a competition ordered by puzzle index
+func getCompetitionPuzzles(competition *models.Competition) ([]models.CompetitionPuzzle, error) {
+	var puzzles []models.CompetitionPuzzle
+	if err := database.DB.Where("competition_id = ?", competition.ID).
+		Scopes(orderByPuzzleIndex).Find(&puzzles).Error; err != nil {
+		return nil, err
+	}
+
+	return puzzles, nil
+}
+
+// findCompetitionPuzzle looks up the puzzle at the given index
+func findCompetitionPuzzle(puzzles []models.CompetitionPuzzle, index int) (*models.CompetitionPuzzle, bool) {
+	for i := range puzzles {
+		if puzzles[i].PuzzleIndex == index {
+			return &puzzles[i], true
+		}
+	}
+	return nil, false
+}
+
+// respondSnapshotError maps a puzzle resolution error to the right HTTP response
+func respondSnapshotError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, errCatalogNotFound):
+		respondWithError(c, http.StatusBadRequest, ErrCatalogNotFound)
+	case errors.Is(err, beeapi.ErrThemeNotFound):
+		respondWithError(c, http.StatusBadRequest, ErrCatalogThemeNotFound)
+	case errors.Is(err, errPuzzleNotInCatalog):
+		respondWithError(c, http.StatusBadRequest, ErrPuzzleNotInCatalog)
+	case errors.Is(err, errInvalidPuzzleConfig):
+		respondWithError(c, http.StatusBadRequest, ErrInvalidPuzzleConfig)
+	default:
+		respondWithError(c, http.StatusInternalServerError, ErrFailedSnapshot)
+	}
+}
+
+// GetCompetitionPuzzles retrieves the puzzle set of a competition
+// @Summary Get the puzzles of a competition
+// @Description Get the ordered puzzle set of a competition, with the metadata snapshotted when the competition was created or opened
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Success 200 {array} models.CompetitionPuzzle
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /competitions/{id}/puzzles [get]
+// @Security Bearer
+func GetCompetitionPuzzles(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	competitionID := c.Param("id")
+
+	if !userHasAccessToCompetition(user.ID, competitionID) && !canManageCompetition(user, competitionID, permissions.COMPETITIONS) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionView)
+		return
+	}
+
+	var competition models.Competition
+	if err := database.DB.First(&competition, "id = ?", competitionID).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrCompetitionNotFound)
+		return
+	}
+
+	puzzles, err := getCompetitionPuzzles(&competition)
+	if err != nil {
+		respondSnapshotError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, puzzles)
+}
+
+// DiffCompetitionPuzzles compares the puzzle snapshot of a competition with the live catalogs
+// @Summary Diff the puzzle snapshot against the live catalogs
+// @Description Compare the snapshotted puzzles of a competition with the current content of their catalog themes
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Success 200 {object} SnapshotDiffResponse
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /competitions/{id}/puzzles/diff [get]
+// @Security Bearer
+func DiffCompetitionPuzzles(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	if !canManageCompetition(user, c.Param("id"), permissions.COMPETITIONS) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionView)
+		return
+	}
+
+	competitionID := c.Param("id")
+	var competition models.Competition
+	if err := database.DB.First(&competition, "id = ?", competitionID).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrCompetitionNotFound)
+		return
+	}
+
+	snapshot, err := getCompetitionPuzzles(&competition)
+	if err != nil {
+		respondSnapshotError(c, err)
+		return
+	}
+
+	response := SnapshotDiffResponse{
+		CompetitionID: competition.ID,
+		InSync:        true,
+		Entries:       []SnapshotDiffEntry{},
+	}
+
+	cache := themeCache{}
+	for i := range snapshot {
+		snap := snapshot[i]
+		entry := SnapshotDiffEntry{PuzzleIndex: snap.PuzzleIndex, PuzzleID: snap.PuzzleID, Snapshot: &snap, Status: DiffUnchanged}
+
+		current := snap
+		if err := cache.snapshotPuzzle(&current, time.Now()); err != nil {
+			if !errors.Is(err, errPuzzleNotInCatalog) && !errors.Is(err, beeapi.ErrThemeNotFound) && !errors.Is(err, errCatalogNotFound) {
+				respondWithError(c, http.StatusInternalServerError, ErrFailedSnapshot)
+				return
+			}
+			entry.Status = DiffRemoved
+		} else {
+			entry.Live = &current
+			if current.Name != snap.Name {
+				entry.Changes = append(entry.Changes, "name")
+			}
+			if current.Difficulty != snap.Difficulty {
+				entry.Changes = append(entry.Changes, "difficulty")
+			}
+			if current.StatementHash != snap.StatementHash {
+				entry.Changes = append(entry.Changes, "statement")
+			}
+			if len(entry.Changes) > 0 {
+				entry.Status = DiffModified
+			}
+		}
+
+		if entry.Status != DiffUnchanged {
+			response.InSync = false
+		}
+		response.Entries = append(response.Entries, entry)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
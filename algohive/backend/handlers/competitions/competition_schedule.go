@@ -0,0 +1,155 @@
+package competitions
+
+import (
+	"api/database"
+	"api/models"
+	"api/utils/hierarchy"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errCompetitionNotStarted = errors.New("competition has not started")
+	errCompetitionEnded      = errors.New("competition has ended")
+	errDurationExceeded      = errors.New("competition duration exceeded")
+)
+
+// schedule is the time window and duration that applies to one participant
+type schedule struct {
+	StartsAt   *time.Time
+	EndsAt     *time.Time
+	Duration   *int
+	Overridden bool
+}
+
+// validSchedule checks that a competition window ends after it starts and that its duration is positive
+// duration: time allowed to each participant in minutes, counted from their first try
+func validSchedule(startsAt *time.Time, endsAt *time.Time, duration *int) bool {
+	if startsAt != nil && endsAt != nil && !endsAt.After(*startsAt) {
+		return false
+	}
+	return duration == nil || *duration > 0
+}
+
+// apply replaces the fields set in an override
+func (s *schedule) apply(override models.CompetitionTimeOverride) {
+	if override.StartsAt != nil {
+		s.StartsAt = override.StartsAt
+	}
+	if override.EndsAt != nil {
+		s.EndsAt = override.EndsAt
+	}
+	if override.Duration != nil {
+		s.Duration = override.Duration
+	}
+	s.Overridden = true
+}
+
+// lenientOverride combines the overrides of several groups, keeping the most lenient value of each field
+func lenientOverride(overrides []models.CompetitionTimeOverride) models.CompetitionTimeOverride {
+	var result models.CompetitionTimeOverride
+	for _, override := range overrides {
+		if override.StartsAt != nil && (result.StartsAt == nil || override.StartsAt.Before(*result.StartsAt)) {
+			result.StartsAt = override.StartsAt
+		}
+		if override.EndsAt != nil && (result.EndsAt == nil || override.EndsAt.After(*result.EndsAt)) {
+			result.EndsAt = override.EndsAt
+		}
+		if override.Duration != nil && (result.Duration == nil || *override.Duration > *result.Duration) {
+			result.Duration = override.Duration
+		}
+	}
+	return result
+}
+
+// mergeSchedule returns the schedule of a competition once the overrides of a user are applied,
+// the overrides of their groups and their ancestors first then their own
+// userOverride: the override of the user, nil when they have none
+func mergeSchedule(competition *models.Competition, groupOverrides []models.CompetitionTimeOverride, userOverride *models.CompetitionTimeOverride) schedule {
+	result := schedule{
+		StartsAt: competition.StartsAt,
+		EndsAt:   competition.EndsAt,
+		Duration: competition.Duration,
+	}
+
+	if len(groupOverrides) > 0 {
+		result.apply(lenientOverride(groupOverrides))
+	}
+	if userOverride != nil {
+		result.apply(*userOverride)
+	}
+
+	return result
+}
+
+// effectiveSchedule returns the schedule of a user, see mergeSchedule
+func effectiveSchedule(competition *models.Competition, userID string) schedule {
+	var groupOverrides []models.CompetitionTimeOverride
+	database.DB.Where("competition_id = ? AND group_id IN (?)", competition.ID, hierarchy.MemberGroups(userID)).
+		Find(&groupOverrides)
+
+	var userOverride *models.CompetitionTimeOverride
+	var override models.CompetitionTimeOverride
+	if err := database.DB.Where("competition_id = ? AND user_id = ?", competition.ID, userID).
+		First(&override).Error; err == nil {
+		userOverride = &override
+	}
+
+	return mergeSchedule(competition, groupOverrides, userOverride)
+}
+
+// firstTryStart returns when a participant (a user, or their team in team competitions) started playing
+func firstTryStart(competition *models.Competition, userID string, teamID *string) *time.Time {
+	query := database.DB.Model(&models.Try{}).Where("competition_id = ?", competition.ID)
+	if teamID != nil {
+		query = query.Where("team_id = ?", *teamID)
+	} else {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var first struct {
+		StartedAt *time.Time
+	}
+	if err := query.Select("MIN(start_time) AS started_at").Scan(&first).Error; err != nil {
+		return nil
+	}
+	return first.StartedAt
+}
+
+// checkCompetitionSchedule checks that a participant is still allowed to play at the given time,
+// honoring the time overrides of the user and their groups
+// teamID: team of the user in team competitions, nil otherwise
+func checkCompetitionSchedule(competition *models.Competition, userID string, teamID *string, now time.Time) error {
+	s := effectiveSchedule(competition, userID)
+
+	if s.StartsAt != nil && now.Before(*s.StartsAt) {
+		return errCompetitionNotStarted
+	}
+	if s.EndsAt != nil && now.After(*s.EndsAt) {
+		return errCompetitionEnded
+	}
+
+	if s.Duration != nil {
+		startedAt := firstTryStart(competition, userID, teamID)
+		if startedAt != nil && now.After(startedAt.Add(time.Duration(*s.Duration)*time.Minute)) {
+			return errDurationExceeded
+		}
+	}
+
+	return nil
+}
+
+// respondScheduleError maps a schedule error to the right HTTP response
+func respondScheduleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, errCompetitionNotStarted):
+		respondWithError(c, http.StatusForbidden, ErrCompetitionNotStarted)
+	case errors.Is(err, errCompetitionEnded):
+		respondWithError(c, http.StatusForbidden, ErrCompetitionEnded)
+	default:
+		respondWithError(c, http.StatusForbidden, ErrDurationExceeded)
+	}
+}
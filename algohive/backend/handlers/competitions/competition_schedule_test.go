@@ -0,0 +1,148 @@
+package competitions
+
+import (
+	"api/models"
+	"testing"
+	"time"
+)
+
+var scheduleBase = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+// at returns the time some hours after scheduleBase
+func at(hours int) *time.Time {
+	t := scheduleBase.Add(time.Duration(hours) * time.Hour)
+	return &t
+}
+
+// minutes returns a duration in minutes
+func minutes(n int) *int {
+	return &n
+}
+
+func sameTime(a, b *time.Time) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && a.Equal(*b))
+}
+
+func sameDuration(a, b *int) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func sameSchedule(a, b schedule) bool {
+	return sameTime(a.StartsAt, b.StartsAt) && sameTime(a.EndsAt, b.EndsAt) &&
+		sameDuration(a.Duration, b.Duration) && a.Overridden == b.Overridden
+}
+
+func TestLenientOverride(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides []models.CompetitionTimeOverride
+		want      models.CompetitionTimeOverride
+	}{
+		{"no override", nil, models.CompetitionTimeOverride{}},
+		{
+			"single override",
+			[]models.CompetitionTimeOverride{{StartsAt: at(1), EndsAt: at(5), Duration: minutes(60)}},
+			models.CompetitionTimeOverride{StartsAt: at(1), EndsAt: at(5), Duration: minutes(60)},
+		},
+		{
+			"earliest start, latest end and longest duration",
+			[]models.CompetitionTimeOverride{
+				{StartsAt: at(2), EndsAt: at(8), Duration: minutes(30)},
+				{StartsAt: at(1), EndsAt: at(6), Duration: minutes(90)},
+			},
+			models.CompetitionTimeOverride{StartsAt: at(1), EndsAt: at(8), Duration: minutes(90)},
+		},
+		{
+			"fields left out by one override are taken from another",
+			[]models.CompetitionTimeOverride{
+				{EndsAt: at(4)},
+				{Duration: minutes(45)},
+			},
+			models.CompetitionTimeOverride{EndsAt: at(4), Duration: minutes(45)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lenientOverride(tt.overrides)
+			if !sameTime(got.StartsAt, tt.want.StartsAt) || !sameTime(got.EndsAt, tt.want.EndsAt) ||
+				!sameDuration(got.Duration, tt.want.Duration) {
+				t.Errorf("lenientOverride() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeSchedule(t *testing.T) {
+	competition := &models.Competition{StartsAt: at(0), EndsAt: at(4), Duration: minutes(120)}
+
+	tests := []struct {
+		name           string
+		groupOverrides []models.CompetitionTimeOverride
+		userOverride   *models.CompetitionTimeOverride
+		want           schedule
+	}{
+		{
+			"no override",
+			nil, nil,
+			schedule{StartsAt: at(0), EndsAt: at(4), Duration: minutes(120)},
+		},
+		{
+			"group override replaces the fields it sets",
+			[]models.CompetitionTimeOverride{{EndsAt: at(6)}},
+			nil,
+			schedule{StartsAt: at(0), EndsAt: at(6), Duration: minutes(120), Overridden: true},
+		},
+		{
+			"most lenient of several groups",
+			[]models.CompetitionTimeOverride{{EndsAt: at(6)}, {EndsAt: at(5), Duration: minutes(180)}},
+			nil,
+			schedule{StartsAt: at(0), EndsAt: at(6), Duration: minutes(180), Overridden: true},
+		},
+		{
+			"user override applies after the groups",
+			[]models.CompetitionTimeOverride{{EndsAt: at(6), Duration: minutes(180)}},
+			&models.CompetitionTimeOverride{EndsAt: at(3)},
+			schedule{StartsAt: at(0), EndsAt: at(3), Duration: minutes(180), Overridden: true},
+		},
+		{
+			"empty user override keeps the competition schedule",
+			nil,
+			&models.CompetitionTimeOverride{},
+			schedule{StartsAt: at(0), EndsAt: at(4), Duration: minutes(120), Overridden: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeSchedule(competition, tt.groupOverrides, tt.userOverride); !sameSchedule(got, tt.want) {
+				t.Errorf("mergeSchedule() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		startsAt *time.Time
+		endsAt   *time.Time
+		duration *int
+		want     bool
+	}{
+		{"open competition", nil, nil, nil, true},
+		{"window", at(0), at(2), nil, true},
+		{"end before start", at(2), at(0), nil, false},
+		{"end at start", at(2), at(2), nil, false},
+		{"positive duration", nil, nil, minutes(30), true},
+		{"zero duration", nil, nil, minutes(0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validSchedule(tt.startsAt, tt.endsAt, tt.duration); got != tt.want {
+				t.Errorf("validSchedule() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
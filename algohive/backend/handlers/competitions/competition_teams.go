@@ -0,0 +1,496 @@
+package competitions
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/utils/hierarchy"
+	"api/utils/permissions"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// isUniqueViolation checks if an error comes from a unique index, such as the one allowing a user in one team per competition
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// findUserTeam retrieves the team of a user in a competition
+// competitionID: ID of the competition
+// userID: ID of the user
+// returns: the team, or nil if the user is not part of any team
+func findUserTeam(competitionID string, userID string) *models.Team {
+	var team models.Team
+	if err := database.DB.Joins("JOIN team_members tm ON tm.team_id = teams.id").
+		Where("teams.competition_id = ? AND tm.user_id = ?", competitionID, userID).
+		First(&team).Error; err != nil {
+		return nil
+	}
+	return &team
+}
+
+// usersAlreadyInTeam returns the users among userIDs that already belong to a team of the competition
+func usersAlreadyInTeam(competitionID string, userIDs []string) []string {
+	var taken []string
+	database.DB.Raw(`
+		SELECT DISTINCT tm.user_id
+		FROM team_members tm
+		JOIN teams t ON t.id = tm.team_id
+		WHERE t.competition_id = ? AND tm.user_id IN ?
+	`, competitionID, userIDs).Pluck("user_id", &taken)
+	return taken
+}
+
+// competitionGroups selects the IDs of the groups of a competition
+func competitionGroups(competitionID string) *gorm.DB {
+	return database.DB.Table("competition_groups").Select("group_id").Where("competition_id = ?", competitionID)
+}
+
+// nonParticipants returns the users among userIDs who do not take part in a competition through one of its groups
+// or their subgroups, as checked by userHasAccessToCompetition. Teams are formed before the competition is shown,
+// so its visibility is not required
+func nonParticipants(competitionID string, userIDs []string) ([]string, error) {
+	var participants []string
+	if err := database.DB.Raw("SELECT user_id FROM (?) members WHERE user_id IN ?",
+		hierarchy.Members(competitionGroups(competitionID)), userIDs).Scan(&participants).Error; err != nil {
+		return nil, err
+	}
+
+	taking := make(map[string]bool, len(participants))
+	for _, id := range participants {
+		taking[id] = true
+	}
+	var others []string
+	for _, id := range userIDs {
+		if !taking[id] {
+			others = append(others, id)
+		}
+	}
+	return others, nil
+}
+
+// canTeamUpGroup checks if a team can be formed from a group: one of the groups of the competition or their subgroups,
+// or a group the user manages competitions for
+// user: the authenticated user
+// competitionID: ID of the competition
+// groupID: ID of the group
+func canTeamUpGroup(user models.User, competitionID string, groupID string) bool {
+	if permissions.IsOwner(user) {
+		return true
+	}
+
+	var count int64
+	if err := database.DB.Raw(
+		"SELECT COUNT(*) FROM ((?) UNION (?)) allowed WHERE id = ?",
+		hierarchy.Subtree(competitionGroups(competitionID)), hierarchy.ManagedGroupsWith(user.ID, permissions.COMPETITIONS), groupID,
+	).Scan(&count).Error; err != nil {
+		return false
+	}
+	return count > 0
+}
+
+// preloadTeamMembers preloads the members of teams with the columns of their public profile only
+func preloadTeamMembers(db *gorm.DB) *gorm.DB {
+	return db.Preload("Members", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "firstname", "lastname").Order("lastname, firstname")
+	})
+}
+
+// newTeamResponse converts a team loaded with its members, no other column of the members is exposed
+func newTeamResponse(team models.Team) TeamResponse {
+	response := TeamResponse{
+		ID:            team.ID,
+		Name:          team.Name,
+		CompetitionID: team.CompetitionID,
+		GroupID:       team.GroupID,
+		Members:       make([]TeamMember, 0, len(team.Members)),
+	}
+	for _, member := range team.Members {
+		response.Members = append(response.Members, TeamMember{ID: member.ID, Firstname: member.Firstname, Lastname: member.Lastname})
+	}
+	return response
+}
+
+// respondWithTeam responds with a team and the public profile of its members
+func respondWithTeam(c *gin.Context, status int, teamID string) {
+	var team models.Team
+	if err := preloadTeamMembers(database.DB).First(&team, "id = ?", teamID).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedFetchTeams)
+		return
+	}
+	c.JSON(status, newTeamResponse(team))
+}
+
+// loadTeamCompetition loads the competition of a team route and checks the staff permission
+// returns: the authenticated user and the competition
+func loadTeamCompetition(c *gin.Context) (models.User, *models.Competition, bool) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return user, nil, false
+	}
+
+	if !canManageCompetition(user, c.Param("id"), permissions.COMPETITIONS) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionManageTeams)
+		return user, nil, false
+	}
+
+	var competition models.Competition
+	if err := database.DB.First(&competition, "id = ?", c.Param("id")).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrCompetitionNotFound)
+		return user, nil, false
+	}
+
+	if !competition.TeamMode {
+		respondWithError(c, http.StatusBadRequest, ErrNotTeamCompetition)
+		return user, nil, false
+	}
+
+	return user, &competition, true
+}
+
+// createTeamWithMembers creates a team and attaches its members in a single transaction
+func createTeamWithMembers(c *gin.Context, team *models.Team, userIDs []string) {
+	if len(userIDs) > 0 {
+		if taken := usersAlreadyInTeam(team.CompetitionID, userIDs); len(taken) > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": ErrUserAlreadyInTeam, "user_ids": taken})
+			return
+		}
+	}
+
+	var members []models.User
+	if len(userIDs) > 0 {
+		if err := database.DB.Where("id IN ?", userIDs).Find(&members).Error; err != nil || len(members) != len(userIDs) {
+			respondWithError(c, http.StatusBadRequest, ErrUserNotFound)
+			return
+		}
+
+		others, err := nonParticipants(team.CompetitionID, userIDs)
+		if err != nil {
+			respondWithError(c, http.StatusInternalServerError, ErrFailedCreateTeam)
+			return
+		}
+		if len(others) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": ErrUserNotParticipant, "user_ids": others})
+			return
+		}
+	}
+
+	tx := database.DB.Begin()
+
+	if err := tx.Create(team).Error; err != nil {
+		tx.Rollback()
+		respondWithError(c, http.StatusInternalServerError, ErrFailedCreateTeam)
+		return
+	}
+
+	if len(members) > 0 {
+		if err := tx.Model(team).Association("Members").Append(members); err != nil {
+			tx.Rollback()
+			// A concurrent request put one of the members in another team
+			if isUniqueViolation(err) {
+				respondWithError(c, http.StatusConflict, ErrUserAlreadyInTeam)
+				return
+			}
+			respondWithError(c, http.StatusInternalServerError, ErrFailedCreateTeam)
+			return
+		}
+	}
+
+	tx.Commit()
+
+	respondWithTeam(c, http.StatusCreated, team.ID)
+}
+
+// GetCompetitionTeams retrieves all teams of a competition
+// @Summary Get the teams of a competition
+// @Description Get all teams of a team competition with their members
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Success 200 {array} TeamResponse
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /competitions/{id}/teams [get]
+// @Security Bearer
+func GetCompetitionTeams(c *gin.Context) {
+	_, competition, ok := loadTeamCompetition(c)
+	if !ok {
+		return
+	}
+
+	var teams []models.Team
+	if err := preloadTeamMembers(database.DB).Where("competition_id = ?", competition.ID).
+		Order("name").Find(&teams).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedFetchTeams)
+		return
+	}
+
+	responses := make([]TeamResponse, len(teams))
+	for i := range teams {
+		responses[i] = newTeamResponse(teams[i])
+	}
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetMyCompetitionTeam retrieves the team of the current user in a competition
+// @Summary Get my team in a competition
+// @Description Get the team the current user belongs to in a team competition
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Success 200 {object} TeamResponse
+// @Failure 404 {object} map[string]string
+// @Router /competitions/{id}/teams/me [get]
+// @Security Bearer
+func GetMyCompetitionTeam(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	team := findUserTeam(c.Param("id"), user.ID)
+	if team == nil {
+		respondWithError(c, http.StatusNotFound, ErrTeamNotFound)
+		return
+	}
+
+	respondWithTeam(c, http.StatusOK, team.ID)
+}
+
+// CreateCompetitionTeam creates a team in a competition
+// @Summary Create a team
+// @Description Create a team in a team competition with an optional list of members, who must take part in the competition through one of its groups
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Param team body CreateTeamRequest true "Team to create"
+// @Success 201 {object} TeamResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /competitions/{id}/teams [post]
+// @Security Bearer
+func CreateCompetitionTeam(c *gin.Context) {
+	_, competition, ok := loadTeamCompetition(c)
+	if !ok {
+		return
+	}
+
+	var req CreateTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidRequest)
+		return
+	}
+
+	team := models.Team{
+		Name:          req.Name,
+		CompetitionID: competition.ID,
+	}
+	createTeamWithMembers(c, &team, req.UserIDs)
+}
+
+// CreateCompetitionTeamFromGroup creates a team from the members of a group
+// @Summary Create a team from a group
+// @Description Create a team in a team competition containing every member of a group. The group is one of the groups of the competition
+// @Description or their subgroups, or a group the user manages competitions for, and its members must take part in the competition
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Param group_id path string true "Group ID"
+// @Success 201 {object} TeamResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /competitions/{id}/teams/group/{group_id} [post]
+// @Security Bearer
+func CreateCompetitionTeamFromGroup(c *gin.Context) {
+	user, competition, ok := loadTeamCompetition(c)
+	if !ok {
+		return
+	}
+
+	var group models.Group
+	if err := database.DB.First(&group, "id = ?", c.Param("group_id")).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrGroupNotFound)
+		return
+	}
+
+	if !canTeamUpGroup(user, competition.ID, group.ID) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionTeamGroup)
+		return
+	}
+
+	// Users in the trash are not members anymore
+	userIDs := []string{}
+	if err := database.DB.Table("user_groups ug").
+		Joins("JOIN users u ON u.id = ug.user_id AND u.deleted_at IS NULL").
+		Where("ug.group_id = ?", group.ID).
+		Pluck("ug.user_id", &userIDs).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedCreateTeam)
+		return
+	}
+
+	team := models.Team{
+		Name:          group.Name,
+		CompetitionID: competition.ID,
+		GroupID:       &group.ID,
+	}
+	createTeamWithMembers(c, &team, userIDs)
+}
+
+// DeleteCompetitionTeam deletes a team of a competition
+// @Summary Delete a team
+// @Description Delete a team that has not recorded any try yet
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Param team_id path string true "Team ID"
+// @Success 204 {object} string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /competitions/{id}/teams/{team_id} [delete]
+// @Security Bearer
+func DeleteCompetitionTeam(c *gin.Context) {
+	_, competition, ok := loadTeamCompetition(c)
+	if !ok {
+		return
+	}
+
+	var team models.Team
+	if err := database.DB.Where("id = ? AND competition_id = ?", c.Param("team_id"), competition.ID).First(&team).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrTeamNotFound)
+		return
+	}
+
+	var tries int64
+	database.DB.Model(&models.Try{}).Where("team_id = ?", team.ID).Count(&tries)
+	if tries > 0 {
+		respondWithError(c, http.StatusConflict, ErrTeamHasTries)
+		return
+	}
+
+	tx := database.DB.Begin()
+
+	if err := tx.Model(&team).Association("Members").Clear(); err != nil {
+		tx.Rollback()
+		respondWithError(c, http.StatusInternalServerError, ErrFailedDeleteTeam)
+		return
+	}
+
+	if err := tx.Delete(&team).Error; err != nil {
+		tx.Rollback()
+		respondWithError(c, http.StatusInternalServerError, ErrFailedDeleteTeam)
+		return
+	}
+
+	tx.Commit()
+	c.Status(http.StatusNoContent)
+}
+
+// AddTeamMember adds a user to a team
+// @Summary Add a member to a team
+// @Description Add a user taking part in the competition to a team, a user can only be part of one team per competition
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Param team_id path string true "Team ID"
+// @Param user_id path string true "User ID"
+// @Success 200 {object} TeamResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /competitions/{id}/teams/{team_id}/members/{user_id} [post]
+// @Security Bearer
+func AddTeamMember(c *gin.Context) {
+	_, competition, ok := loadTeamCompetition(c)
+	if !ok {
+		return
+	}
+
+	var team models.Team
+	if err := database.DB.Where("id = ? AND competition_id = ?", c.Param("team_id"), competition.ID).First(&team).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrTeamNotFound)
+		return
+	}
+
+	var member models.User
+	if err := database.DB.First(&member, "id = ?", c.Param("user_id")).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrUserNotFound)
+		return
+	}
+
+	others, err := nonParticipants(competition.ID, []string{member.ID})
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedUpdateTeam)
+		return
+	}
+	if len(others) > 0 {
+		respondWithError(c, http.StatusBadRequest, ErrUserNotParticipant)
+		return
+	}
+
+	if len(usersAlreadyInTeam(competition.ID, []string{member.ID})) > 0 {
+		respondWithError(c, http.StatusConflict, ErrUserAlreadyInTeam)
+		return
+	}
+
+	if err := database.DB.Model(&team).Association("Members").Append(&member); err != nil {
+		// A concurrent request put the user in another team
+		if isUniqueViolation(err) {
+			respondWithError(c, http.StatusConflict, ErrUserAlreadyInTeam)
+			return
+		}
+		respondWithError(c, http.StatusInternalServerError, ErrFailedUpdateTeam)
+		return
+	}
+
+	respondWithTeam(c, http.StatusOK, team.ID)
+}
+
+// RemoveTeamMember removes a user from a team
+// @Summary Remove a member from a team
+// @Description Remove a user from a team, the tries they submitted stay attributed to the team
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Param team_id path string true "Team ID"
+// @Param user_id path string true "User ID"
+// @Success 200 {object} TeamResponse
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /competitions/{id}/teams/{team_id}/members/{user_id} [delete]
+// @Security Bearer
+func RemoveTeamMember(c *gin.Context) {
+	_, competition, ok := loadTeamCompetition(c)
+	if !ok {
+		return
+	}
+
+	var team models.Team
+	if err := database.DB.Where("id = ? AND competition_id = ?", c.Param("team_id"), competition.ID).First(&team).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrTeamNotFound)
+		return
+	}
+
+	if err := database.DB.Exec("DELETE FROM team_members WHERE team_id = ? AND user_id = ?",
+		team.ID, c.Param("user_id")).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedUpdateTeam)
+		return
+	}
+
+	respondWithTeam(c, http.StatusOK, team.ID)
+}
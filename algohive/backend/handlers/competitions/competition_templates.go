@@ -0,0 +1,432 @@
+package competitions
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/utils/permissions"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requireCompetitionPermission checks that the current user holds the COMPETITIONS permission in one of their scopes,
+// templates belong to no scope, see hasCompetitionPermission
+func requireCompetitionPermission(c *gin.Context, message string) bool {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return false
+	}
+
+	if !hasCompetitionPermission(user, permissions.COMPETITIONS) {
+		respondWithError(c, http.StatusUnauthorized, message)
+		return false
+	}
+	return true
+}
+
+// requireCompetitionManager checks that the current user can manage the competition of the route, see canManageCompetition
+func requireCompetitionManager(c *gin.Context, message string) bool {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return false
+	}
+
+	if !canManageCompetition(user, c.Param("id"), permissions.COMPETITIONS) {
+		respondWithError(c, http.StatusUnauthorized, message)
+		return false
+	}
+	return true
+}
+
+// templatePuzzleRequests converts the puzzle set of a template to puzzle requests, ready to be resolved
+func templatePuzzleRequests(template *models.CompetitionTemplate) []CompetitionPuzzleRequest {
+	requests := make([]CompetitionPuzzleRequest, 0, len(template.Puzzles))
+	for _, p := range template.Puzzles {
+		weight := p.Weight
+		requests = append(requests, CompetitionPuzzleRequest{
+			CatalogID: p.CatalogID,
+			Theme:     p.Theme,
+			PuzzleID:  p.PuzzleID,
+			Weight:    &weight,
+			TimeLimit: p.TimeLimit,
+		})
+	}
+	return requests
+}
+
+// saveTemplate stores a template with the catalog references of its puzzle set
+func saveTemplate(c *gin.Context, template *models.CompetitionTemplate, puzzles []models.CompetitionPuzzle) {
+	tx := database.DB.Begin()
+
+	if err := tx.Create(template).Error; err != nil {
+		tx.Rollback()
+		respondWithError(c, http.StatusInternalServerError, ErrFailedCreateTemplate)
+		return
+	}
+
+	for _, p := range puzzles {
+		puzzle := models.CompetitionTemplatePuzzle{
+			TemplateID:  template.ID,
+			PuzzleIndex: p.PuzzleIndex,
+			CatalogID:   p.CatalogID,
+			Theme:       p.Theme,
+			PuzzleID:    p.PuzzleID,
+			Weight:      p.Weight,
+			TimeLimit:   p.TimeLimit,
+		}
+		if err := tx.Create(&puzzle).Error; err != nil {
+			tx.Rollback()
+			respondWithError(c, http.StatusInternalServerError, ErrFailedCreateTemplate)
+			return
+		}
+	}
+
+	tx.Commit()
+
+	database.DB.Preload("Puzzles", orderByPuzzleIndex).First(template, "id = ?", template.ID)
+	c.JSON(http.StatusCreated, template)
+}
+
+// CloneCompetition clones the configuration of a competition
+// @Summary Clone a competition
+// @Description Copy the puzzle set, scoring, scheduling settings and groups of a competition, without its tries and teams.
+// @Description Groups can be remapped to new ones, the clone keeps the length of the source window when only its start is given
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Param competition body CloneCompetitionRequest true "Clone settings"
+// @Success 201 {object} models.Competition
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /competitions/{id}/clone [post]
+// @Security Bearer
+func CloneCompetition(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	if !canManageCompetition(user, c.Param("id"), permissions.COMPETITIONS) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionCreate)
+		return
+	}
+
+	var source models.Competition
+	if err := database.DB.Preload("Groups").First(&source, "id = ?", c.Param("id")).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrCompetitionNotFound)
+		return
+	}
+
+	var req CloneCompetitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidRequest)
+		return
+	}
+
+	// Shift the window of the source to the new start
+	endsAt := req.EndsAt
+	if endsAt == nil && req.StartsAt != nil && source.StartsAt != nil && source.EndsAt != nil {
+		shifted := req.StartsAt.Add(source.EndsAt.Sub(*source.StartsAt))
+		endsAt = &shifted
+	}
+
+	if !validSchedule(req.StartsAt, endsAt, source.Duration) {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidSchedule)
+		return
+	}
+
+	puzzles, err := getCompetitionPuzzles(&source)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedCloneCompetition)
+		return
+	}
+
+	groupIDs := make([]string, 0, len(source.Groups))
+	for _, group := range source.Groups {
+		if mapped, ok := req.GroupMapping[group.ID]; ok {
+			groupIDs = append(groupIDs, mapped)
+		} else {
+			groupIDs = append(groupIDs, group.ID)
+		}
+	}
+
+	if !canGiveCompetitionToGroups(user, groupIDs) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionManageGroups)
+		return
+	}
+
+	description := req.Description
+	if description == "" {
+		description = source.Description
+	}
+
+	competition := models.Competition{
+		Title:       req.Title,
+		Description: description,
+		Finished:    false,
+		Show:        req.Show,
+		TeamMode:    source.TeamMode,
+		StartsAt:    req.StartsAt,
+		EndsAt:      endsAt,
+		Duration:    source.Duration,
+	}
+
+	saveNewCompetition(c, &competition, puzzles, groupIDs, ErrFailedCloneCompetition)
+}
+
+// GetCompetitionTemplates retrieves all competition templates
+// @Summary Get all competition templates
+// @Description Get all saved competition templates with their puzzle sets
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.CompetitionTemplate
+// @Failure 401 {object} map[string]string
+// @Router /competitions/templates [get]
+// @Security Bearer
+func GetCompetitionTemplates(c *gin.Context) {
+	if !requireCompetitionPermission(c, ErrNoPermissionView) {
+		return
+	}
+
+	var templates []models.CompetitionTemplate
+	if err := database.DB.Preload("Puzzles", orderByPuzzleIndex).Order("name").Find(&templates).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedFetchTemplates)
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetCompetitionTemplate retrieves a competition template by ID
+// @Summary Get a competition template
+// @Description Get a competition template and its puzzle set
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param template_id path string true "Template ID"
+// @Success 200 {object} models.CompetitionTemplate
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /competitions/templates/{template_id} [get]
+// @Security Bearer
+func GetCompetitionTemplate(c *gin.Context) {
+	if !requireCompetitionPermission(c, ErrNoPermissionView) {
+		return
+	}
+
+	var template models.CompetitionTemplate
+	if err := database.DB.Preload("Puzzles", orderByPuzzleIndex).First(&template, "id = ?", c.Param("template_id")).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrTemplateNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// CreateCompetitionTemplate creates a competition template
+// @Summary Create a competition template
+// @Description Save a reusable competition configuration, its puzzles are checked against the live catalogs
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param template body CreateTemplateRequest true "Template to create"
+// @Success 201 {object} models.CompetitionTemplate
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /competitions/templates [post]
+// @Security Bearer
+func CreateCompetitionTemplate(c *gin.Context) {
+	if !requireCompetitionPermission(c, ErrNoPermissionCreate) {
+		return
+	}
+
+	var req CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidRequest)
+		return
+	}
+
+	if !validSchedule(nil, nil, req.Duration) {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidSchedule)
+		return
+	}
+
+	puzzles, err := resolveCompetitionPuzzles(req.Puzzles)
+	if err != nil {
+		respondSnapshotError(c, err)
+		return
+	}
+
+	template := models.CompetitionTemplate{
+		Name:        req.Name,
+		Title:       req.Title,
+		Description: req.Description,
+		TeamMode:    req.TeamMode,
+		Duration:    req.Duration,
+	}
+	saveTemplate(c, &template, puzzles)
+}
+
+// SaveCompetitionAsTemplate saves the configuration of a competition as a template
+// @Summary Save a competition as a template
+// @Description Save the puzzle set, scoring and duration of a competition as a reusable template
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Param template body SaveTemplateRequest true "Template name"
+// @Success 201 {object} models.CompetitionTemplate
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /competitions/{id}/template [post]
+// @Security Bearer
+func SaveCompetitionAsTemplate(c *gin.Context) {
+	if !requireCompetitionManager(c, ErrNoPermissionCreate) {
+		return
+	}
+
+	var competition models.Competition
+	if err := database.DB.First(&competition, "id = ?", c.Param("id")).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrCompetitionNotFound)
+		return
+	}
+
+	var req SaveTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidRequest)
+		return
+	}
+
+	puzzles, err := getCompetitionPuzzles(&competition)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedCreateTemplate)
+		return
+	}
+
+	template := models.CompetitionTemplate{
+		Name:        req.Name,
+		Title:       competition.Title,
+		Description: competition.Description,
+		TeamMode:    competition.TeamMode,
+		Duration:    competition.Duration,
+	}
+	saveTemplate(c, &template, puzzles)
+}
+
+// InstantiateCompetitionTemplate creates a competition from a template
+// @Summary Instantiate a competition template
+// @Description Create a competition from a template with new dates and groups, the puzzles are snapshotted from the live catalogs
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param template_id path string true "Template ID"
+// @Param competition body InstantiateTemplateRequest true "Competition settings"
+// @Success 201 {object} models.Competition
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /competitions/templates/{template_id}/instantiate [post]
+// @Security Bearer
+func InstantiateCompetitionTemplate(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	if !hasCompetitionPermission(user, permissions.COMPETITIONS) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionCreate)
+		return
+	}
+
+	var template models.CompetitionTemplate
+	if err := database.DB.Preload("Puzzles", orderByPuzzleIndex).First(&template, "id = ?", c.Param("template_id")).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrTemplateNotFound)
+		return
+	}
+
+	var req InstantiateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidRequest)
+		return
+	}
+
+	if !validSchedule(req.StartsAt, req.EndsAt, template.Duration) {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidSchedule)
+		return
+	}
+
+	if !canGiveCompetitionToGroups(user, req.GroupIds) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionManageGroups)
+		return
+	}
+
+	puzzles, err := resolveCompetitionPuzzles(templatePuzzleRequests(&template))
+	if err != nil {
+		respondSnapshotError(c, err)
+		return
+	}
+
+	description := req.Description
+	if description == "" {
+		description = template.Description
+	}
+
+	competition := models.Competition{
+		Title:       req.Title,
+		Description: description,
+		Finished:    false,
+		Show:        req.Show,
+		TeamMode:    template.TeamMode,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+		Duration:    template.Duration,
+	}
+
+	saveNewCompetition(c, &competition, puzzles, req.GroupIds, ErrFailedCreateCompetition)
+}
+
+// DeleteCompetitionTemplate deletes a competition template
+// @Summary Delete a competition template
+// @Description Delete a competition template, competitions created from it are not affected
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param template_id path string true "Template ID"
+// @Success 204 {object} string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /competitions/templates/{template_id} [delete]
+// @Security Bearer
+func DeleteCompetitionTemplate(c *gin.Context) {
+	if !requireCompetitionPermission(c, ErrNoPermissionDelete) {
+		return
+	}
+
+	var template models.CompetitionTemplate
+	if err := database.DB.First(&template, "id = ?", c.Param("template_id")).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrTemplateNotFound)
+		return
+	}
+
+	tx := database.DB.Begin()
+
+	if err := tx.Where("template_id = ?", template.ID).Delete(&models.CompetitionTemplatePuzzle{}).Error; err != nil {
+		tx.Rollback()
+		respondWithError(c, http.StatusInternalServerError, ErrFailedDeleteTemplate)
+		return
+	}
+
+	if err := tx.Delete(&template).Error; err != nil {
+		tx.Rollback()
+		respondWithError(c, http.StatusInternalServerError, ErrFailedDeleteTemplate)
+		return
+	}
+
+	tx.Commit()
+	c.Status(http.StatusNoContent)
+}
@@ -4,39 +4,84 @@ import (
 	"api/database"
 	"api/middleware"
 	"api/models"
+	"api/utils/pagination"
 	"api/utils/permissions"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CreateTryRequest model for creating a try
+// The puzzle is resolved through the competition puzzle set, PuzzleID is only checked when provided
 type CreateTryRequest struct {
-	PuzzleID    string `json:"puzzle_id" binding:"required"`
-	PuzzleIndex int    `json:"puzzle_index" binding:"required"`
-	PuzzleLvl   string `json:"puzzle_lvl" binding:"required"`
+	PuzzleID    string `json:"puzzle_id"`
+	PuzzleIndex int    `json:"puzzle_index" binding:"gte=0"`
+	PuzzleLvl   string `json:"puzzle_lvl"`
 	Step        int    `json:"step" binding:"required"`
 }
 
-// UpdateTryRequest model for updating a try
-type UpdateTryRequest struct {
-	EndTime  string  `json:"end_time" binding:"required"`
-	Attempts int     `json:"attempts" binding:"required"`
-	Score    float64 `json:"score" binding:"required"`
+// parseTryTime parses a try timestamp, as sent by clients or as read back from the database
+func parseTryTime(value string) (time.Time, error) {
+	layouts := []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999", "2006-01-02T15:04:05.999999"}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid try time: %s", value)
+}
+
+// findParticipantTry loads a try of a competition that the user can play, team members share the tries of their team
+func findParticipantTry(competitionID string, tryID string, userID string) (*models.Try, error) {
+	query := database.DB.Where("id = ? AND competition_id = ?", tryID, competitionID)
+	if team := findUserTeam(competitionID, userID); team != nil {
+		query = query.Where("(user_id = ? OR team_id = ?)", userID, team.ID)
+	} else {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var try models.Try
+	if err := query.First(&try).Error; err != nil {
+		return nil, err
+	}
+	return &try, nil
+}
+
+// findStepTry loads the try of a participant for a puzzle step: the try of their team in team competitions
+// db: the connection or transaction to query with
+// teamID: team of the user in team competitions, nil otherwise
+func findStepTry(db *gorm.DB, competitionID string, userID string, teamID *string, puzzleIndex int, step int) (*models.Try, error) {
+	query := db.Where("competition_id = ? AND puzzle_index = ? AND step = ?", competitionID, puzzleIndex, step)
+	if teamID != nil {
+		query = query.Where("team_id = ?", *teamID)
+	} else {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var try models.Try
+	if err := query.First(&try).Error; err != nil {
+		return nil, err
+	}
+	return &try, nil
 }
 
 // StartCompetitionTry starts a try for a competition
 // @Summary Start a competition try
-// @Description Start a new try for a puzzle in a competition
+// @Description Start a new try for a puzzle step in a competition, or return the existing try of the participant for this step
 // @Tags Competitions
 // @Accept json
 // @Produce json
 // @Param id path string true "Competition ID"
 // @Param try body CreateTryRequest true "Try details"
+// @Success 200 {object} models.Try
 // @Success 201 {object} models.Try
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Router /competitions/{id}/tries [post]
 // @Security Bearer
@@ -47,9 +92,9 @@ func StartCompetitionTry(c *gin.Context) {
 	}
 
 	competitionID := c.Param("id")
-	
+
 	// Check if user has access to the competition
-	if !userHasAccessToCompetition(user.ID, competitionID) && !hasCompetitionPermission(user, permissions.COMPETITIONS) {
+	if !userHasAccessToCompetition(user.ID, competitionID) && !canManageCompetition(user, competitionID, permissions.COMPETITIONS) {
 		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionView)
 		return
 	}
@@ -62,7 +107,7 @@ func StartCompetitionTry(c *gin.Context) {
 
 	// Check if competition is finished
 	if competition.Finished {
-		respondWithError(c, http.StatusBadRequest, "Competition is already finished")
+		respondWithError(c, http.StatusBadRequest, ErrCompetitionFinished)
 		return
 	}
 
@@ -72,123 +117,201 @@ func StartCompetitionTry(c *gin.Context) {
 		return
 	}
 
-	// Create a new try
+	// Resolve the puzzle through the competition puzzle set
+	puzzles, err := getCompetitionPuzzles(&competition)
+	if err != nil {
+		respondSnapshotError(c, err)
+		return
+	}
+
+	puzzle, ok := findCompetitionPuzzle(puzzles, req.PuzzleIndex)
+	if !ok || (req.PuzzleID != "" && puzzle.PuzzleID != req.PuzzleID) {
+		respondWithError(c, http.StatusBadRequest, ErrPuzzleNotInSnapshot)
+		return
+	}
+
+	// In team competitions, the try is attributed to the team of the user
+	var teamID *string
+	if competition.TeamMode {
+		team := findUserTeam(competition.ID, user.ID)
+		if team == nil {
+			respondWithError(c, http.StatusBadRequest, ErrNotInTeam)
+			return
+		}
+		teamID = &team.ID
+	}
+
+	// A participant has a single try per puzzle step, starting it again returns the existing one
+	if existing, err := findStepTry(database.DB, competitionID, user.ID, teamID, puzzle.PuzzleIndex, req.Step); err == nil {
+		c.JSON(http.StatusOK, existing)
+		return
+	}
+
+	// Check the competition window and the time allowed to the participant
 	now := time.Now()
+	if err := checkCompetitionSchedule(&competition, user.ID, teamID, now); err != nil {
+		respondScheduleError(c, err)
+		return
+	}
+
+	// Create a new try
 	try := models.Try{
-		PuzzleID:      req.PuzzleID,
-		PuzzleIndex:   req.PuzzleIndex,
-		PuzzleLvl:     req.PuzzleLvl,
+		PuzzleID:      puzzle.PuzzleID,
+		PuzzleIndex:   puzzle.PuzzleIndex,
+		PuzzleLvl:     puzzle.Difficulty,
 		Step:          req.Step,
 		StartTime:     now.Format(time.RFC3339),
 		Attempts:      0,
 		Score:         0,
 		CompetitionID: competitionID,
 		UserID:        user.ID,
+		TeamID:        teamID,
 	}
 
-	if err := database.DB.Create(&try).Error; err != nil {
+	// The unique indexes on tries settle concurrent starts, the request that loses returns the winner's try
+	tx := database.DB.Begin()
+
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&try)
+	if result.Error != nil {
+		tx.Rollback()
 		respondWithError(c, http.StatusInternalServerError, "Failed to create try")
 		return
 	}
 
+	if result.RowsAffected == 0 {
+		existing, err := findStepTry(tx, competitionID, user.ID, teamID, puzzle.PuzzleIndex, req.Step)
+		tx.Rollback()
+		if err != nil {
+			respondWithError(c, http.StatusInternalServerError, "Failed to create try")
+			return
+		}
+		c.JSON(http.StatusOK, existing)
+		return
+	}
+
+	tx.Commit()
+
 	c.JSON(http.StatusCreated, try)
 }
 
-// FinishCompetitionTry finishes a try for a competition
-// @Summary Finish a competition try
-// @Description Complete an ongoing try for a puzzle in a competition
+// FinishCompetitionTry used to finish a try with the end time, attempts and score sent by the client
+// @Summary Finish a competition try (removed)
+// @Description Removed: the client could set its own score. Tries are finished and scored by the server
+// @Description when a correct answer is posted to /competitions/{id}/tries/{try_id}/answer
 // @Tags Competitions
-// @Accept json
 // @Produce json
 // @Param id path string true "Competition ID"
 // @Param try_id path string true "Try ID"
-// @Param try body UpdateTryRequest true "Try details"
-// @Success 200 {object} models.Try
+// @Failure 410 {object} map[string]string
+// @Router /competitions/{id}/tries/{try_id} [put]
+// @Security Bearer
+// @Deprecated
+func FinishCompetitionTry(c *gin.Context) {
+	respondWithError(c, http.StatusGone, ErrFinishTryRemoved)
+}
+
+// trySortKeys are the sort keys accepted by the try list
+var trySortKeys = map[string]string{
+	"start_time":   "start_time",
+	"end_time":     "end_time",
+	"score":        "score",
+	"puzzle_index": "puzzle_index",
+	"step":         "step",
+}
+
+// GetCompetitionTries retrieves all tries for a competition
+// @Summary Get all tries for a competition
+// @Description Get a page of the tries of the specified competition: all of them for staff, those of the team or of the user otherwise
+// @Tags Competitions
+// @Accept json
+// @Produce json
+// @Param id path string true "Competition ID"
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param sort query string false "Sort keys among start_time, end_time, score, puzzle_index, step, prefixed by - to sort descending"
+// @Param user_id query string false "Only tries of this user"
+// @Param team_id query string false "Only tries of this team"
+// @Param puzzle_index query int false "Only tries of this puzzle"
+// @Param step query int false "Only tries of this step"
+// @Param finished query bool false "Only finished or open tries"
+// @Success 200 {object} pagination.Page{items=[]models.Try}
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
-// @Router /competitions/{id}/tries/{try_id} [put]
+// @Router /competitions/{id}/tries [get]
 // @Security Bearer
-func FinishCompetitionTry(c *gin.Context) {
+func GetCompetitionTries(c *gin.Context) {
 	user, err := middleware.GetUserFromRequest(c)
 	if err != nil {
 		return
 	}
 
 	competitionID := c.Param("id")
-	tryID := c.Param("try_id")
 
-	var try models.Try
-	if err := database.DB.Where("id = ? AND competition_id = ? AND user_id = ?", 
-		tryID, competitionID, user.ID).First(&try).Error; err != nil {
-		respondWithError(c, http.StatusNotFound, "Try not found")
+	params, err := pagination.Parse(c, trySortKeys, "start_time")
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidListParams)
 		return
 	}
 
-	// Check if try is already finished
-	if try.EndTime != nil {
-		respondWithError(c, http.StatusBadRequest, "Try is already finished")
-		return
+	// Administrators can see all tries, other users only those of their team or their own
+	query := database.DB.Model(&models.Try{}).Where("competition_id = ?", competitionID)
+	if !canManageCompetition(user, competitionID, permissions.COMPETITIONS) {
+		if team := findUserTeam(competitionID, user.ID); team != nil {
+			// Team members see the tries of their whole team
+			query = query.Where("team_id = ?", team.ID)
+		} else {
+			// Normal users can only see their own tries
+			query = query.Where("user_id = ?", user.ID)
+		}
 	}
 
-	var req UpdateTryRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		respondWithError(c, http.StatusBadRequest, ErrInvalidRequest)
-		return
+	if userID := c.Query("user_id"); userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	if teamID := c.Query("team_id"); teamID != "" {
+		query = query.Where("team_id = ?", teamID)
 	}
 
-	// Update the try
-	try.EndTime = &req.EndTime
-	try.Attempts = req.Attempts
-	try.Score = req.Score
-
-	if err := database.DB.Save(&try).Error; err != nil {
-		respondWithError(c, http.StatusInternalServerError, "Failed to update try")
+	puzzleIndex, err := pagination.Int(c, "puzzle_index")
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidListParams)
 		return
 	}
+	if puzzleIndex != nil {
+		query = query.Where("puzzle_index = ?", *puzzleIndex)
+	}
 
-	c.JSON(http.StatusOK, try)
-}
-
-// GetCompetitionTries retrieves all tries for a competition
-// @Summary Get all tries for a competition
-// @Description Get all tries for the specified competition
-// @Tags Competitions
-// @Accept json
-// @Produce json
-// @Param id path string true "Competition ID"
-// @Success 200 {array} models.Try
-// @Failure 401 {object} map[string]string
-// @Failure 404 {object} map[string]string
-// @Router /competitions/{id}/tries [get]
-// @Security Bearer
-func GetCompetitionTries(c *gin.Context) {
-	user, err := middleware.GetUserFromRequest(c)
+	step, err := pagination.Int(c, "step")
 	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidListParams)
 		return
 	}
+	if step != nil {
+		query = query.Where("step = ?", *step)
+	}
 
-	competitionID := c.Param("id")
+	finished, err := pagination.Bool(c, "finished")
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidListParams)
+		return
+	}
+	if finished != nil {
+		if *finished {
+			query = query.Where("end_time IS NOT NULL")
+		} else {
+			query = query.Where("end_time IS NULL")
+		}
+	}
 
-	// Check if user has permission to see all tries or only their own
 	var tries []models.Try
-	if hasCompetitionPermission(user, permissions.COMPETITIONS) {
-		// Administrators can see all tries
-		if err := database.DB.Where("competition_id = ?", competitionID).
-			Preload("User").Find(&tries).Error; err != nil {
-			respondWithError(c, http.StatusInternalServerError, "Failed to fetch tries")
-			return
-		}
-	} else {
-		// Normal users can only see their own tries
-		if err := database.DB.Where("competition_id = ? AND user_id = ?", 
-			competitionID, user.ID).Find(&tries).Error; err != nil {
-			respondWithError(c, http.StatusInternalServerError, "Failed to fetch tries")
-			return
-		}
+	page, err := pagination.Find(query, params, &tries)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, "Failed to fetch tries")
+		return
 	}
 
-	c.JSON(http.StatusOK, tries)
+	c.JSON(http.StatusOK, page)
 }
 
 // GetCompetitionStatistics retrieves statistics for a competition
@@ -212,7 +335,7 @@ func GetCompetitionStatistics(c *gin.Context) {
 	competitionID := c.Param("id")
 
 	// Check if user has access to the competition
-	if !userHasAccessToCompetition(user.ID, competitionID) && !hasCompetitionPermission(user, permissions.COMPETITIONS) {
+	if !userHasAccessToCompetition(user.ID, competitionID) && !canManageCompetition(user, competitionID, permissions.COMPETITIONS) {
 		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionView)
 		return
 	}
@@ -223,50 +346,69 @@ func GetCompetitionStatistics(c *gin.Context) {
 		return
 	}
 
-	// Calculate statistics
-	var totalUsers int64
-	var activeUsers int64
+	// Calculate statistics in a single pass over the tries of the competition
+	var totals struct {
+		TotalUsers   int64
+		ActiveUsers  int64
+		TotalTeams   int64
+		ActiveTeams  int64
+		AverageScore float64
+		HighestScore float64
+	}
+	database.DB.Raw(`
+		SELECT
+			COUNT(DISTINCT user_id) AS total_users,
+			COUNT(DISTINCT user_id) FILTER (WHERE end_time IS NOT NULL) AS active_users,
+			COUNT(DISTINCT team_id) AS total_teams,
+			COUNT(DISTINCT team_id) FILTER (WHERE end_time IS NOT NULL) AS active_teams,
+			COALESCE(AVG(score) FILTER (WHERE end_time IS NOT NULL), 0) AS average_score,
+			COALESCE(MAX(score) FILTER (WHERE end_time IS NOT NULL), 0) AS highest_score
+		FROM tries
+		WHERE competition_id = ?
+	`, competitionID).Scan(&totals)
+
+	totalUsers, activeUsers := totals.TotalUsers, totals.ActiveUsers
+	averageScore, highestScore := totals.AverageScore, totals.HighestScore
+
+	// In team competitions, count teams as well
+	var totalTeams, activeTeams int64
+	if competition.TeamMode {
+		totalTeams, activeTeams = totals.TotalTeams, totals.ActiveTeams
+	}
+
+	// Calculate completion rate
 	var completionRate float64
-	var averageScore float64
-	var highestScore float64
-
-	// Total number of users who have at least one try
-	database.DB.Model(&models.Try{}).
-		Select("COUNT(DISTINCT user_id)").
-		Where("competition_id = ?", competitionID).
-		Count(&totalUsers)
-
-	// Number of active users (who have at least one completed try)
-	database.DB.Model(&models.Try{}).
-		Select("COUNT(DISTINCT user_id)").
-		Where("competition_id = ? AND end_time IS NOT NULL", competitionID).
-		Count(&activeUsers)
-
-	// Calculate completion rate, average score, and highest score
 	if totalUsers > 0 {
 		completionRate = float64(activeUsers) / float64(totalUsers) * 100
-		
-		// Average score
-		database.DB.Model(&models.Try{}).
-			Select("COALESCE(AVG(score), 0)").
-			Where("competition_id = ? AND end_time IS NOT NULL", competitionID).
-			Scan(&averageScore)
-		
-		// Highest score
-		database.DB.Model(&models.Try{}).
-			Select("COALESCE(MAX(score), 0)").
-			Where("competition_id = ? AND end_time IS NOT NULL", competitionID).
-			Scan(&highestScore)
+		if competition.TeamMode && totalTeams > 0 {
+			completionRate = float64(activeTeams) / float64(totalTeams) * 100
+		}
+	}
+
+	// Attempts are counted from the logged submissions, not from the counter reported by clients
+	var attempts struct {
+		Total   int64
+		Average float64
 	}
+	database.DB.Raw(`
+		SELECT COUNT(*) AS total, COALESCE(COUNT(*)::float / NULLIF(COUNT(DISTINCT try_id), 0), 0) AS average
+		FROM submissions
+		WHERE competition_id = ?
+	`, competitionID).Scan(&attempts)
 
 	stats := CompetitionStatsResponse{
-		CompetitionID:  competitionID,
-		Title:          competition.Title,
-		TotalUsers:     int(totalUsers),
-		ActiveUsers:    int(activeUsers),
-		CompletionRate: completionRate,
-		AverageScore:   averageScore,
-		HighestScore:   highestScore,
+		CompetitionID:   competitionID,
+		Title:           competition.Title,
+		TotalUsers:      int(totalUsers),
+		ActiveUsers:     int(activeUsers),
+		CompletionRate:  completionRate,
+		AverageScore:    averageScore,
+		HighestScore:    highestScore,
+		TeamMode:        competition.TeamMode,
+		TotalTeams:      int(totalTeams),
+		ActiveTeams:     int(activeTeams),
+		TotalAttempts:   int(attempts.Total),
+		AverageAttempts: attempts.Average,
 	}
 
 	c.JSON(http.StatusOK, stats)
@@ -295,13 +437,13 @@ func GetUserCompetitionTries(c *gin.Context) {
 	targetUserID := c.Param("user_id")
 
 	// Check if user has permission to view others' tries
-	if user.ID != targetUserID && !hasCompetitionPermission(user, permissions.COMPETITIONS) {
+	if user.ID != targetUserID && !canManageCompetition(user, competitionID, permissions.COMPETITIONS) {
 		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionViewTries)
 		return
 	}
 
 	var tries []models.Try
-	if err := database.DB.Where("competition_id = ? AND user_id = ?", 
+	if err := database.DB.Where("competition_id = ? AND user_id = ?",
 		competitionID, targetUserID).Find(&tries).Error; err != nil {
 		respondWithError(c, http.StatusInternalServerError, "Failed to fetch tries")
 		return
@@ -21,19 +21,57 @@ func RegisterRoutes(r *gin.RouterGroup) {
 		competitions.PUT("/:id/finish", FinishCompetition)
 		competitions.PUT("/:id/visibility", ToggleCompetitionVisibility)
 		competitions.DELETE("/:id", DeleteCompetition)
-		
-		 // Competition group management routes
+		competitions.POST("/:id/clone", CloneCompetition)
+		competitions.POST("/:id/template", SaveCompetitionAsTemplate)
+
+		// Competition template routes
+		competitions.GET("/templates", GetCompetitionTemplates)
+		competitions.POST("/templates", CreateCompetitionTemplate)
+		competitions.GET("/templates/:template_id", GetCompetitionTemplate)
+		competitions.DELETE("/templates/:template_id", DeleteCompetitionTemplate)
+		competitions.POST("/templates/:template_id/instantiate", InstantiateCompetitionTemplate)
+
+		// Competition group management routes
 		competitions.GET("/:id/groups", GetCompetitionGroups)
 		competitions.POST("/:id/groups/:group_id", AddGroupToCompetition)
 		competitions.DELETE("/:id/groups/:group_id", RemoveGroupFromCompetition)
-		
-		 // Try management routes
+
+		// Puzzle snapshot routes
+		competitions.GET("/:id/puzzles", GetCompetitionPuzzles)
+		competitions.GET("/:id/puzzles/diff", DiffCompetitionPuzzles)
+		competitions.GET("/:id/puzzles/:puzzle_index/input", GetCompetitionPuzzleInput)
+
+		// Team management routes
+		competitions.GET("/:id/teams", GetCompetitionTeams)
+		competitions.GET("/:id/teams/me", GetMyCompetitionTeam)
+		competitions.POST("/:id/teams", CreateCompetitionTeam)
+		competitions.POST("/:id/teams/group/:group_id", CreateCompetitionTeamFromGroup)
+		competitions.DELETE("/:id/teams/:team_id", DeleteCompetitionTeam)
+		competitions.POST("/:id/teams/:team_id/members/:user_id", AddTeamMember)
+		competitions.DELETE("/:id/teams/:team_id/members/:user_id", RemoveTeamMember)
+
+		// Time override routes
+		competitions.GET("/:id/schedule", GetMyCompetitionSchedule)
+		competitions.GET("/:id/overrides", GetCompetitionOverrides)
+		competitions.PUT("/:id/overrides/users/:user_id", SetUserOverride)
+		competitions.PUT("/:id/overrides/groups/:group_id", SetGroupOverride)
+		competitions.DELETE("/:id/overrides/:override_id", DeleteCompetitionOverride)
+
+		// Try management routes
 		competitions.GET("/:id/tries", GetCompetitionTries)
 		competitions.POST("/:id/tries", StartCompetitionTry)
 		competitions.PUT("/:id/tries/:try_id", FinishCompetitionTry)
+		competitions.POST("/:id/tries/:try_id/answer", SubmitTryAnswer)
+		competitions.GET("/:id/tries/:try_id/submissions", GetTrySubmissions)
 		competitions.GET("/:id/users/:user_id/tries", GetUserCompetitionTries)
-		
-		 // Statistics routes
+
+		// Statistics routes
 		competitions.GET("/:id/statistics", GetCompetitionStatistics)
+		competitions.GET("/:id/analytics", GetCompetitionAnalytics)
+		competitions.GET("/:id/leaderboard", GetCompetitionLeaderboard)
+
+		// Anti-cheat routes
+		competitions.POST("/:id/anticheat/analyze", AnalyzeCompetitionSubmissions)
+		competitions.GET("/:id/anticheat/report", GetCompetitionCheatReport)
 	}
 }
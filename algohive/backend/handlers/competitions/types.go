@@ -1,6 +1,9 @@
 package competitions
 
 import (
+	"api/models"
+	"time"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -16,47 +19,296 @@ const (
 	ErrNoPermissionManageGroups = "User does not have permission to manage competition groups"
 	ErrNoPermissionViewTries    = "User does not have permission to view competition tries"
 	ErrFailedFetchCompetitions  = "Failed to fetch competitions"
+	ErrInvalidListParams        = "Invalid pagination, sort or filter parameters"
 	ErrFailedCreateCompetition  = "Failed to create competition"
 	ErrFailedUpdateCompetition  = "Failed to update competition"
 	ErrFailedDeleteCompetition  = "Failed to delete competition"
 	ErrInvalidRequest           = "Invalid request data"
 	ErrFailedAddGroup           = "Failed to add group to competition"
 	ErrFailedRemoveGroup        = "Failed to remove group from competition"
-	ErrNoPermissionFinish	  = "User does not have permission to finish competitions"
-	ErrFailedToggleFinished	  = "Failed to toggle competition finished status"
-	ErrNoPermissionVisibility	  = "User does not have permission to change competition visibility"
-	ErrFailedToggleVisibility	  = "Failed to toggle competition visibility"
+	ErrNoPermissionFinish       = "User does not have permission to finish competitions"
+	ErrFailedToggleFinished     = "Failed to toggle competition finished status"
+	ErrNoPermissionVisibility   = "User does not have permission to change competition visibility"
+	ErrFailedToggleVisibility   = "Failed to toggle competition visibility"
+	ErrCatalogThemeNotFound     = "Theme not found in the catalog"
+	ErrFailedSnapshot           = "Failed to snapshot competition puzzles"
+	ErrPuzzleNotInSnapshot      = "Puzzle does not match the competition puzzle list"
+	ErrCompetitionHasTries      = "Cannot change the puzzles of a competition that already has tries"
+	ErrPuzzleNotInCatalog       = "Puzzle not found in the catalog theme"
+	ErrInvalidPuzzleConfig      = "Puzzle weight and time limit must be positive"
+	ErrTimeLimitExceeded        = "Time limit exceeded for this puzzle"
+	ErrNoPermissionManageTeams  = "User does not have permission to manage competition teams"
+	ErrNotTeamCompetition       = "Competition is not a team competition"
+	ErrTeamModeHasTries         = "Cannot change the team mode of a competition that already has tries"
+	ErrNotInTeam                = "User is not part of a team in this competition"
+	ErrTeamNotFound             = "Team not found"
+	ErrUserNotFound             = "User not found"
+	ErrUserAlreadyInTeam        = "User is already part of a team in this competition"
+	ErrUserNotParticipant       = "Team members must take part in the competition through one of its groups"
+	ErrNoPermissionTeamGroup    = "The group is neither a group of the competition nor managed by the user"
+	ErrTeamHasTries             = "Cannot delete a team that already has tries"
+	ErrFailedFetchTeams         = "Failed to fetch teams"
+	ErrFailedCreateTeam         = "Failed to create team"
+	ErrFailedUpdateTeam         = "Failed to update team"
+	ErrFailedDeleteTeam         = "Failed to delete team"
+	ErrFailedGenerateInput      = "Failed to generate puzzle input"
+	ErrFailedFetchLeaderboard   = "Failed to fetch leaderboard"
+	ErrInvalidSchedule          = "Competition must end after it starts and its duration must be positive"
+	ErrCompetitionNotStarted    = "Competition has not started yet"
+	ErrCompetitionEnded         = "Competition has ended"
+	ErrCompetitionFinished      = "Competition is already finished"
+	ErrFinishTryRemoved         = "Tries are finished by posting the answer to /competitions/{id}/tries/{try_id}/answer"
+	ErrDurationExceeded         = "Competition duration exceeded"
+	ErrFailedCloneCompetition   = "Failed to clone competition"
+	ErrTemplateNotFound         = "Competition template not found"
+	ErrFailedFetchTemplates     = "Failed to fetch competition templates"
+	ErrFailedCreateTemplate     = "Failed to create competition template"
+	ErrFailedDeleteTemplate     = "Failed to delete competition template"
+	ErrOverrideNotFound         = "Time override not found"
+	ErrFailedFetchOverrides     = "Failed to fetch time overrides"
+	ErrFailedSaveOverride       = "Failed to save time override"
+	ErrOverrideNotParticipant   = "The user does not take part in the competition through one of its groups"
+	ErrFailedDeleteOverride     = "Failed to delete time override"
+	ErrTryNotFound              = "Try not found"
+	ErrStepAlreadySolved        = "This puzzle step is already solved"
+	ErrFailedSaveSubmission     = "Failed to save submission"
+	ErrFailedFetchReport        = "Failed to fetch anti-cheat report"
+	ErrFailedFetchSubmissions   = "Failed to fetch submissions"
+	ErrFailedFetchAnalytics     = "Failed to compute competition analytics"
+	ErrAnalysisRunning          = "An anti-cheat analysis is already running for this competition"
+)
+
+// Statuses of a puzzle when diffing a competition snapshot against the live catalog
+const (
+	DiffUnchanged = "unchanged"
+	DiffModified  = "modified"
+	DiffRemoved   = "removed"
 )
 
+// CompetitionPuzzleRequest modèle pour un puzzle d'une compétition, dans l'ordre de la compétition
+type CompetitionPuzzleRequest struct {
+	CatalogID string   `json:"catalog_id" binding:"required"`
+	Theme     string   `json:"theme" binding:"required"`
+	PuzzleID  string   `json:"puzzle_id" binding:"required"`
+	Weight    *float64 `json:"weight"`
+	TimeLimit *int     `json:"time_limit"`
+}
+
 // CreateCompetitionRequest modèle pour créer une compétition
 type CreateCompetitionRequest struct {
-	Title           string   `json:"title" binding:"required"`
-	Description     string   `json:"description" binding:"required"`
-	CatalogTheme    string   `json:"catalog_theme" binding:"required"`
-	CatalogID       string   `json:"catalog_id" binding:"required"`
-	GroupIds        []string `json:"group_ids"`
-	Show            bool     `json:"show"`
+	Title       string                     `json:"title" binding:"required"`
+	Description string                     `json:"description" binding:"required"`
+	Puzzles     []CompetitionPuzzleRequest `json:"puzzles" binding:"required,min=1,dive"`
+	GroupIds    []string                   `json:"group_ids"`
+	Show        bool                       `json:"show"`
+	TeamMode    bool                       `json:"team_mode"`
+	StartsAt    *time.Time                 `json:"starts_at"`
+	EndsAt      *time.Time                 `json:"ends_at"`
+	Duration    *int                       `json:"duration"`
 }
 
 // UpdateCompetitionRequest modèle pour mettre à jour une compétition
 type UpdateCompetitionRequest struct {
-	Title           string   `json:"title"`
-	Description     string   `json:"description"`
-	CatalogTheme    string   `json:"catalog_theme"`
-	CatalogID       string   `json:"catalog_id"`
-	Finished        *bool    `json:"finished"`
-	Show            *bool    `json:"show"`
+	Title       string                     `json:"title"`
+	Description string                     `json:"description"`
+	Puzzles     []CompetitionPuzzleRequest `json:"puzzles" binding:"omitempty,min=1,dive"`
+	Finished    *bool                      `json:"finished"`
+	Show        *bool                      `json:"show"`
+	TeamMode    *bool                      `json:"team_mode"`
+	StartsAt    *time.Time                 `json:"starts_at"`
+	EndsAt      *time.Time                 `json:"ends_at"`
+	Duration    *int                       `json:"duration"`
+}
+
+// CloneCompetitionRequest model for cloning a competition
+// GroupMapping maps groups of the source competition to the groups of the clone, unmapped groups are kept
+type CloneCompetitionRequest struct {
+	Title        string            `json:"title" binding:"required"`
+	Description  string            `json:"description"`
+	StartsAt     *time.Time        `json:"starts_at"`
+	EndsAt       *time.Time        `json:"ends_at"`
+	GroupMapping map[string]string `json:"group_mapping"`
+	Show         bool              `json:"show"`
+}
+
+// TimeOverrideRequest model for overriding the schedule of a competition for a user or a group
+type TimeOverrideRequest struct {
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+	Duration *int       `json:"duration"`
+}
+
+// SubmitAnswerRequest model for submitting the answer of a try
+type SubmitAnswerRequest struct {
+	Answer string `json:"answer" binding:"required"`
+}
+
+// SubmitAnswerResponse model for the result of an answer check
+type SubmitAnswerResponse struct {
+	Correct bool       `json:"correct"`
+	Try     models.Try `json:"try"`
+}
+
+// CheatReportEntry model for a pair of users flagged by the anti-cheat analysis, with the evidence
+type CheatReportEntry struct {
+	UserID        string             `json:"user_id"`
+	UserName      string             `json:"user_name"`
+	OtherUserID   string             `json:"other_user_id"`
+	OtherUserName string             `json:"other_user_name"`
+	Flags         []models.CheatFlag `json:"flags"`
+}
+
+// ScheduleResponse model for the schedule that applies to a user, overrides included
+type ScheduleResponse struct {
+	CompetitionID string     `json:"competition_id"`
+	StartsAt      *time.Time `json:"starts_at"`
+	EndsAt        *time.Time `json:"ends_at"`
+	Duration      *int       `json:"duration"`
+	StartedAt     *time.Time `json:"started_at"`
+	Overridden    bool       `json:"overridden"`
+}
+
+// CreateTemplateRequest model for creating a competition template
+type CreateTemplateRequest struct {
+	Name        string                     `json:"name" binding:"required"`
+	Title       string                     `json:"title" binding:"required"`
+	Description string                     `json:"description" binding:"required"`
+	Puzzles     []CompetitionPuzzleRequest `json:"puzzles" binding:"required,min=1,dive"`
+	TeamMode    bool                       `json:"team_mode"`
+	Duration    *int                       `json:"duration"`
+}
+
+// SaveTemplateRequest model for saving an existing competition as a template
+type SaveTemplateRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// InstantiateTemplateRequest model for creating a competition from a template
+type InstantiateTemplateRequest struct {
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+	GroupIds    []string   `json:"group_ids"`
+	Show        bool       `json:"show"`
 }
 
 // CompetitionStatsResponse modèle pour les statistiques d'une compétition
 type CompetitionStatsResponse struct {
-	CompetitionID   string `json:"competition_id"`
-	Title           string `json:"title"`
-	TotalUsers      int    `json:"total_users"`
-	ActiveUsers     int    `json:"active_users"`
+	CompetitionID   string  `json:"competition_id"`
+	Title           string  `json:"title"`
+	TotalUsers      int     `json:"total_users"`
+	ActiveUsers     int     `json:"active_users"`
 	CompletionRate  float64 `json:"completion_rate"`
 	AverageScore    float64 `json:"average_score"`
 	HighestScore    float64 `json:"highest_score"`
+	TeamMode        bool    `json:"team_mode"`
+	TotalTeams      int     `json:"total_teams"`
+	ActiveTeams     int     `json:"active_teams"`
+	TotalAttempts   int     `json:"total_attempts"`
+	AverageAttempts float64 `json:"average_attempts"`
+}
+
+// PuzzleStepAnalytics model for the solve data of one step of a competition puzzle.
+// Solve times are in seconds, the solve rate is a percentage of the participants
+type PuzzleStepAnalytics struct {
+	PuzzleIndex          int        `json:"puzzle_index"`
+	PuzzleID             string     `json:"puzzle_id"`
+	Name                 string     `json:"name"`
+	Step                 int        `json:"step"`
+	Tries                int        `json:"tries"`
+	SolveCount           int        `json:"solve_count"`
+	SolveRate            float64    `json:"solve_rate"`
+	MedianSolveTime      float64    `json:"median_solve_time"`
+	P90SolveTime         float64    `json:"p90_solve_time"`
+	AverageWrongAttempts float64    `json:"average_wrong_attempts"`
+	FirstSolverID        *string    `json:"first_solver_id"`
+	FirstSolverName      *string    `json:"first_solver_name"`
+	FirstSolvedAt        *time.Time `json:"first_solved_at"`
+}
+
+// SolvesPerMinute model for one point of the solve time series of a competition
+type SolvesPerMinute struct {
+	Minute time.Time `json:"minute"`
+	Solves int       `json:"solves"`
+}
+
+// GroupAnalytics model for the participation of one group of a competition, the members of its subgroups included
+type GroupAnalytics struct {
+	GroupID      string  `json:"group_id"`
+	GroupName    string  `json:"group_name"`
+	Members      int     `json:"members"`
+	Participants int     `json:"participants"`
+	ActiveUsers  int     `json:"active_users"`
+	Solves       int     `json:"solves"`
+	AverageScore float64 `json:"average_score"`
+}
+
+// CompetitionAnalyticsResponse model for the detailed analytics of a competition
+type CompetitionAnalyticsResponse struct {
+	CompetitionID string                `json:"competition_id"`
+	Participants  int                   `json:"participants"`
+	Puzzles       []PuzzleStepAnalytics `json:"puzzles"`
+	Timeline      []SolvesPerMinute     `json:"timeline"`
+	Groups        []GroupAnalytics      `json:"groups"`
+	GeneratedAt   time.Time             `json:"generated_at"`
+}
+
+// CreateTeamRequest model for creating a team in a competition
+type CreateTeamRequest struct {
+	Name    string   `json:"name" binding:"required"`
+	UserIDs []string `json:"user_ids"`
+}
+
+// TeamMember model for the public profile of a team member
+type TeamMember struct {
+	ID        string `json:"id"`
+	Firstname string `json:"firstname"`
+	Lastname  string `json:"lastname"`
+}
+
+// TeamResponse model for a team with its members
+type TeamResponse struct {
+	ID            string       `json:"id"`
+	Name          string       `json:"name"`
+	CompetitionID string       `json:"competition_id"`
+	GroupID       *string      `json:"group_id"`
+	Members       []TeamMember `json:"members"`
+}
+
+// PuzzleInputResponse model for the generated input of a competition puzzle
+type PuzzleInputResponse struct {
+	PuzzleIndex int      `json:"puzzle_index"`
+	PuzzleID    string   `json:"puzzle_id"`
+	InputLines  []string `json:"input_lines"`
+}
+
+// LeaderboardEntry model for one row of a competition leaderboard, a user or a team
+type LeaderboardEntry struct {
+	Rank      int     `json:"rank"`
+	EntryID   string  `json:"entry_id"`
+	Name      string  `json:"name"`
+	IsTeam    bool    `json:"is_team"`
+	Score     float64 `json:"score"`
+	Solved    int     `json:"solved"`
+	LastSolve *string `json:"last_solve"`
+}
+
+// SnapshotDiffEntry compares one snapshotted puzzle with its live counterpart
+type SnapshotDiffEntry struct {
+	PuzzleIndex int                       `json:"puzzle_index"`
+	PuzzleID    string                    `json:"puzzle_id"`
+	Status      string                    `json:"status"`
+	Changes     []string                  `json:"changes,omitempty"`
+	Snapshot    *models.CompetitionPuzzle `json:"snapshot,omitempty"`
+	Live        *models.CompetitionPuzzle `json:"live,omitempty"`
+}
+
+// SnapshotDiffResponse model for the diff between a competition snapshot and the live catalogs
+type SnapshotDiffResponse struct {
+	CompetitionID string              `json:"competition_id"`
+	InSync        bool                `json:"in_sync"`
+	Entries       []SnapshotDiffEntry `json:"entries"`
 }
 
 // respondWithError envoie une réponse d'erreur standardisée
@@ -0,0 +1,80 @@
+package groups
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/utils/lifecycle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ExtendGroupExpiry sets the expiry date of a group and of its members
+// @Summary Extend the expiry of a group
+// @Description Set the expiry date of a group (null: never expires), optionally extending the members whose own expiry date is earlier.
+// @Description Members blocked by the expiry job are unblocked once their account is no longer expired
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param group_id path string true "Group ID"
+// @Param expiry body ExtendGroupExpiryRequest true "New expiry date"
+// @Success 200 {object} ExtendGroupExpiryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /groups/{group_id}/expiry [put]
+// @Security Bearer
+func ExtendGroupExpiry(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	groupID := c.Param("group_id")
+	var group models.Group
+	if err := database.DB.Where("id = ?", groupID).First(&group).Error; err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrGroupNotFound)
+		return
+	}
+
+	if !userCanManageGroup(user, &group) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionUpdate)
+		return
+	}
+
+	var req ExtendGroupExpiryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := ExtendGroupExpiryResponse{GroupID: group.ID, ExpiresAt: req.ExpiresAt}
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&group).Update("expires_at", req.ExpiresAt).Error; err != nil {
+			return err
+		}
+
+		if req.ExtendMembers {
+			members := tx.Table("user_groups").Select("user_id").Where("group_id = ?", group.ID)
+			query := tx.Model(&models.User{}).Where("id IN (?) AND expires_at IS NOT NULL", members)
+			if req.ExpiresAt != nil {
+				query = query.Where("expires_at < ?", *req.ExpiresAt)
+			}
+			result := query.Update("expires_at", req.ExpiresAt)
+			if result.Error != nil {
+				return result.Error
+			}
+			response.Extended = result.RowsAffected
+		}
+
+		reactivated, err := lifecycle.ReactivateMembers(tx, group.ID)
+		response.Reactivated = reactivated
+		return err
+	}); err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedExtendExpiry)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
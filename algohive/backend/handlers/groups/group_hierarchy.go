@@ -0,0 +1,37 @@
+package groups
+
+import (
+	"api/database"
+	"api/models"
+	"api/utils/hierarchy"
+	"net/http"
+)
+
+// checkParentGroup checks that a group can be nested under a parent group
+// user: the authenticated user, who must manage the parent group
+// groupID: ID of the nested group, empty for a group being created
+// parentID: ID of the parent group
+// returns: the HTTP status and error message, or 0 when the group can be nested
+func checkParentGroup(user models.User, groupID string, parentID string) (int, string) {
+	var parent models.Group
+	if err := database.DB.Where("id = ?", parentID).First(&parent).Error; err != nil {
+		return http.StatusBadRequest, ErrParentNotFound
+	}
+
+	if !userCanManageGroup(user, &parent) {
+		return http.StatusUnauthorized, ErrNoPermissionParent
+	}
+
+	// The parent cannot be the group itself or one of its descendants
+	if groupID != "" {
+		cycle, err := hierarchy.IsInSubtree(groupID, parentID)
+		if err != nil {
+			return http.StatusInternalServerError, "Failed to update group"
+		}
+		if cycle {
+			return http.StatusBadRequest, ErrParentCycle
+		}
+	}
+
+	return 0, ""
+}
@@ -0,0 +1,253 @@
+package groups
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// joinCodeAlphabet leaves out the characters easily mistaken for one another (0/O, 1/I).
+// Its 32 characters divide 256, so picking them from random bytes is not biased
+const joinCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// joinCodeLength is the number of characters of a join code
+const joinCodeLength = 8
+
+// errJoinCodeUnusable is returned when a join code has expired or reached its maximum uses
+var errJoinCodeUnusable = errors.New("join code unusable")
+
+// generateJoinCode draws a random join code
+func generateJoinCode() (string, error) {
+	code := make([]byte, joinCodeLength)
+	if _, err := rand.Read(code); err != nil {
+		return "", err
+	}
+	for i := range code {
+		code[i] = joinCodeAlphabet[int(code[i])%len(joinCodeAlphabet)]
+	}
+	return string(code), nil
+}
+
+// findManagedGroup loads a group the authenticated user manages
+// returns: false once the error response is sent
+func findManagedGroup(c *gin.Context, user models.User, group *models.Group) bool {
+	if err := database.DB.Where("id = ?", c.Param("group_id")).First(group).Error; err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrGroupNotFound)
+		return false
+	}
+
+	if !userCanManageGroup(user, group) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionUpdate)
+		return false
+	}
+	return true
+}
+
+// GetGroupJoinCode retrieves the join code of a group
+// @Summary Get the join code of a group
+// @Description Get the join code of a group with its limits and number of uses
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param group_id path string true "Group ID"
+// @Success 200 {object} models.GroupJoinCode
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /groups/{group_id}/join-code [get]
+// @Security Bearer
+func GetGroupJoinCode(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	var group models.Group
+	if !findManagedGroup(c, user, &group) {
+		return
+	}
+
+	var joinCode models.GroupJoinCode
+	if err := database.DB.Where("group_id = ?", group.ID).First(&joinCode).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrJoinCodeNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, joinCode)
+}
+
+// RegenerateGroupJoinCode creates a new join code for a group
+// @Summary Generate the join code of a group
+// @Description Generate a new join code for a group, optionally limited in time and uses. The previous code of the group stops working
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param group_id path string true "Group ID"
+// @Param limits body JoinCodeRequest false "Expiry date and maximum uses of the code"
+// @Success 201 {object} models.GroupJoinCode
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /groups/{group_id}/join-code [post]
+// @Security Bearer
+func RegenerateGroupJoinCode(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	var group models.Group
+	if !findManagedGroup(c, user, &group) {
+		return
+	}
+
+	var req JoinCodeRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondWithError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	code, err := generateJoinCode()
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedJoinCode)
+		return
+	}
+
+	joinCode := models.GroupJoinCode{
+		GroupID:   group.ID,
+		Code:      code,
+		ExpiresAt: req.ExpiresAt,
+		MaxUses:   req.MaxUses,
+		CreatedBy: &user.ID,
+		CreatedAt: time.Now(),
+	}
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("group_id = ?", group.ID).Delete(&models.GroupJoinCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&joinCode).Error
+	}); err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedJoinCode)
+		return
+	}
+
+	c.JSON(http.StatusCreated, joinCode)
+}
+
+// DisableGroupJoinCode removes the join code of a group
+// @Summary Disable the join code of a group
+// @Description Remove the join code of a group, users can no longer join it by themselves
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param group_id path string true "Group ID"
+// @Success 204 {object} string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /groups/{group_id}/join-code [delete]
+// @Security Bearer
+func DisableGroupJoinCode(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	var group models.Group
+	if !findManagedGroup(c, user, &group) {
+		return
+	}
+
+	if err := database.DB.Where("group_id = ?", group.ID).Delete(&models.GroupJoinCode{}).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedJoinCode)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// JoinGroup adds the authenticated user to the group of a join code
+// @Summary Join a group with a code
+// @Description Add the authenticated user to the group of a join code. A member of the group joining again does not use the code
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param join body JoinGroupRequest true "Join code"
+// @Success 200 {object} JoinGroupResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /groups/join [post]
+// @Security Bearer
+func JoinGroup(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	var req JoinGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Codes and groups are looked up the same way, so an invalid code tells nothing about the existing ones
+	var joinCode models.GroupJoinCode
+	var group models.Group
+	if err := database.DB.Where("code = ?", strings.ToUpper(strings.TrimSpace(req.Code))).First(&joinCode).Error; err != nil ||
+		database.DB.Where("id = ?", joinCode.GroupID).First(&group).Error != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidJoinCode)
+		return
+	}
+
+	if group.ExpiresAt != nil && !group.ExpiresAt.After(time.Now()) {
+		respondWithError(c, http.StatusForbidden, ErrGroupExpired)
+		return
+	}
+
+	response := JoinGroupResponse{GroupID: group.ID, GroupName: group.Name}
+
+	var memberships int64
+	if err := database.DB.Table("user_groups").Where("group_id = ? AND user_id = ?", group.ID, user.ID).
+		Count(&memberships).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedJoinGroup)
+		return
+	}
+	if memberships > 0 {
+		response.AlreadyMember = true
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// The use is counted only while the code is still valid, concurrent joins cannot exceed max_uses
+		result := tx.Exec(`
+			UPDATE group_join_codes SET uses = uses + 1
+			WHERE id = ? AND (expires_at IS NULL OR expires_at > ?) AND (max_uses IS NULL OR uses < max_uses)
+		`, joinCode.ID, time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errJoinCodeUnusable
+		}
+
+		return tx.Exec("INSERT INTO user_groups (user_id, group_id) VALUES (?, ?) ON CONFLICT DO NOTHING", user.ID, group.ID).Error
+	}); err != nil {
+		if errors.Is(err, errJoinCodeUnusable) {
+			respondWithError(c, http.StatusBadRequest, ErrInvalidJoinCode)
+			return
+		}
+		respondWithError(c, http.StatusInternalServerError, ErrFailedJoinGroup)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
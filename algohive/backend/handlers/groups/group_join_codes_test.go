@@ -0,0 +1,32 @@
+package groups
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateJoinCode(t *testing.T) {
+	if 256%len(joinCodeAlphabet) != 0 {
+		t.Fatalf("the alphabet has %d characters, a divisor of 256 is required to stay unbiased", len(joinCodeAlphabet))
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		code, err := generateJoinCode()
+		if err != nil {
+			t.Fatalf("generateJoinCode() error = %v", err)
+		}
+		if len(code) != joinCodeLength {
+			t.Fatalf("generateJoinCode() = %q, want %d characters", code, joinCodeLength)
+		}
+		for _, r := range code {
+			if !strings.ContainsRune(joinCodeAlphabet, r) {
+				t.Fatalf("generateJoinCode() = %q, %q is not in the alphabet", code, r)
+			}
+		}
+		if seen[code] {
+			t.Fatalf("generateJoinCode() drew %q twice", code)
+		}
+		seen[code] = true
+	}
+}
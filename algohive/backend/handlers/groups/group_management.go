@@ -4,19 +4,34 @@ import (
 	"api/database"
 	"api/middleware"
 	"api/models"
+	"api/utils/hierarchy"
+	"api/utils/pagination"
 	"api/utils/permissions"
+	"api/utils/trash"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
+// groupSortKeys are the sort keys accepted by the group list
+var groupSortKeys = map[string]string{
+	"name": "name",
+}
+
 // GetAllGroups retrieves all groups
 // @Summary Get all groups
-// @Description Get all groups, only accessible to users with the GROUPS permission
+// @Description Get a page of the groups of the scopes where the user holds the GROUPS permission, every group for owners
 // @Tags Groups
 // @Accept json
 // @Produce json
-// @Success 200 {array} models.Group
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param sort query string false "Sort key (name), prefixed by - to sort descending"
+// @Param q query string false "Text searched in the name and description"
+// @Param scope_id query string false "Only groups of this scope"
+// @Success 200 {object} pagination.Page{items=[]models.Group}
+// @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Router /groups [get]
 // @Security Bearer
@@ -26,23 +41,38 @@ func GetAllGroups(c *gin.Context) {
 		return
 	}
 
-	if !permissions.RolesHavePermission(user.Roles, permissions.GROUPS) {
+	if !permissions.IsStaff(user) {
 		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionView)
 		return
 	}
 
+	params, err := pagination.Parse(c, groupSortKeys, "name")
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidListParams)
+		return
+	}
+
+	query := pagination.Search(database.DB.Model(&models.Group{}), c.Query("q"), "name", "description")
+	if !permissions.IsOwner(user) {
+		query = query.Where("scope_id IN (?)", hierarchy.ScopesWith(user.ID, permissions.GROUPS))
+	}
+	if scopeID := c.Query("scope_id"); scopeID != "" {
+		query = query.Where("scope_id = ?", scopeID)
+	}
+
 	var groups []models.Group
-	if err := database.DB.Find(&groups).Error; err != nil {
+	page, err := pagination.Find(query, params, &groups)
+	if err != nil {
 		respondWithError(c, http.StatusInternalServerError, ErrFetchingGroups)
 		return
 	}
 	
-	c.JSON(http.StatusOK, groups)
+	c.JSON(http.StatusOK, page)
 }
 
 // GetGroup retrieves a group by ID
 // @Summary Get a group
-// @Description Get a group
+// @Description Get a group with its direct subgroups
 // @Tags Groups
 // @Accept json
 // @Produce json
@@ -59,12 +89,12 @@ func GetGroup(c *gin.Context) {
 
 	groupID := c.Param("group_id")
 	var group models.Group
-	if err := database.DB.Where("id = ?", groupID).Preload("Users").Preload("Competitions").First(&group).Error; err != nil {
+	if err := database.DB.Where("id = ?", groupID).Preload("Users").Preload("Competitions").Preload("Children").First(&group).Error; err != nil {
 		respondWithError(c, http.StatusBadRequest, ErrGroupNotFound)
 		return
 	}
 
-	if !userCanManageGroup(user.ID, &group) && !permissions.RolesHavePermission(user.Roles, permissions.OWNER) {
+	if !userCanViewGroup(user, &group) {
 		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionViewGroup)
 		return
 	}
@@ -74,7 +104,7 @@ func GetGroup(c *gin.Context) {
 
 // CreateGroup creates a new group
 // @Summary Create a group
-// @Description Create a group, only accessible to users with the GROUPS permission
+// @Description Create a group, optionally nested under a parent group the user manages, only accessible to users with the GROUPS permission in the scope
 // @Tags Groups
 // @Accept json
 // @Produce json
@@ -114,11 +144,26 @@ func CreateGroup(c *gin.Context) {
 		return
 	}
 
+	// Verify that the roles of the user grant the GROUPS permission in the scope
+	if !hasGroupPermission(user, req.ScopeId, permissions.GROUPS) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionCreate)
+		return
+	}
+
+	if req.ParentID != nil {
+		if status, message := checkParentGroup(user, "", *req.ParentID); status != 0 {
+			respondWithError(c, status, message)
+			return
+		}
+	}
+
 	// Create the group
 	group := models.Group{
 		Name:        req.Name,
 		Description: req.Description,
 		ScopeID:     req.ScopeId,
+		ParentID:    req.ParentID,
+		ExpiresAt:   req.ExpiresAt,
 	}
 	
 	if err := database.DB.Create(&group).Error; err != nil {
@@ -129,9 +174,9 @@ func CreateGroup(c *gin.Context) {
 	c.JSON(http.StatusCreated, group)
 }
 
-// DeleteGroup deletes a group and cascades deletion of all its users and competitions
-// @Summary Delete a group and cascade delete all users and competitions associated with the group
-// @Description Delete a group and cascade delete all users and competitions associated with the group
+// DeleteGroup moves a group to the trash
+// @Summary Move a group to the trash
+// @Description Move a group to the trash, its users and competitions are detached until it is restored
 // @Tags Groups
 // @Accept json
 // @Produce json
@@ -149,45 +194,30 @@ func DeleteGroup(c *gin.Context) {
 
 	groupID := c.Param("group_id")
 	var group models.Group
-	if err := database.DB.Where("id = ?", groupID).Preload("Users").Preload("Competitions").First(&group).Error; err != nil {
+	if err := database.DB.Where("id = ?", groupID).First(&group).Error; err != nil {
 		respondWithError(c, http.StatusBadRequest, ErrGroupNotFound)
 		return
 	}
 
-	if !userCanManageGroup(user.ID, &group) && !permissions.RolesHavePermission(user.Roles, permissions.OWNER) {
+	if !userCanManageGroup(user, &group) {
 		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionDelete)
 		return
 	}
 
-	// Delete the group
-	tx := database.DB.Begin()
-	
-	// First remove the associations
-	if err := tx.Model(&group).Association("Users").Clear(); err != nil {
-		tx.Rollback()
-		respondWithError(c, http.StatusInternalServerError, "Failed to clear group associations")
-		return
-	}
-	
-	if err := tx.Model(&group).Association("Competitions").Clear(); err != nil {
-		tx.Rollback()
-		respondWithError(c, http.StatusInternalServerError, "Failed to clear group competitions")
-		return
-	}
-	
-	if err := tx.Delete(&group).Error; err != nil {
-		tx.Rollback()
+	// Time overrides and teams of the group are kept until it is purged from the trash
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return trash.MoveToTrash(tx, models.TrashGroup, group.ID, group.Name, user.ID)
+	}); err != nil {
 		respondWithError(c, http.StatusInternalServerError, "Failed to delete group")
 		return
 	}
-	
-	tx.Commit()
+
 	c.Status(http.StatusNoContent)
 }
 
 // UpdateGroup updates a group's name and description
 // @Summary Update a group name and description
-// @Description Update a group name and description
+// @Description Update a group name, description and expiry, and nest it under another group (parent_id, empty to detach it)
 // @Tags Groups
 // @Accept json
 // @Produce json
@@ -211,7 +241,7 @@ func UpdateGroup(c *gin.Context) {
 		return
 	}
 
-	if !userCanManageGroup(user.ID, &group) && !permissions.RolesHavePermission(user.Roles, permissions.OWNER) {
+	if !userCanManageGroup(user, &group) {
 		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionUpdate)
 		return
 	}
@@ -230,11 +260,30 @@ func UpdateGroup(c *gin.Context) {
 	if req.Description != "" {
 		updates.Description = req.Description
 	}
+	if req.ExpiresAt != nil {
+		updates.ExpiresAt = req.ExpiresAt
+	}
 
 	if err := database.DB.Model(&group).Updates(updates).Error; err != nil {
 		respondWithError(c, http.StatusInternalServerError, "Failed to update group")
 		return
 	}
+
+	// An empty parent_id detaches the group, Updates skips nil fields so the parent is set apart
+	if req.ParentID != nil {
+		var parentID *string
+		if *req.ParentID != "" {
+			if status, message := checkParentGroup(user, group.ID, *req.ParentID); status != 0 {
+				respondWithError(c, status, message)
+				return
+			}
+			parentID = req.ParentID
+		}
+		if err := database.DB.Model(&group).Update("parent_id", parentID).Error; err != nil {
+			respondWithError(c, http.StatusInternalServerError, "Failed to update group")
+			return
+		}
+	}
 	
 	c.Status(http.StatusNoContent)
 }
@@ -248,14 +297,19 @@ func UpdateGroup(c *gin.Context) {
 // @Param scope_id path string true "Scope ID"
 // @Success 200 {array} models.Group
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
 // @Router /groups/scope/{scope_id} [get]
 func GetGroupsFromScope(c *gin.Context) {
-	_, err := middleware.GetUserFromRequest(c)
+	user, err := middleware.GetUserFromRequest(c)
 	if err != nil {
 		return
 	}
 
 	scopeID := c.Param("scope_id")
+	if !userCanViewScope(user, scopeID) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionView)
+		return
+	}
 	var scope models.Scope
 	if err := database.DB.Where("id = ?", scopeID).First(&scope).Error; err != nil {
 		respondWithError(c, http.StatusBadRequest, ErrScopeNotFound)
@@ -273,7 +327,7 @@ func GetGroupsFromScope(c *gin.Context) {
 
 // Get all the groups that authenticated user has access to
 // @Summary Get all the groups that authenticated user has access to
-// @Description Get all the groups that authenticated user has access to
+// @Description Get all the groups that authenticated user has access to, through the scopes of its roles, with their subgroups
 // @Tags Groups
 // @Accept json
 // @Produce json
@@ -288,13 +342,7 @@ func GetMyGroups(c *gin.Context) {
 	}
 
 	var groups []models.Group
-	if err := database.DB.Raw(`
-		SELECT DISTINCT g.*
-		FROM public.groups g
-		JOIN public.scopes s ON g.scope_id = s.id
-		JOIN public.role_scopes rs ON rs.scope_id = s.id
-		JOIN public.user_roles ur ON ur.role_id = rs.role_id
-		WHERE ur.user_id = ?`, user.ID).Scan(&groups).Error; err != nil {
+	if err := database.DB.Where("id IN (?)", hierarchy.ManagedGroups(user.ID)).Find(&groups).Error; err != nil {
 		respondWithError(c, http.StatusBadRequest, ErrFetchingGroups)
 		return
 	}
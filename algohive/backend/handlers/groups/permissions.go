@@ -3,82 +3,96 @@ package groups
 import (
 	"api/database"
 	"api/models"
+	"api/utils/hierarchy"
 	"api/utils/permissions"
 )
 
-// userCanManageGroup check if the user can manage the group
-// userID: User ID
+// userCanManageGroup check if the user can manage the group: owners manage every group, other users
+// the groups of the scopes where their roles grant the GROUPS permission, with their subgroups
+// user: the authenticated user
 // group: Group object
 // return: true if the user can manage the group
 //         false if the user cannot manage the group
-func userCanManageGroup(userID string, group *models.Group) bool {
-	// Fetch in cascade the user with roles and scopes
-	var user models.User
-	if err := database.DB.Where("id = ?", userID).Preload("Roles.Scopes.Groups").First(&user).Error; err != nil {
-		return false
+func userCanManageGroup(user models.User, group *models.Group) bool {
+	if permissions.IsOwner(user) {
+		return true
 	}
-	
-	for _, role := range user.Roles {
-		for _, scope := range role.Scopes {
-			for _, g := range scope.Groups {
-				if g.ID == group.ID {
-					return true
-				}
-			}
-		}
+
+	var count int64
+	if err := database.DB.Raw(
+		"SELECT COUNT(*) FROM (?) managed WHERE id = ?", hierarchy.ManagedGroupsWith(user.ID, permissions.GROUPS), group.ID,
+	).Scan(&count).Error; err != nil {
+		return false
 	}
-	
-	return false
+
+	return count > 0
 }
 
-// UserOwnsTargetGroups check if the user owns the target groups
-// userID: User ID
-// targetID: Target ID
-// return: true if the user owns the target groups
-//         false if the user does not own the target groups
-//         false if there is an error
-func UserOwnsTargetGroups(userID string, targetID string) bool {
-    var count int64
-    err := database.DB.Raw(`
-        SELECT COUNT(DISTINCT g1.id) 
-        FROM groups g1
-        JOIN user_groups ug ON g1.id = ug.group_id
-        WHERE ug.user_id = ? 
-        AND g1.id IN (
-            SELECT DISTINCT g2.id
-            FROM groups g2
-            JOIN scopes s ON g2.scope_id = s.id
-            JOIN role_scopes rs ON s.id = rs.scope_id
-            JOIN roles r ON rs.role_id = r.id
-            JOIN user_roles ur ON r.id = ur.role_id
-            WHERE ur.user_id = ?
-        )
-    `, targetID, userID).Count(&count).Error
+// userCanViewGroup check if the user can view the group: owners view every group, other users
+// the groups of the scopes of their roles, whatever the permissions granted there, with their subgroups
+// user: the authenticated user
+// group: Group object
+// return: true if the user can view the group
+func userCanViewGroup(user models.User, group *models.Group) bool {
+	if permissions.IsOwner(user) {
+		return true
+	}
+
+	var count int64
+	if err := database.DB.Raw(
+		"SELECT COUNT(*) FROM (?) visible WHERE id = ?", hierarchy.ManagedGroups(user.ID), group.ID,
+	).Scan(&count).Error; err != nil {
+		return false
+	}
 
-    if err != nil {
-        return false
-    }
-    
-    return count > 0
+	return count > 0
 }
 
 // checkGroupPermission check if the user has permission to manage the group
-// userID: User ID
+// user: the authenticated user
 // groupID: Group ID
 // return: Group object and a boolean indicating if the user can manage the group
-func checkGroupPermission(userID string, groupID string) (*models.Group, bool) {
+func checkGroupPermission(user models.User, groupID string) (*models.Group, bool) {
 	var group models.Group
 	if err := database.DB.Where("id = ?", groupID).First(&group).Error; err != nil {
 		return nil, false
 	}
 	
-	return &group, userCanManageGroup(userID, &group)
+	return &group, userCanManageGroup(user, &group)
 }
 
-// hasGroupPermission check if the user has the required permission
+// hasGroupPermission check if the user has the required permission in a scope
 // user: User object
+// scopeID: Scope ID
 // permission: Required permission
-// return: true if the user has the permission
-func hasGroupPermission(user models.User, permission int) bool {
-	return permissions.IsStaff(user) || permissions.RolesHavePermission(user.Roles, permission)
+// return: true if the user is an owner or if the roles of the user grant the permission in the scope
+func hasGroupPermission(user models.User, scopeID string, permission int) bool {
+	if permissions.IsOwner(user) {
+		return true
+	}
+
+	scopePermissions, err := hierarchy.ScopePermissions(user.ID, scopeID)
+	if err != nil {
+		return false
+	}
+	return permissions.HasPermission(scopePermissions, permission)
+}
+
+// userCanViewScope check if the user can view the groups of a scope: owners view every scope,
+// other users the scopes of their roles
+// user: the authenticated user
+// scopeID: Scope ID
+func userCanViewScope(user models.User, scopeID string) bool {
+	if permissions.IsOwner(user) {
+		return true
+	}
+
+	var count int64
+	if err := database.DB.Raw(
+		"SELECT COUNT(*) FROM (?) bound WHERE scope_id = ?", hierarchy.ScopesWith(user.ID, 0), scopeID,
+	).Scan(&count).Error; err != nil {
+		return false
+	}
+
+	return count > 0
 }
@@ -14,9 +14,14 @@ func RegisterRoutes(r *gin.RouterGroup) {
 	{
 		groups.GET("/", GetAllGroups)
 		groups.GET("/me", GetMyGroups)
+		groups.POST("/join", JoinGroup)
 		groups.GET("/:group_id", GetGroup)
 		groups.GET("/scope/:scope_id", GetGroupsFromScope)
 		groups.PUT("/:group_id", UpdateGroup)
+		groups.PUT("/:group_id/expiry", ExtendGroupExpiry)
+		groups.GET("/:group_id/join-code", GetGroupJoinCode)
+		groups.POST("/:group_id/join-code", RegenerateGroupJoinCode)
+		groups.DELETE("/:group_id/join-code", DisableGroupJoinCode)
 		groups.POST("/:group_id/users/:user_id", AddUserToGroup)
 		groups.DELETE("/:group_id/users/:user_id", RemoveUserFromGroup)
 		groups.POST("/", CreateGroup)
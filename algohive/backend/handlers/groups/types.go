@@ -1,6 +1,8 @@
 package groups
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -18,6 +20,16 @@ const (
 	ErrNoPermissionAddUser    = "User does not have permission to add users to this group"
 	ErrNoPermissionRemoveUser = "User does not have permission to remove users from this group"
 	ErrFetchingGroups         = "Error while fetching groups"
+	ErrInvalidListParams      = "Invalid pagination, sort or filter parameters"
+	ErrFailedExtendExpiry     = "Failed to extend the expiry of the group"
+	ErrParentNotFound         = "Parent group not found"
+	ErrNoPermissionParent     = "User does not have permission to manage the parent group"
+	ErrParentCycle            = "A group cannot be nested under itself or one of its subgroups"
+	ErrJoinCodeNotFound       = "This group has no join code"
+	ErrInvalidJoinCode        = "Invalid, expired or exhausted join code"
+	ErrGroupExpired           = "This group has expired"
+	ErrFailedJoinCode         = "Failed to update the join code"
+	ErrFailedJoinGroup        = "Failed to join the group"
 )
 
 // CreateGroupRequest modèle pour créer un groupe
@@ -25,12 +37,50 @@ type CreateGroupRequest struct {
 	Name        string `json:"name" binding:"required"`
 	Description string `json:"description"`
 	ScopeId     string `json:"scope_id" binding:"required"`
+	ParentID    *string    `json:"parent_id"`
+	ExpiresAt   *time.Time `json:"expires_at"`
 }
 
-// UpdateGroupRequest modèle pour mettre à jour un groupe
+// UpdateGroupRequest modèle pour mettre à jour un groupe, parent_id "" détache le groupe de son parent
 type UpdateGroupRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	ParentID    *string    `json:"parent_id"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+// ExtendGroupExpiryRequest sets the expiry date of a group, null for a group that never expires.
+// With extend_members, the members whose own expiry date is earlier get the same date
+type ExtendGroupExpiryRequest struct {
+	ExpiresAt     *time.Time `json:"expires_at"`
+	ExtendMembers bool       `json:"extend_members"`
+}
+
+// ExtendGroupExpiryResponse reports the members whose expiry date was extended,
+// and the members blocked by their expiry that got their access back
+type ExtendGroupExpiryResponse struct {
+	GroupID     string     `json:"group_id"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	Extended    int64      `json:"extended"`
+	Reactivated int64      `json:"reactivated"`
+}
+
+// JoinCodeRequest sets the limits of a new join code, both optional
+type JoinCodeRequest struct {
+	ExpiresAt *time.Time `json:"expires_at"`
+	MaxUses   *int       `json:"max_uses" binding:"omitempty,min=1"`
+}
+
+// JoinGroupRequest carries the code of the group to join
+type JoinGroupRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// JoinGroupResponse reports the group joined, already_member being true when the code was not used
+type JoinGroupResponse struct {
+	GroupID       string `json:"group_id"`
+	GroupName     string `json:"group_name"`
+	AlreadyMember bool   `json:"already_member"`
 }
 
 // respondWithError envoie une réponse d'erreur standardisée
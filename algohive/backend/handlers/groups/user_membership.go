@@ -29,18 +29,13 @@ func AddUserToGroup(c *gin.Context) {
 	}
 
 	groupID := c.Param("group_id")
-	if !UserOwnsTargetGroups(user.ID, groupID) {
-		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionAddUser)
-		return
-	}
-
 	var group models.Group
 	if err := database.DB.Where("id = ?", groupID).Preload("Users").First(&group).Error; err != nil {
 		respondWithError(c, http.StatusBadRequest, ErrGroupNotFound)
 		return
 	}
 
-	if !userCanManageGroup(user.ID, &group) {
+	if !userCanManageGroup(user, &group) {
 		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionAddUser)
 		return
 	}
@@ -81,18 +76,13 @@ func RemoveUserFromGroup(c *gin.Context) {
 	}
 
 	groupID := c.Param("group_id")
-	if !UserOwnsTargetGroups(user.ID, groupID) {
-		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionRemoveUser)
-		return
-	}
-
 	var group models.Group
 	if err := database.DB.Where("id = ?", groupID).Preload("Users").First(&group).Error; err != nil {
 		respondWithError(c, http.StatusBadRequest, ErrGroupNotFound)
 		return
 	}
 
-	if !userCanManageGroup(user.ID, &group) {
+	if !userCanManageGroup(user, &group) {
 		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionRemoveUser)
 		return
 	}
@@ -0,0 +1,50 @@
+package practice
+
+import (
+	"api/database"
+	"api/models"
+	"api/utils/hierarchy"
+	"api/utils/permissions"
+)
+
+// getVisibleCatalogs retrieves the catalogs a user can practice on.
+// Users with the API_ENV or OWNER permission see every catalog, other users see the catalogs
+// of the live scopes reachable through their roles (as in GetAllCatalogs) or through their groups and their ancestors
+// user: the authenticated user with its roles
+// catalogs: pointer to the slice of catalogs to fill
+func getVisibleCatalogs(user models.User, catalogs *[]models.Catalog) error {
+	if permissions.RolesHavePermission(user.Roles, permissions.API_ENV) || permissions.IsOwner(user) {
+		return database.DB.Find(catalogs).Error
+	}
+
+	return database.DB.Raw(`
+		SELECT DISTINCT c.*
+		FROM catalogs c
+		JOIN scope_catalogs sc ON sc.catalog_id = c.id
+		JOIN scopes s ON s.id = sc.scope_id AND s.deleted_at IS NULL
+		WHERE sc.scope_id IN (
+			SELECT rs.scope_id
+			FROM role_scopes rs
+			JOIN user_roles ur ON ur.role_id = rs.role_id
+			WHERE ur.user_id = ?
+			UNION
+			SELECT g.scope_id
+			FROM groups g
+			WHERE g.id IN (?)
+		)`, user.ID, hierarchy.MemberGroups(user.ID)).Scan(catalogs).Error
+}
+
+// userCanPracticeOnCatalog checks if the catalog is visible to the user
+func userCanPracticeOnCatalog(user models.User, catalogID string) bool {
+	var catalogs []models.Catalog
+	if err := getVisibleCatalogs(user, &catalogs); err != nil {
+		return false
+	}
+
+	for _, catalog := range catalogs {
+		if catalog.ID == catalogID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,331 @@
+package practice
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/utils/beeapi"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// generationCacheTTL is how long a generated practice input is kept in Redis
+const generationCacheTTL = time.Hour
+
+// getSessionGeneration returns the generated input and solutions of a practice session.
+// Practice inputs are seeded apart from competition inputs so practicing never reveals a competition answer
+func getSessionGeneration(ctx context.Context, session *models.PracticeSession) (*beeapi.Generation, error) {
+	cacheKey := "practice_input:" + session.ID
+
+	if cached, err := database.REDIS.Get(ctx, cacheKey).Result(); err == nil {
+		var generation beeapi.Generation
+		if err := json.Unmarshal([]byte(cached), &generation); err == nil {
+			return &generation, nil
+		}
+	}
+
+	var catalog models.Catalog
+	if err := database.DB.First(&catalog, "id = ?", session.CatalogID).Error; err != nil {
+		return nil, err
+	}
+
+	generation, err := beeapi.GeneratePuzzle(catalog.Address, session.Theme, session.PuzzleName, "practice:"+session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Continue even if caching fails
+	if generationJSON, err := json.Marshal(generation); err == nil {
+		database.REDIS.Set(ctx, cacheKey, generationJSON, generationCacheTTL)
+	}
+
+	return generation, nil
+}
+
+// getOwnSession loads a practice session owned by the authenticated user
+func getOwnSession(c *gin.Context, user models.User) (*models.PracticeSession, bool) {
+	var session models.PracticeSession
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("session_id"), user.ID).First(&session).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrSessionNotFound)
+		return nil, false
+	}
+	return &session, true
+}
+
+// GetPracticeCatalogs retrieves the catalogs the user can practice on
+// @Summary Get practice catalogs
+// @Description Get the catalogs visible to the current user through their roles and groups scopes
+// @Tags Practice
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Catalog
+// @Failure 401 {object} map[string]string
+// @Router /practice/catalogs [get]
+// @Security Bearer
+func GetPracticeCatalogs(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	var catalogs []models.Catalog
+	if err := getVisibleCatalogs(user, &catalogs); err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedFetchCatalogs)
+		return
+	}
+
+	c.JSON(http.StatusOK, catalogs)
+}
+
+// StartPracticeSession starts a practice session on a catalog puzzle
+// @Summary Start a practice session
+// @Description Start training on a puzzle of a visible catalog, without affecting any competition
+// @Tags Practice
+// @Accept json
+// @Produce json
+// @Param session body StartPracticeRequest true "Puzzle to practice on"
+// @Success 201 {object} models.PracticeSession
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /practice/sessions [post]
+// @Security Bearer
+func StartPracticeSession(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	var req StartPracticeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidRequest)
+		return
+	}
+
+	if !userCanPracticeOnCatalog(user, req.CatalogID) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionCatalog)
+		return
+	}
+
+	var catalog models.Catalog
+	if err := database.DB.First(&catalog, "id = ?", req.CatalogID).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrCatalogNotFound)
+		return
+	}
+
+	theme, err := beeapi.GetTheme(catalog.Address, req.Theme)
+	if err != nil {
+		if errors.Is(err, beeapi.ErrThemeNotFound) {
+			respondWithError(c, http.StatusBadRequest, ErrPuzzleNotFound)
+			return
+		}
+		respondWithError(c, http.StatusInternalServerError, ErrAPIReachFailed)
+		return
+	}
+
+	var puzzle *beeapi.Puzzle
+	for i := range theme.Puzzles {
+		if theme.Puzzles[i].ID == req.PuzzleID {
+			puzzle = &theme.Puzzles[i]
+			break
+		}
+	}
+	if puzzle == nil {
+		respondWithError(c, http.StatusBadRequest, ErrPuzzleNotFound)
+		return
+	}
+
+	session := models.PracticeSession{
+		UserID:     user.ID,
+		CatalogID:  catalog.ID,
+		Theme:      theme.Name,
+		PuzzleID:   puzzle.ID,
+		PuzzleName: puzzle.Name,
+		Difficulty: puzzle.Difficulty,
+		StartTime:  time.Now(),
+	}
+
+	if err := database.DB.Create(&session).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedCreateSession)
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+// GetPracticeSessions retrieves the practice history of the current user
+// @Summary Get practice history
+// @Description Get the practice sessions of the current user, most recent first
+// @Tags Practice
+// @Accept json
+// @Produce json
+// @Param puzzle_id query string false "Only sessions on this puzzle"
+// @Success 200 {array} models.PracticeSession
+// @Failure 401 {object} map[string]string
+// @Router /practice/sessions [get]
+// @Security Bearer
+func GetPracticeSessions(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	query := database.DB.Where("user_id = ?", user.ID)
+	if puzzleID := c.Query("puzzle_id"); puzzleID != "" {
+		query = query.Where("puzzle_id = ?", puzzleID)
+	}
+
+	var sessions []models.PracticeSession
+	if err := query.Order("start_time DESC").Find(&sessions).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedFetchSessions)
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// GetPracticeSession retrieves a practice session of the current user
+// @Summary Get a practice session
+// @Description Get a practice session of the current user
+// @Tags Practice
+// @Accept json
+// @Produce json
+// @Param session_id path string true "Practice session ID"
+// @Success 200 {object} models.PracticeSession
+// @Failure 404 {object} map[string]string
+// @Router /practice/sessions/{session_id} [get]
+// @Security Bearer
+func GetPracticeSession(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	session, ok := getOwnSession(c, user)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// GetPracticeInput retrieves the generated input of a practice session
+// @Summary Get the input of a practice session
+// @Description Generate (or return the cached) puzzle input of a practice session
+// @Tags Practice
+// @Accept json
+// @Produce json
+// @Param session_id path string true "Practice session ID"
+// @Success 200 {object} PracticeInputResponse
+// @Failure 404 {object} map[string]string
+// @Router /practice/sessions/{session_id}/input [get]
+// @Security Bearer
+func GetPracticeInput(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	session, ok := getOwnSession(c, user)
+	if !ok {
+		return
+	}
+
+	generation, err := getSessionGeneration(c.Request.Context(), session)
+	if err != nil {
+		if errors.Is(err, beeapi.ErrPuzzleNotFound) {
+			respondWithError(c, http.StatusNotFound, ErrPuzzleNotFound)
+			return
+		}
+		respondWithError(c, http.StatusInternalServerError, ErrAPIReachFailed)
+		return
+	}
+
+	c.JSON(http.StatusOK, PracticeInputResponse{
+		SessionID:  session.ID,
+		InputLines: generation.InputLines,
+	})
+}
+
+// SubmitPracticeAnswer checks an answer for a step of a practice session
+// @Summary Submit a practice answer
+// @Description Check the answer of a step (1 or 2) of a practice session
+// @Tags Practice
+// @Accept json
+// @Produce json
+// @Param session_id path string true "Practice session ID"
+// @Param answer body PracticeAnswerRequest true "Answer"
+// @Success 200 {object} PracticeAnswerResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /practice/sessions/{session_id}/answer [post]
+// @Security Bearer
+func SubmitPracticeAnswer(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	session, ok := getOwnSession(c, user)
+	if !ok {
+		return
+	}
+
+	var req PracticeAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidRequest)
+		return
+	}
+
+	switch req.Step {
+	case 1:
+		if session.FirstSolvedAt != nil {
+			respondWithError(c, http.StatusBadRequest, ErrStepAlreadySolved)
+			return
+		}
+	case 2:
+		if session.FirstSolvedAt == nil {
+			respondWithError(c, http.StatusBadRequest, ErrFirstStepRequired)
+			return
+		}
+		if session.SecondSolvedAt != nil {
+			respondWithError(c, http.StatusBadRequest, ErrStepAlreadySolved)
+			return
+		}
+	default:
+		respondWithError(c, http.StatusBadRequest, ErrInvalidStep)
+		return
+	}
+
+	generation, err := getSessionGeneration(c.Request.Context(), session)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrAPIReachFailed)
+		return
+	}
+
+	correct := beeapi.CheckAnswer(req.Answer, generation.Solution(req.Step))
+
+	session.Attempts++
+	if correct {
+		now := time.Now()
+		if req.Step == 1 {
+			session.FirstSolvedAt = &now
+		} else {
+			session.SecondSolvedAt = &now
+		}
+	}
+
+	if err := database.DB.Save(session).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedUpdateSession)
+		return
+	}
+
+	c.JSON(http.StatusOK, PracticeAnswerResponse{
+		Correct: correct,
+		Session: *session,
+	})
+}
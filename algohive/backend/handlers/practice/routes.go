@@ -0,0 +1,24 @@
+package practice
+
+import (
+	"api/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes registers all routes related to practice mode
+// r: the RouterGroup to which the routes are added
+func RegisterRoutes(r *gin.RouterGroup) {
+	practice := r.Group("/practice")
+	practice.Use(middleware.AuthMiddleware())
+	{
+		practice.GET("/catalogs", GetPracticeCatalogs)
+
+		// Practice session routes
+		practice.GET("/sessions", GetPracticeSessions)
+		practice.POST("/sessions", StartPracticeSession)
+		practice.GET("/sessions/:session_id", GetPracticeSession)
+		practice.GET("/sessions/:session_id/input", GetPracticeInput)
+		practice.POST("/sessions/:session_id/answer", SubmitPracticeAnswer)
+	}
+}
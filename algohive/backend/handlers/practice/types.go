@@ -0,0 +1,54 @@
+package practice
+
+import (
+	"api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Error message constants
+const (
+	ErrCatalogNotFound     = "Catalog not found"
+	ErrNoPermissionCatalog = "User does not have access to this catalog"
+	ErrPuzzleNotFound      = "Puzzle not found in the catalog theme"
+	ErrSessionNotFound     = "Practice session not found"
+	ErrInvalidRequest      = "Invalid request data"
+	ErrInvalidStep         = "Step must be 1 or 2"
+	ErrFirstStepRequired   = "The first step must be solved before the second one"
+	ErrStepAlreadySolved   = "This step is already solved"
+	ErrAPIReachFailed      = "Error while reaching the catalog"
+	ErrFailedFetchCatalogs = "Failed to fetch catalogs"
+	ErrFailedFetchSessions = "Failed to fetch practice sessions"
+	ErrFailedCreateSession = "Failed to create practice session"
+	ErrFailedUpdateSession = "Failed to update practice session"
+)
+
+// StartPracticeRequest model for starting a practice session
+type StartPracticeRequest struct {
+	CatalogID string `json:"catalog_id" binding:"required"`
+	Theme     string `json:"theme" binding:"required"`
+	PuzzleID  string `json:"puzzle_id" binding:"required"`
+}
+
+// PracticeAnswerRequest model for submitting a practice answer
+type PracticeAnswerRequest struct {
+	Step   int    `json:"step" binding:"required"`
+	Answer string `json:"answer" binding:"required"`
+}
+
+// PracticeAnswerResponse model for the result of a practice answer
+type PracticeAnswerResponse struct {
+	Correct bool                   `json:"correct"`
+	Session models.PracticeSession `json:"session"`
+}
+
+// PracticeInputResponse model for the generated input of a practice session
+type PracticeInputResponse struct {
+	SessionID  string   `json:"session_id"`
+	InputLines []string `json:"input_lines"`
+}
+
+// respondWithError sends a standardized error response
+func respondWithError(c *gin.Context, status int, message string) {
+	c.JSON(status, gin.H{"error": message})
+}
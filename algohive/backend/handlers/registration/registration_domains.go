@@ -0,0 +1,158 @@
+package registration
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/utils/permissions"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// domainPattern matches the email domains a rule can be added for
+var domainPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)+$`)
+
+// GetDomains retrieves the domain rules
+// @Summary Get the domain rules
+// @Description Get the email domains allowed to register in the domain mode, with the group or scope their users are placed in. Only accessible to owners
+// @Tags Registration
+// @Produce json
+// @Success 200 {array} models.RegistrationDomain
+// @Failure 401 {object} map[string]string
+// @Router /registration/domains [get]
+// @Security Bearer
+func GetDomains(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	if !permissions.RolesHavePermission(user.Roles, permissions.OWNER) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionManage)
+		return
+	}
+
+	var domains []models.RegistrationDomain
+	if err := database.DB.Order("domain").Find(&domains).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedGetDomains)
+		return
+	}
+
+	c.JSON(http.StatusOK, domains)
+}
+
+// CreateDomain adds a domain rule
+// @Summary Add a domain rule
+// @Description Allow an email domain to register in the domain mode. Its users are placed in group_id, or in the group named after
+// @Description the domain in scope_id, created on the first registration. In the open mode, the rule still places its users. Only accessible to owners
+// @Tags Registration
+// @Accept json
+// @Produce json
+// @Param request body DomainRequest true "Domain rule"
+// @Success 201 {object} models.RegistrationDomain
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /registration/domains [post]
+// @Security Bearer
+func CreateDomain(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	if !permissions.RolesHavePermission(user.Roles, permissions.OWNER) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionManage)
+		return
+	}
+
+	var req DomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	domain := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(req.Domain), "@"))
+	if !domainPattern.MatchString(domain) {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidDomain)
+		return
+	}
+	if req.GroupID != nil && req.ScopeID != nil {
+		respondWithError(c, http.StatusBadRequest, ErrDomainTarget)
+		return
+	}
+	if req.GroupID != nil {
+		if err := database.DB.Select("id").First(&models.Group{}, "id = ?", *req.GroupID).Error; err != nil {
+			respondWithError(c, http.StatusNotFound, ErrGroupNotFound)
+			return
+		}
+	}
+	if req.ScopeID != nil {
+		if err := database.DB.Select("id").First(&models.Scope{}, "id = ?", *req.ScopeID).Error; err != nil {
+			respondWithError(c, http.StatusNotFound, ErrScopeNotFound)
+			return
+		}
+	}
+
+	var count int64
+	if err := database.DB.Model(&models.RegistrationDomain{}).Where("domain = ?", domain).Count(&count).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedSaveDomain)
+		return
+	}
+	if count > 0 {
+		respondWithError(c, http.StatusConflict, ErrDomainExists)
+		return
+	}
+
+	rule := models.RegistrationDomain{
+		Domain:    domain,
+		GroupID:   req.GroupID,
+		ScopeID:   req.ScopeID,
+		CreatedAt: time.Now(),
+	}
+	if err := database.DB.Create(&rule).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedSaveDomain)
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// DeleteDomain removes a domain rule
+// @Summary Delete a domain rule
+// @Description Remove a domain rule, the users already registered with it keep their groups. Only accessible to owners
+// @Tags Registration
+// @Param id path string true "Domain rule ID"
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /registration/domains/{id} [delete]
+// @Security Bearer
+func DeleteDomain(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	if !permissions.RolesHavePermission(user.Roles, permissions.OWNER) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionManage)
+		return
+	}
+
+	result := database.DB.Where("id = ?", c.Param("id")).Delete(&models.RegistrationDomain{})
+	if result.Error != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedDeleteDomain)
+		return
+	}
+	if result.RowsAffected == 0 {
+		respondWithError(c, http.StatusNotFound, ErrDomainNotFound)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
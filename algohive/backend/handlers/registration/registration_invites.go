@@ -0,0 +1,195 @@
+package registration
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/utils/pagination"
+	"api/utils/permissions"
+	"api/utils/registration"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// inviteSortKeys are the sort keys accepted by the list of invitations
+var inviteSortKeys = map[string]string{
+	"created_at": "created_at",
+	"expires_at": "expires_at",
+	"email":      "email",
+}
+
+// GetInvites retrieves the invitations
+// @Summary Get the invitations
+// @Description Get a page of the invitations to register, only accessible to owners
+// @Tags Registration
+// @Produce json
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param sort query string false "Sort key (created_at, expires_at, email), prefixed by - to sort descending"
+// @Param pending query bool false "Only the unused and unexpired invitations, or only the others"
+// @Success 200 {object} pagination.Page{items=[]models.RegistrationInvite}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /registration/invites [get]
+// @Security Bearer
+func GetInvites(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	if !permissions.RolesHavePermission(user.Roles, permissions.OWNER) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionManage)
+		return
+	}
+
+	params, err := pagination.Parse(c, inviteSortKeys, "-created_at")
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidListParams)
+		return
+	}
+
+	query := database.DB.Model(&models.RegistrationInvite{})
+	pending, err := pagination.Bool(c, "pending")
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidListParams)
+		return
+	}
+	if pending != nil {
+		if *pending {
+			query = query.Where("used_at IS NULL AND expires_at > ?", time.Now())
+		} else {
+			query = query.Where("used_at IS NOT NULL OR expires_at <= ?", time.Now())
+		}
+	}
+
+	var invites []models.RegistrationInvite
+	page, err := pagination.Find(query, params, &invites)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedGetInvites)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// CreateInvite invites an email address to register
+// @Summary Invite an email address
+// @Description Create an invitation for an email address, optionally into a group, and send it by email. The token is only returned once,
+// @Description the invitation can be used once and expires after 14 days unless expires_at is set. Only accessible to owners
+// @Tags Registration
+// @Accept json
+// @Produce json
+// @Param request body InviteRequest true "Invitation"
+// @Success 201 {object} InviteResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /registration/invites [post]
+// @Security Bearer
+func CreateInvite(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	if !permissions.RolesHavePermission(user.Roles, permissions.OWNER) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionManage)
+		return
+	}
+
+	var req InviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(registration.InviteValidity)
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) {
+			respondWithError(c, http.StatusBadRequest, ErrInvalidExpiry)
+			return
+		}
+		expiresAt = *req.ExpiresAt
+	}
+	if req.GroupID != nil {
+		if err := database.DB.Select("id").First(&models.Group{}, "id = ?", *req.GroupID).Error; err != nil {
+			respondWithError(c, http.StatusNotFound, ErrGroupNotFound)
+			return
+		}
+	}
+
+	token, hash, err := registration.NewToken()
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedCreateInvite)
+		return
+	}
+
+	invite := models.RegistrationInvite{
+		Email:     strings.ToLower(req.Email),
+		TokenHash: hash,
+		GroupID:   req.GroupID,
+		ExpiresAt: expiresAt,
+		CreatedBy: &user.ID,
+		CreatedAt: now,
+	}
+	if err := database.DB.Create(&invite).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedCreateInvite)
+		return
+	}
+
+	// The invitation stays valid when the email fails, the token can be shared by hand
+	emailSent := true
+	if err := registration.SendInviteEmail(invite, token); err != nil {
+		log.Printf("Failed to send the invitation email to %s: %v", invite.Email, err)
+		emailSent = false
+	}
+
+	c.JSON(http.StatusCreated, InviteResponse{
+		ID:        invite.ID,
+		Email:     invite.Email,
+		GroupID:   invite.GroupID,
+		ExpiresAt: invite.ExpiresAt,
+		Token:     token,
+		EmailSent: emailSent,
+	})
+}
+
+// DeleteInvite revokes an invitation
+// @Summary Delete an invitation
+// @Description Revoke an invitation, it can no longer be used to register. Only accessible to owners
+// @Tags Registration
+// @Param id path string true "Invitation ID"
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /registration/invites/{id} [delete]
+// @Security Bearer
+func DeleteInvite(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	if !permissions.RolesHavePermission(user.Roles, permissions.OWNER) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionManage)
+		return
+	}
+
+	result := database.DB.Where("id = ?", c.Param("id")).Delete(&models.RegistrationInvite{})
+	if result.Error != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedDeleteInvite)
+		return
+	}
+	if result.RowsAffected == 0 {
+		respondWithError(c, http.StatusNotFound, ErrInviteNotFound)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,94 @@
+package registration
+
+import (
+	"api/middleware"
+	"api/models"
+	"api/utils/permissions"
+	"api/utils/registration"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSettings retrieves the registration settings
+// @Summary Get the registration settings
+// @Description Get the registration mode and whether new users must verify their email address, only accessible to owners
+// @Tags Registration
+// @Produce json
+// @Success 200 {object} models.RegistrationSettings
+// @Failure 401 {object} map[string]string
+// @Router /registration/settings [get]
+// @Security Bearer
+func GetSettings(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	if !permissions.RolesHavePermission(user.Roles, permissions.OWNER) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionManage)
+		return
+	}
+
+	settings, err := registration.LoadSettings()
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedGetSettings)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings replaces the registration settings
+// @Summary Update the registration settings
+// @Description Set the registration mode: disabled, open, domain (only the email domains with a rule) or invite (only invited email addresses),
+// @Description and whether new users must verify their email address before logging in. Only accessible to owners
+// @Tags Registration
+// @Accept json
+// @Produce json
+// @Param request body SettingsRequest true "Registration settings"
+// @Success 200 {object} models.RegistrationSettings
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /registration/settings [put]
+// @Security Bearer
+func UpdateSettings(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	if !permissions.RolesHavePermission(user.Roles, permissions.OWNER) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionManage)
+		return
+	}
+
+	var req SettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !slices.Contains(registration.Modes, req.Mode) {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidMode)
+		return
+	}
+
+	settings := models.RegistrationSettings{
+		Mode:                     req.Mode,
+		RequireEmailVerification: req.RequireEmailVerification,
+		UpdatedBy:                &user.ID,
+	}
+	if err := registration.SaveSettings(settings); err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedSaveSettings)
+		return
+	}
+
+	settings, err = registration.LoadSettings()
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedGetSettings)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
@@ -0,0 +1,24 @@
+package registration
+
+import (
+	"api/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes registers all routes related to the registration settings
+// r: the RouterGroup to which the routes are added
+func RegisterRoutes(r *gin.RouterGroup) {
+	registration := r.Group("/registration")
+	registration.Use(middleware.AuthMiddleware())
+	{
+		registration.GET("/settings", GetSettings)
+		registration.PUT("/settings", UpdateSettings)
+		registration.GET("/domains", GetDomains)
+		registration.POST("/domains", CreateDomain)
+		registration.DELETE("/domains/:id", DeleteDomain)
+		registration.GET("/invites", GetInvites)
+		registration.POST("/invites", CreateInvite)
+		registration.DELETE("/invites/:id", DeleteInvite)
+	}
+}
@@ -0,0 +1,67 @@
+package registration
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Error messages constants
+const (
+	ErrNoPermissionManage = "User does not have permission to manage the registration"
+	ErrInvalidMode        = "Unknown registration mode, expected disabled, open, domain or invite"
+	ErrFailedGetSettings  = "Failed to get the registration settings"
+	ErrFailedSaveSettings = "Failed to save the registration settings"
+	ErrInvalidDomain      = "Invalid email domain"
+	ErrDomainTarget       = "Set either group_id or scope_id, not both"
+	ErrDomainExists       = "A rule already exists for this domain"
+	ErrDomainNotFound     = "Domain rule not found"
+	ErrGroupNotFound      = "Group not found"
+	ErrScopeNotFound      = "Scope not found"
+	ErrFailedGetDomains   = "Failed to get the domain rules"
+	ErrFailedSaveDomain   = "Failed to save the domain rule"
+	ErrFailedDeleteDomain = "Failed to delete the domain rule"
+	ErrInvalidListParams  = "Invalid pagination, sort or filter parameters"
+	ErrInvalidExpiry      = "The expiry date must be in the future"
+	ErrInviteNotFound     = "Invitation not found"
+	ErrFailedGetInvites   = "Failed to get the invitations"
+	ErrFailedCreateInvite = "Failed to create the invitation"
+	ErrFailedDeleteInvite = "Failed to delete the invitation"
+)
+
+// SettingsRequest replaces the registration settings
+type SettingsRequest struct {
+	Mode                     string `json:"mode" binding:"required"`
+	RequireEmailVerification bool   `json:"require_email_verification"`
+}
+
+// DomainRequest adds a domain rule, the users registering with the domain join the group, or
+// the group named after the domain in the scope
+type DomainRequest struct {
+	Domain  string  `json:"domain" binding:"required"`
+	GroupID *string `json:"group_id"`
+	ScopeID *string `json:"scope_id"`
+}
+
+// InviteRequest invites an email address to register, optionally into a group.
+// The invitation expires after 14 days when expires_at is not set
+type InviteRequest struct {
+	Email     string     `json:"email" binding:"required,email"`
+	GroupID   *string    `json:"group_id"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// InviteResponse is a created invitation with its token, only returned once
+type InviteResponse struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	GroupID   *string   `json:"group_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Token     string    `json:"token"`
+	EmailSent bool      `json:"email_sent"`
+}
+
+// respondWithError sends a JSON response with an error message
+func respondWithError(c *gin.Context, status int, message string) {
+	c.JSON(status, gin.H{"error": message})
+}
@@ -0,0 +1,97 @@
+package roles
+
+import (
+	"api/models"
+	"api/utils/audit"
+	"api/utils/escalation"
+	"api/utils/hierarchy"
+	"api/utils/permissions"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// checkManageScopes checks that the user holds the ROLES permission in every live scope of a list, otherwise the request is rejected
+// user: the authenticated user
+// scopeIDs: slice of scope IDs or subquery selecting them, see escalation.CanManageIn
+// message: the error message of a rejection
+// returns: true when the operation can proceed
+func checkManageScopes(c *gin.Context, user models.User, scopeIDs interface{}, message string) bool {
+	allowed, err := escalation.CanManageIn(user, permissions.ROLES, scopeIDs)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedPermissionCheck)
+		return false
+	}
+	if !allowed {
+		respondWithError(c, http.StatusUnauthorized, message)
+		return false
+	}
+	return true
+}
+
+// checkManageRole checks that the user holds the ROLES permission in every live scope a role is bound to,
+// otherwise the request is rejected
+// user: the authenticated user
+// roleID: ID of the role
+// message: the error message of a rejection
+// returns: true when the operation can proceed
+func checkManageRole(c *gin.Context, user models.User, roleID string, message string) bool {
+	return checkManageScopes(c, user, hierarchy.RoleScopes([]string{roleID}), message)
+}
+
+// boundGrant is what a role grants once bound to scopes
+// rolePermissions: the permissions of the role
+// scopeIDs: IDs of the live scopes the role is bound to
+// masks: the permission masks of the bindings already set, by scope ID
+func boundGrant(rolePermissions int, scopeIDs []string, masks map[string]*int) escalation.Grant {
+	grant := escalation.Grant{Permissions: rolePermissions, Scopes: make(map[string]int, len(scopeIDs))}
+	for _, scopeID := range scopeIDs {
+		grant.Scopes[scopeID] = rolePermissions
+		if mask := masks[scopeID]; mask != nil {
+			grant.Scopes[scopeID] = *mask
+		}
+	}
+	return grant
+}
+
+// checkGrant checks that the user holds every permission of a grant, otherwise the attempt is logged and rejected
+// user: the authenticated user
+// grant: the permissions given, taken away or changed
+// targetID: ID of the role or user the operation is about
+// message: the error message of a rejection
+// returns: true when the operation can proceed
+func checkGrant(c *gin.Context, user models.User, grant escalation.Grant, targetID string, message string) bool {
+	allowed, err := escalation.CanGrant(user, grant)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedPermissionCheck)
+		return false
+	}
+	if !allowed {
+		escalation.Deny(c, audit.ActionGrantDenied, user.ID, targetID)
+		respondWithError(c, http.StatusUnauthorized, message)
+		return false
+	}
+	return true
+}
+
+// checkRoleGrant checks that the user holds every permission a role grants, otherwise the attempt is logged and rejected
+// user: the authenticated user
+// roleID: ID of the role
+// message: the error message of a rejection
+// returns: true when the operation can proceed
+func checkRoleGrant(c *gin.Context, user models.User, roleID string, message string) bool {
+	grant, err := escalation.RoleGrant(roleID)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedPermissionCheck)
+		return false
+	}
+	return checkGrant(c, user, grant, roleID, message)
+}
+
+// respondLastOwner logs and rejects an operation that would have removed the last owner
+// user: the authenticated user
+// targetID: ID of the role or user the operation is about
+func respondLastOwner(c *gin.Context, user models.User, targetID string) {
+	escalation.Deny(c, audit.ActionLastOwnerDenied, user.ID, targetID)
+	respondWithError(c, http.StatusConflict, ErrLastOwner)
+}
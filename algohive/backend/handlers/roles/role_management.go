@@ -4,21 +4,29 @@ import (
 	"api/database"
 	"api/middleware"
 	"api/models"
+	"api/utils/escalation"
+	"api/utils/hierarchy"
+	"api/utils/pagination"
 	"api/utils/permissions"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CreateRole creates a new role
 // @Summary Create a new Role
-// @Description Create a new Role
+// @Description Create a new Role, bound to scopes where the user holds the ROLES permission (in one of their scopes for a role without scopes),
+// @Description granting only permissions the user holds, in the scopes where they hold them
 // @Tags Roles
 // @Accept json
 // @Produce json
 // @Param role body CreateRoleRequest true "Role Profile"
 // @Success 200 {object} models.Role
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
 // @Router /roles [post]
 // @Security Bearer
 func CreateRole(c *gin.Context) {
@@ -27,12 +35,6 @@ func CreateRole(c *gin.Context) {
 		return
 	}
 
-	// Check permissions
-	if !permissions.RolesHavePermission(user.Roles, permissions.ROLES) {
-		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionCreate)
-		return
-	}
-
 	var createRoleRequest CreateRoleRequest
 	if err := c.ShouldBindJSON(&createRoleRequest); err != nil {
 		respondWithError(c, http.StatusBadRequest, err.Error())
@@ -53,6 +55,18 @@ func CreateRole(c *gin.Context) {
 		}
 	}
 
+	// The role can only be bound to scopes where the user manages roles, and cannot grant more than the user holds
+	scopeIDs := make([]string, len(scopePointers))
+	for i, scope := range scopePointers {
+		scopeIDs[i] = scope.ID
+	}
+	if !checkManageScopes(c, user, scopeIDs, ErrNoPermissionCreate) {
+		return
+	}
+	if !checkGrant(c, user, boundGrant(createRoleRequest.Permission, scopeIDs, nil), "", ErrCannotGrant) {
+		return
+	}
+
 	// Create the role
 	role := models.Role{
 		Name:        createRoleRequest.Name,
@@ -68,13 +82,33 @@ func CreateRole(c *gin.Context) {
 	c.JSON(http.StatusCreated, role)
 }
 
+// preloadHolders preloads the users holding roles, without their password
+func preloadHolders(db *gorm.DB) *gorm.DB {
+	return db.Preload("Users", func(db *gorm.DB) *gorm.DB {
+		return db.Omit("password")
+	})
+}
+
+// roleSortKeys are the sort keys accepted by the role list
+var roleSortKeys = map[string]string{
+	"name":        "name",
+	"permissions": "permissions",
+}
+
 // GetAllRoles retrieves all roles
 // @Summary Get all Roles
-// @Description Get all Roles
+// @Description Get a page of the Roles the user manages: every role for owners, otherwise the roles bound to the scopes
+// @Description where the user holds the ROLES permission and the roles without scopes
 // @Tags Roles
 // @Accept json
 // @Produce json
-// @Success 200 {array} models.Role
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param sort query string false "Sort keys among name, permissions, prefixed by - to sort descending"
+// @Param q query string false "Text searched in the name"
+// @Param scope_id query string false "Only roles attached to this scope"
+// @Success 200 {object} pagination.Page{items=[]models.Role}
+// @Failure 400 {object} map[string]string
 // @Router /roles [get]
 // @Security Bearer
 func GetAllRoles(c *gin.Context) {
@@ -84,46 +118,77 @@ func GetAllRoles(c *gin.Context) {
 	}
 
 	// Check permissions
-	if !permissions.RolesHavePermission(user.Roles, permissions.ROLES) {
-		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionView)
+	if !checkManageScopes(c, user, []string{}, ErrNoPermissionView) {
+		return
+	}
+
+	params, err := pagination.Parse(c, roleSortKeys, "name")
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidListParams)
 		return
 	}
 
+	query := pagination.Search(database.DB.Model(&models.Role{}), c.Query("q"), "name")
+	if scopeID := c.Query("scope_id"); scopeID != "" {
+		query = query.Where("id IN (?)", database.DB.Table("role_scopes").Select("role_id").Where("scope_id = ?", scopeID))
+	}
+	if !permissions.IsOwner(user) {
+		query = query.Where("id IN (?) OR id NOT IN (?)",
+			database.DB.Table("role_scopes").Select("role_id").Where("scope_id IN (?)", hierarchy.ScopesWith(user.ID, permissions.ROLES)),
+			database.DB.Table("role_scopes rs").Select("rs.role_id").Joins("JOIN scopes s ON s.id = rs.scope_id AND s.deleted_at IS NULL"),
+		)
+	}
+
 	var roles []models.Role
-	if err := database.DB.Preload("Users").Preload("Scopes").Find(&roles).Error; err != nil {
+	page, err := pagination.Find(query, params, &roles, func(db *gorm.DB) *gorm.DB {
+		return preloadHolders(db).Preload("Scopes")
+	})
+	if err != nil {
 		respondWithError(c, http.StatusInternalServerError, "Failed to fetch roles")
 		return
 	}
 	
-	c.JSON(http.StatusOK, roles)
+	c.JSON(http.StatusOK, page)
 }
 
 // GetRoleByID retrieves a role by its ID
 // @Summary Get a Role by ID
-// @Description Get a Role by ID
+// @Description Get a Role by ID, only accessible to users holding the ROLES permission in the scopes of the role
 // @Tags Roles
 // @Accept json
 // @Produce json
 // @Param role_id path string true "Role ID"
 // @Success 200 {object} models.Role
+// @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Router /roles/{role_id} [get]
 // @Security Bearer
 func GetRoleByID(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
 	roleID := c.Param("role_id")
 
 	var role models.Role
-	if err := database.DB.Where("id = ?", roleID).Preload("Users").Preload("Scopes").First(&role).Error; err != nil {
+	if err := preloadHolders(database.DB.Where("id = ?", roleID)).Preload("Scopes").First(&role).Error; err != nil {
 		respondWithError(c, http.StatusNotFound, ErrRoleNotFound)
 		return
 	}
 
+	if !checkManageRole(c, user, role.ID, ErrNoPermissionView) {
+		return
+	}
+
 	c.JSON(http.StatusOK, role)
 }
 
 // UpdateRoleByID updates a role by its ID
 // @Summary Update a Role by ID
-// @Description Update a Role by ID
+// @Description Update a Role by ID. The user must hold the ROLES permission in the scopes of the role before and after the update
+// @Description and the permissions the role grants before and after the update,
+// @Description and the OWNER permission cannot be removed from the role of the last owner
 // @Tags Roles
 // @Accept json
 // @Produce json
@@ -131,7 +196,9 @@ func GetRoleByID(c *gin.Context) {
 // @Param role body UpdateRoleRequest true "Role Update Data"
 // @Success 200 {object} models.Role
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /roles/{role_id} [put]
 // @Security Bearer
 func UpdateRoleByID(c *gin.Context) {
@@ -140,12 +207,6 @@ func UpdateRoleByID(c *gin.Context) {
 		return
 	}
 
-	// Check permissions
-	if !permissions.RolesHavePermission(user.Roles, permissions.ROLES) {
-		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionUpdate)
-		return
-	}
-
 	roleID := c.Param("role_id")
 
 	var role models.Role
@@ -154,6 +215,11 @@ func UpdateRoleByID(c *gin.Context) {
 		return
 	}
 
+	// Check permissions in the scopes the role is bound to
+	if !checkManageRole(c, user, role.ID, ErrNoPermissionUpdate) {
+		return
+	}
+
 	// Parse the update request
 	var updateRequest UpdateRoleRequest
 	if err := c.ShouldBindJSON(&updateRequest); err != nil {
@@ -161,44 +227,81 @@ func UpdateRoleByID(c *gin.Context) {
 		return
 	}
 
+	// The user must hold what the role grants before and after the update
+	if !checkRoleGrant(c, user, roleID, ErrCannotChangeRole) {
+		return
+	}
+
+	var bindings []models.RoleScope
+	if err := database.DB.Where("role_id = ?", roleID).Find(&bindings).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, "Failed to fetch the scopes of the role")
+		return
+	}
+	masks := make(map[string]*int, len(bindings))
+	scopeIDs := updateRequest.ScopesIds
+	for _, binding := range bindings {
+		masks[binding.ScopeID] = binding.Permissions
+		if updateRequest.ScopesIds == nil {
+			scopeIDs = append(scopeIDs, binding.ScopeID)
+		}
+	}
+
+	var liveScopeIDs []string
+	if len(scopeIDs) > 0 {
+		if err := database.DB.Model(&models.Scope{}).Where("id IN ?", scopeIDs).Pluck("id", &liveScopeIDs).Error; err != nil {
+			respondWithError(c, http.StatusBadRequest, "Invalid scope IDs")
+			return
+		}
+	}
+
+	if !checkManageScopes(c, user, liveScopeIDs, ErrNoPermissionUpdate) ||
+		!checkGrant(c, user, boundGrant(updateRequest.Permission, liveScopeIDs, masks), roleID, ErrCannotGrant) {
+		return
+	}
+
 	// Update basic fields
 	role.Name = updateRequest.Name
 	role.Permissions = updateRequest.Permission
 
-	// If scopes are specified, update the scopes relationship
-	if updateRequest.ScopesIds != nil {
-		// Clear existing associations
-		if err := database.DB.Model(&role).Association("Scopes").Clear(); err != nil {
-			respondWithError(c, http.StatusInternalServerError, "Failed to clear existing scope associations")
-			return
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(&role).Error; err != nil {
+			return err
 		}
 
-		// Add new scope associations if any scope IDs are provided
-		if len(updateRequest.ScopesIds) > 0 {
-			var scopes []models.Scope
-			if err := database.DB.Where("id IN (?)", updateRequest.ScopesIds).Find(&scopes).Error; err != nil {
-				respondWithError(c, http.StatusBadRequest, "Invalid scope IDs")
-				return
+		// If scopes are specified, replace the scopes of the role, keeping the permissions of the bindings that stay
+		if updateRequest.ScopesIds != nil {
+			removed := tx.Where("role_id = ?", roleID)
+			if len(updateRequest.ScopesIds) > 0 {
+				removed = removed.Where("scope_id NOT IN ?", updateRequest.ScopesIds)
 			}
-
-			var scopePointers []*models.Scope
-			for i := range scopes {
-				scopePointers = append(scopePointers, &scopes[i])
+			if err := removed.Delete(&models.RoleScope{}).Error; err != nil {
+				return err
 			}
 
-			// Set the new scopes
-			role.Scopes = scopePointers
+			if len(liveScopeIDs) > 0 {
+				if err := tx.Exec(
+					"INSERT INTO role_scopes (role_id, scope_id) SELECT ?, id FROM scopes WHERE id IN ? ON CONFLICT DO NOTHING",
+					roleID, liveScopeIDs,
+				).Error; err != nil {
+					return err
+				}
+			}
 		}
-	}
 
-	// Save the updated role with its scope relationships
-	if err := database.DB.Save(&role).Error; err != nil {
+		// Removing OWNER from the role must leave an owner
+		return escalation.EnsureOwnerRemains(tx)
+	})
+	if errors.Is(err, escalation.ErrLastOwner) {
+		respondLastOwner(c, user, roleID)
+		return
+	}
+	if err != nil {
 		respondWithError(c, http.StatusInternalServerError, "Failed to update role")
 		return
 	}
 
 	// Fetch the updated role with all its associations for the response
-	if err := database.DB.Preload("Users").Preload("Scopes").Where("id = ?", roleID).First(&role).Error; err != nil {
+	if err := preloadHolders(database.DB).Preload("Scopes").Where("id = ?", roleID).First(&role).Error; err != nil {
 		respondWithError(c, http.StatusInternalServerError, "Failed to fetch updated role")
 		return
 	}
@@ -208,13 +311,16 @@ func UpdateRoleByID(c *gin.Context) {
 
 // DeleteRole deletes a role and its associations
 // @Summary delete a role
-// @Description delete a role and cascade first to roles_scopes and user_roles
+// @Description delete a role and cascade first to roles_scopes and user_roles, only accessible to users holding the ROLES permission
+// @Description in the scopes of the role. The role of the last owner cannot be deleted
 // @Tags Roles
 // @Accept json
 // @Produce json
 // @Param role_id path string true "Role ID"
 // @Success 200 {object} models.Role
+// @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /roles/{role_id} [delete]
 // @Security Bearer
 func DeleteRole(c *gin.Context) {
@@ -223,12 +329,6 @@ func DeleteRole(c *gin.Context) {
 		return
 	}
 
-	// Check permissions
-    if !permissions.RolesHavePermission(user.Roles, permissions.ROLES) {
-        respondWithError(c, http.StatusUnauthorized, ErrNoPermissionDelete)
-        return
-    }
-
 	roleID := c.Param("role_id")
 
     var role models.Role
@@ -237,6 +337,14 @@ func DeleteRole(c *gin.Context) {
         return
     }
 
+    // The user must manage roles in the scopes of the role and hold what the role grants
+    if !checkManageRole(c, user, roleID, ErrNoPermissionDelete) {
+        return
+    }
+    if !checkRoleGrant(c, user, roleID, ErrCannotChangeRole) {
+        return
+    }
+
     // Start a transaction to ensure atomicity of operations
     tx := database.DB.Begin()
 
@@ -261,6 +369,17 @@ func DeleteRole(c *gin.Context) {
         return
     }
 
+    // The last owner role cannot be deleted
+    if err := escalation.EnsureOwnerRemains(tx); err != nil {
+        tx.Rollback()
+        if errors.Is(err, escalation.ErrLastOwner) {
+            respondLastOwner(c, user, roleID)
+            return
+        }
+        respondWithError(c, http.StatusInternalServerError, ErrFailedRoleDelete)
+        return
+    }
+
     // Commit the transaction
     if err := tx.Commit().Error; err != nil {
         respondWithError(c, http.StatusInternalServerError, ErrFailedTxCommit)
@@ -18,6 +18,12 @@ const (
 	ErrFailedRoleScopeRemove = "Failed to remove role associations from scopes"
 	ErrFailedRoleDelete      = "Failed to delete role"
 	ErrFailedTxCommit        = "Failed to commit transaction"
+	ErrInvalidListParams     = "Invalid pagination, sort or filter parameters"
+	ErrCannotGrant           = "You cannot grant permissions you do not hold"
+	ErrCannotChangeRole      = "You cannot change a role granting permissions you do not hold"
+	ErrCannotRevoke          = "You cannot revoke permissions you do not hold"
+	ErrLastOwner             = "The last owner cannot lose the OWNER permission"
+	ErrFailedPermissionCheck = "Failed to check the granted permissions"
 )
 
 // CreateRoleRequest modèle pour créer un rôle
@@ -4,21 +4,24 @@ import (
 	"api/database"
 	"api/middleware"
 	"api/models"
-	"api/utils/permissions"
+	"api/utils/escalation"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // AttachRoleToUser attaches a role to a user
 // @Summary Attach a Role to a User
-// @Description Attach a Role to a User
+// @Description Attach a Role to a User, the user must hold the ROLES permission in the scopes of the role and the permissions the role grants
 // @Tags Roles
 // @Accept json
 // @Produce json
 // @Param user_id path string true "User ID"
 // @Param role_id path string true "Role ID"
 // @Success 200 {object} models.User
+// @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Router /roles/attach/{role_id}/to-user/{user_id} [post]
 // @Security Bearer
@@ -28,12 +31,6 @@ func AttachRoleToUser(c *gin.Context) {
 		return
 	}
 
-	// Check permissions
-    if !permissions.RolesHavePermission(user.Roles, permissions.ROLES) {
-        respondWithError(c, http.StatusUnauthorized, ErrNoPermissionAttach)
-        return
-    }
-
 	// Get target user
 	targetUserId := c.Param("user_id")
 	var targetUser models.User
@@ -51,6 +48,15 @@ func AttachRoleToUser(c *gin.Context) {
 		respondWithError(c, http.StatusNotFound, ErrRoleNotFound)
 		return
 	}
+
+
+	// The user must manage roles in the scopes of the role and cannot grant more than they hold
+	if !checkManageRole(c, user, role.ID, ErrNoPermissionAttach) {
+		return
+	}
+	if !checkRoleGrant(c, user, role.ID, ErrCannotGrant) {
+		return
+	}
 	
 	// Attach role to user
 	targetUser.Roles = append(targetUser.Roles, &role)
@@ -64,14 +70,17 @@ func AttachRoleToUser(c *gin.Context) {
 
 // DetachRoleFromUser detaches a role from a user
 // @Summary Detach a Role from a User
-// @Description Detach a Role from a User
+// @Description Detach a Role from a User, the user must hold the ROLES permission in the scopes of the role and the permissions the role grants,
+// @Description and the last owner keeps their OWNER role
 // @Tags Roles
 // @Accept json
 // @Produce json
 // @Param user_id path string true "User ID"
 // @Param role_id path string true "Role ID"
 // @Success 200 {object} models.User
+// @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /roles/detach/{role_id}/from-user/{user_id} [delete]
 // @Security Bearer
 func DetachRoleFromUser(c *gin.Context) {
@@ -80,12 +89,6 @@ func DetachRoleFromUser(c *gin.Context) {
 		return
 	}
 
-	// Check permissions
-	if !permissions.RolesHavePermission(user.Roles, permissions.ROLES) {
-		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionDetach)
-		return
-	}
-
 	// Get target user
 	targetUserId := c.Param("user_id")
 	var targetUser models.User
@@ -103,16 +106,28 @@ func DetachRoleFromUser(c *gin.Context) {
 		respondWithError(c, http.StatusNotFound, ErrRoleNotFound)
 		return
 	}
+
+
+	// The user must manage roles in the scopes of the role and cannot revoke more than they hold
+	if !checkManageRole(c, user, role.ID, ErrNoPermissionDetach) {
+		return
+	}
+	if !checkRoleGrant(c, user, role.ID, ErrCannotRevoke) {
+		return
+	}
 	
-	// Find and remove role from user
-	for i, r := range targetUser.Roles {
-		if r.ID == role.ID {
-			targetUser.Roles = append(targetUser.Roles[:i], targetUser.Roles[i+1:]...)
-			break
+	// Remove role from user, the last owner keeps their role
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&targetUser).Association("Roles").Delete(&role); err != nil {
+			return err
 		}
+		return escalation.EnsureOwnerRemains(tx)
+	})
+	if errors.Is(err, escalation.ErrLastOwner) {
+		respondLastOwner(c, user, targetUser.ID)
+		return
 	}
-	
-	if err := database.DB.Save(&targetUser).Error; err != nil {
+	if err != nil {
 		respondWithError(c, http.StatusInternalServerError, "Failed to detach role from user")
 		return
 	}
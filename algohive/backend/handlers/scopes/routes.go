@@ -21,10 +21,13 @@ func RegisterRoutes(r *gin.RouterGroup) {
 		
 		 // User-specific routes
 		scopes.GET("/user", GetUserScopes)
+		scopes.GET("/permissions", GetMyScopePermissions)
 		
 		// Routes for role relationships
 		scopes.GET("/roles", GetRoleScopes)
 		scopes.POST("/:scope_id/roles/:role_id", AttachScopeToRole)
 		scopes.DELETE("/:scope_id/roles/:role_id", DetachScopeFromRole)
+		scopes.GET("/:scope_id/roles", GetScopeRoles)
+		scopes.PUT("/:scope_id/roles/:role_id/permissions", UpdateScopeRolePermissions)
 	}
 }
@@ -4,19 +4,33 @@ import (
 	"api/database"
 	"api/middleware"
 	"api/models"
+	"api/utils/hierarchy"
+	"api/utils/pagination"
 	"api/utils/permissions"
+	"api/utils/trash"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
+// scopeSortKeys are the sort keys accepted by the scope list
+var scopeSortKeys = map[string]string{
+	"name": "name",
+}
+
 // GetAllScopes retrieves all scopes
 // @Summary Get all scopes
-// @Description Get all scopes, only accessible to users with the SCOPES permission
+// @Description Get a page of the scopes where the user holds the SCOPES permission, every scope for owners
 // @Tags Scopes
 // @Accept json
 // @Produce json
-// @Success 200 {array} models.Scope
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param sort query string false "Sort key (name), prefixed by - to sort descending"
+// @Param q query string false "Text searched in the name and description"
+// @Success 200 {object} pagination.Page{items=[]models.Scope}
+// @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Router /scopes [get]
 // @Security Bearer
@@ -26,23 +40,34 @@ func GetAllScopes(c *gin.Context) {
 		return
 	}
 
-	if !permissions.RolesHavePermission(user.Roles, permissions.SCOPES) {
-		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionView)
+	if !checkManageScope(c, user, []string{}, ErrNoPermissionView) {
 		return
 	}
 
+	params, err := pagination.Parse(c, scopeSortKeys, "name")
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidListParams)
+		return
+	}
+
+	query := pagination.Search(database.DB.Model(&models.Scope{}), c.Query("q"), "name", "description")
+	if !permissions.IsOwner(user) {
+		query = query.Where("id IN (?)", hierarchy.ScopesWith(user.ID, permissions.SCOPES))
+	}
+
 	var scopes []models.Scope
-	if err := database.DB.Find(&scopes).Error; err != nil {
+	page, err := pagination.Find(query, params, &scopes)
+	if err != nil {
 		respondWithError(c, http.StatusInternalServerError, ErrFailedGetScopes)
 		return
 	}
 
-	c.JSON(http.StatusOK, scopes)
+	c.JSON(http.StatusOK, page)
 }
 
 // GetScope retrieves a scope by ID
 // @Summary Get a scope
-// @Description Get a scope, only accessible to users with the SCOPES permission
+// @Description Get a scope, only accessible to users with the SCOPES permission in the scope
 // @Tags Scopes
 // @Accept json
 // @Produce json
@@ -58,11 +83,6 @@ func GetScope(c *gin.Context) {
 		return
 	}
 
-	if !permissions.RolesHavePermission(user.Roles, permissions.SCOPES) {
-		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionView)
-		return
-	}
-
 	scopeID := c.Param("scope_id")
 	var scope models.Scope
 	
@@ -71,12 +91,16 @@ func GetScope(c *gin.Context) {
 		return
 	}
 
+	if !checkManageScope(c, user, []string{scope.ID}, ErrNoPermissionView) {
+		return
+	}
+
 	c.JSON(http.StatusOK, scope)
 }
 
 // CreateScope creates a new scope
 // @Summary Create a scope
-// @Description Create a scope, only accessible to users with the SCOPES permission
+// @Description Create a scope, only accessible to owners as the new scope is bound to no role
 // @Tags Scopes
 // @Accept json
 // @Produce json
@@ -92,7 +116,8 @@ func CreateScope(c *gin.Context) {
 		return
 	}
 
-	if !permissions.RolesHavePermission(user.Roles, permissions.SCOPES) {
+	// A new scope is bound to no role, only owners could manage it
+	if !permissions.IsOwner(user) {
 		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionCreate)
 		return
 	}
@@ -144,7 +169,7 @@ func CreateScope(c *gin.Context) {
 
 // UpdateScope updates an existing scope
 // @Summary Update a scope
-// @Description Update a scope, only accessible to users with the SCOPES permission
+// @Description Update a scope, only accessible to users with the SCOPES permission in the scope
 // @Tags Scopes
 // @Accept json
 // @Produce json
@@ -161,11 +186,6 @@ func UpdateScope(c *gin.Context) {
 		return
 	}
 	
-	if !permissions.RolesHavePermission(user.Roles, permissions.SCOPES) {
-		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionUpdate)
-		return
-	}
-
 	scopeID := c.Param("scope_id")
 	var scope models.Scope
 	if err := database.DB.Where("id = ?", scopeID).First(&scope).Error; err != nil {
@@ -173,6 +193,10 @@ func UpdateScope(c *gin.Context) {
 		return
 	}
 
+	if !checkManageScope(c, user, []string{scope.ID}, ErrNoPermissionUpdate) {
+		return
+	}
+
 	var updateScopeReq CreateScopeRequest
 	if err := c.ShouldBindJSON(&updateScopeReq); err != nil {
 		respondWithError(c, http.StatusBadRequest, err.Error())
@@ -215,9 +239,9 @@ func UpdateScope(c *gin.Context) {
 	c.JSON(http.StatusOK, scope)
 }
 
-// DeleteScope deletes a scope
+// DeleteScope moves a scope to the trash
 // @Summary Delete a scope
-// @Description Delete a scope, only accessible to users with the SCOPES permission
+// @Description Move a scope without live groups to the trash, only accessible to users with the SCOPES permission in the scope
 // @Tags Scopes
 // @Accept json
 // @Produce json
@@ -233,11 +257,6 @@ func DeleteScope(c *gin.Context) {
         return
     }
 
-    if !permissions.RolesHavePermission(user.Roles, permissions.SCOPES) {
-        respondWithError(c, http.StatusUnauthorized, ErrNoPermissionDelete)
-        return
-    }
-
     scopeID := c.Param("scope_id")
     var scope models.Scope
     if err := database.DB.Where("id = ?", scopeID).First(&scope).Error; err != nil {
@@ -245,6 +264,10 @@ func DeleteScope(c *gin.Context) {
         return
     }
 
+    if !checkManageScope(c, user, []string{scope.ID}, ErrNoPermissionDelete) {
+        return
+    }
+
     // Check if any groups use this scope
     var groupCount int64
     if err := database.DB.Model(&models.Group{}).Where("scope_id = ?", scope.ID).Count(&groupCount).Error; err != nil {
@@ -257,30 +280,13 @@ func DeleteScope(c *gin.Context) {
         return
     }
 
-    // Transaction to ensure atomicity
-    tx := database.DB.Begin()
-
-    // Delete associations before deleting the scope
-    if err := tx.Model(&scope).Association("Catalogs").Clear(); err != nil {
-        tx.Rollback()
-        respondWithError(c, http.StatusInternalServerError, ErrFailedClearAssoc+err.Error())
-        return
-    }
-
-    // Delete role associations
-    if err := tx.Model(&scope).Association("Roles").Clear(); err != nil {
-        tx.Rollback()
-        respondWithError(c, http.StatusInternalServerError, ErrFailedClearAssoc+err.Error())
-        return
-    }
-
-    // Delete the scope
-    if err := tx.Delete(&scope).Error; err != nil {
-        tx.Rollback()
+    // Roles and catalogs are detached until the scope is restored
+    if err := database.DB.Transaction(func(tx *gorm.DB) error {
+        return trash.MoveToTrash(tx, models.TrashScope, scope.ID, scope.Name, user.ID)
+    }); err != nil {
         respondWithError(c, http.StatusInternalServerError, ErrFailedDeleteScope+err.Error())
         return
     }
 
-    tx.Commit()
     c.Status(http.StatusNoContent)
 }
\ No newline at end of file
@@ -0,0 +1,212 @@
+package scopes
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/utils/audit"
+	"api/utils/escalation"
+	"api/utils/hierarchy"
+	"api/utils/permissions"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetScopeRoles retrieves the roles attached to a scope with the permissions they grant there
+// @Summary Get the roles of a scope
+// @Description Get the roles attached to a scope with the permission mask of each binding, null when the role grants its own permissions,
+// @Description and the resulting permissions in the scope. Only accessible to users with the SCOPES permission in the scope
+// @Tags Scopes
+// @Produce json
+// @Param scope_id path string true "Scope ID"
+// @Success 200 {array} ScopeRole
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /scopes/{scope_id}/roles [get]
+// @Security Bearer
+func GetScopeRoles(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	var scope models.Scope
+	if err := database.DB.Where("id = ?", c.Param("scope_id")).First(&scope).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrScopeNotFound)
+		return
+	}
+
+	if !checkManageScope(c, user, []string{scope.ID}, ErrNoPermissionView) {
+		return
+	}
+
+	var bindings []models.RoleScope
+	if err := database.DB.Preload("Role").Where("scope_id = ?", scope.ID).Find(&bindings).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedGetPermissions)
+		return
+	}
+
+	roles := make([]ScopeRole, 0, len(bindings))
+	for _, binding := range bindings {
+		if binding.Role == nil {
+			continue
+		}
+		effective := binding.Role.Permissions
+		if binding.Permissions != nil {
+			effective = *binding.Permissions
+		}
+		roles = append(roles, ScopeRole{
+			RoleID:               binding.RoleID,
+			Name:                 binding.Role.Name,
+			RolePermissions:      binding.Role.Permissions,
+			Permissions:          binding.Permissions,
+			EffectivePermissions: effective,
+		})
+	}
+
+	c.JSON(http.StatusOK, roles)
+}
+
+// checkManageScope checks that the user holds the SCOPES permission in every live scope of a list, otherwise the request is rejected
+// user: the authenticated user
+// scopeIDs: IDs of the scopes, see escalation.CanManageIn
+// message: the error message of a rejection
+// returns: true when the operation can proceed
+func checkManageScope(c *gin.Context, user models.User, scopeIDs []string, message string) bool {
+	allowed, err := escalation.CanManageIn(user, permissions.SCOPES, scopeIDs)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedPermissionCheck)
+		return false
+	}
+	if !allowed {
+		respondWithError(c, http.StatusUnauthorized, message)
+		return false
+	}
+	return true
+}
+
+// checkBindingGrant checks that the user holds a permission mask in a scope, so they can make a role grant it there
+// or stop it from granting it, otherwise the attempt is logged and rejected
+// user: the authenticated user
+// scopeID: ID of the scope
+// mask: the permissions the binding grants in the scope
+// roleID: ID of the bound role
+// message: the error message of a rejection
+// returns: true when the operation can proceed
+func checkBindingGrant(c *gin.Context, user models.User, scopeID string, mask int, roleID string, message string) bool {
+	allowed, err := escalation.CanGrant(user, escalation.Grant{Scopes: map[string]int{scopeID: mask}})
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedPermissionCheck)
+		return false
+	}
+	if !allowed {
+		escalation.Deny(c, audit.ActionGrantDenied, user.ID, roleID)
+		respondWithError(c, http.StatusUnauthorized, message)
+		return false
+	}
+	return true
+}
+
+// UpdateScopeRolePermissions sets the permissions a role grants in a scope
+// @Summary Set the permissions of a role in a scope
+// @Description Set the permission mask a role attached to a scope grants there, in place of the permissions of the role.
+// @Description A null mask grants the permissions of the role again. OWNER in a mask makes the holders owners of the scope only.
+// @Description Only accessible to users with the SCOPES permission in the scope who hold both the current and the new permissions in the scope
+// @Tags Scopes
+// @Accept json
+// @Produce json
+// @Param scope_id path string true "Scope ID"
+// @Param role_id path string true "Role ID"
+// @Param request body ScopeRolePermissionsRequest true "Permission mask"
+// @Success 200 {object} models.RoleScope
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /scopes/{scope_id}/roles/{role_id}/permissions [put]
+// @Security Bearer
+func UpdateScopeRolePermissions(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	var req ScopeRolePermissionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Permissions != nil && (*req.Permissions < 0 || *req.Permissions&^permissions.GetAdminPermissions() != 0) {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidPermissions)
+		return
+	}
+
+	var scope models.Scope
+	if err := database.DB.Where("id = ?", c.Param("scope_id")).First(&scope).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrScopeNotFound)
+		return
+	}
+
+	if !checkManageScope(c, user, []string{scope.ID}, ErrNoPermissionAttach) {
+		return
+	}
+
+	var binding models.RoleScope
+	if err := database.DB.Where("scope_id = ? AND role_id = ?", scope.ID, c.Param("role_id")).First(&binding).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrRoleNotAttached)
+		return
+	}
+
+	var role models.Role
+	if err := database.DB.Where("id = ?", binding.RoleID).First(&role).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrRoleNotFound)
+		return
+	}
+
+	// The mask in place and the new one are both checked, so a role above the user can be neither raised nor lowered
+	current, next := role.Permissions, role.Permissions
+	if binding.Permissions != nil {
+		current = *binding.Permissions
+	}
+	if req.Permissions != nil {
+		next = *req.Permissions
+	}
+	if !checkBindingGrant(c, user, scope.ID, current, role.ID, ErrCannotRevoke) ||
+		!checkBindingGrant(c, user, scope.ID, next, role.ID, ErrCannotGrant) {
+		return
+	}
+
+	if err := database.DB.Model(&models.RoleScope{}).
+		Where("scope_id = ? AND role_id = ?", binding.ScopeID, binding.RoleID).
+		Update("permissions", req.Permissions).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedUpdatePerms)
+		return
+	}
+	binding.Permissions = req.Permissions
+
+	c.JSON(http.StatusOK, binding)
+}
+
+// GetMyScopePermissions retrieves the permissions of the authenticated user in each scope of their roles
+// @Summary Get my permissions by scope
+// @Description Get the permissions the roles of the authenticated user grant in each of their scopes, by scope ID
+// @Tags Scopes
+// @Produce json
+// @Success 200 {object} map[string]int
+// @Failure 401 {object} map[string]string
+// @Router /scopes/permissions [get]
+// @Security Bearer
+func GetMyScopePermissions(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	scopePermissions, err := hierarchy.UserScopePermissions(user.ID)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedGetPermissions)
+		return
+	}
+
+	c.JSON(http.StatusOK, scopePermissions)
+}
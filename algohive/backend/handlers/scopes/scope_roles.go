@@ -13,7 +13,8 @@ import (
 
 // AttachScopeToRole attaches a scope to a role
 // @Summary Attach the scope to a role
-// @Description Attach the scope to a role, only accessible to users with the SCOPES permission
+// @Description Attach the scope to a role, only accessible to users with the SCOPES permission in the scope
+// @Description who hold the permissions of the role in the scope
 // @Tags Scopes
 // @Accept json
 // @Produce json
@@ -30,11 +31,6 @@ func AttachScopeToRole(c *gin.Context) {
 		return
 	}
 
-	if !permissions.RolesHavePermission(user.Roles, permissions.SCOPES) {
-		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionAttach)
-		return
-	}
-
 	scopeID := c.Param("scope_id")
 	var scope models.Scope
 	if err := database.DB.Where("id = ?", scopeID).First(&scope).Error; err != nil {
@@ -42,6 +38,10 @@ func AttachScopeToRole(c *gin.Context) {
 		return
 	}
 
+	if !checkManageScope(c, user, []string{scope.ID}, ErrNoPermissionAttach) {
+		return
+	}
+
 	roleID := c.Param("role_id")
 	var role models.Role
 	if err := database.DB.Where("id = ?", roleID).First(&role).Error; err != nil {
@@ -49,6 +49,10 @@ func AttachScopeToRole(c *gin.Context) {
 		return
 	}
 
+	if !checkBindingGrant(c, user, scope.ID, role.Permissions, role.ID, ErrCannotGrant) {
+		return
+	}
+
 	if err := database.DB.Model(&scope).Association("Roles").Append(&role); err != nil {
 		respondWithError(c, http.StatusInternalServerError, ErrFailedAttachRole+err.Error())
 		return
@@ -59,7 +63,8 @@ func AttachScopeToRole(c *gin.Context) {
 
 // DetachScopeFromRole detaches a scope from a role
 // @Summary Detach the scope from a role
-// @Description Detach the scope from a role, only accessible to users with the SCOPES permission
+// @Description Detach the scope from a role, only accessible to users with the SCOPES permission in the scope
+// @Description who hold the permissions the role grants in the scope
 // @Tags Scopes
 // @Accept json
 // @Produce json
@@ -76,11 +81,6 @@ func DetachScopeFromRole(c *gin.Context) {
 		return
 	}
 
-	if !permissions.RolesHavePermission(user.Roles, permissions.SCOPES) {
-		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionDetach)
-		return
-	}
-
 	scopeID := c.Param("scope_id")
 	var scope models.Scope
 	if err := database.DB.Where("id = ?", scopeID).First(&scope).Error; err != nil {
@@ -88,6 +88,10 @@ func DetachScopeFromRole(c *gin.Context) {
 		return
 	}
 
+	if !checkManageScope(c, user, []string{scope.ID}, ErrNoPermissionDetach) {
+		return
+	}
+
 	roleID := c.Param("role_id")
 	var role models.Role
 	if err := database.DB.Where("id = ?", roleID).First(&role).Error; err != nil {
@@ -95,6 +99,21 @@ func DetachScopeFromRole(c *gin.Context) {
 		return
 	}
 
+	var bindings []models.RoleScope
+	if err := database.DB.Where("scope_id = ? AND role_id = ?", scope.ID, role.ID).Find(&bindings).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedPermissionCheck)
+		return
+	}
+	for _, binding := range bindings {
+		mask := role.Permissions
+		if binding.Permissions != nil {
+			mask = *binding.Permissions
+		}
+		if !checkBindingGrant(c, user, scope.ID, mask, role.ID, ErrCannotRevoke) {
+			return
+		}
+	}
+
 	if err := database.DB.Model(&scope).Association("Roles").Delete(&role); err != nil {
 		respondWithError(c, http.StatusInternalServerError, ErrFailedDetachRole+err.Error())
 		return
@@ -25,6 +25,14 @@ const (
 	ErrFailedAttachRole       = "Failed to attach scope to role: "
 	ErrFailedDetachRole       = "Failed to detach scope from role: "
 	ErrFailedGetScopes        = "Failed to get scopes"
+	ErrInvalidListParams      = "Invalid pagination, sort or filter parameters"
+	ErrRoleNotAttached        = "The role is not attached to this scope"
+	ErrInvalidPermissions     = "Invalid permission mask"
+	ErrFailedUpdatePerms      = "Failed to update the permissions of the role in the scope"
+	ErrFailedGetPermissions   = "Failed to get the permissions"
+	ErrCannotGrant            = "You cannot grant permissions you do not hold"
+	ErrCannotRevoke           = "You cannot revoke permissions you do not hold"
+	ErrFailedPermissionCheck  = "Failed to check the granted permissions"
 )
 
 // CreateScopeRequest modèle pour créer un scope
@@ -34,6 +42,20 @@ type CreateScopeRequest struct {
 	CatalogsIds []string `json:"catalogs_ids" binding:"required"`
 }
 
+// ScopeRolePermissionsRequest sets the permission mask a role grants in a scope, null to grant the permissions of the role
+type ScopeRolePermissionsRequest struct {
+	Permissions *int `json:"permissions"`
+}
+
+// ScopeRole is a role attached to a scope with the permissions it grants there
+type ScopeRole struct {
+	RoleID               string `json:"role_id"`
+	Name                 string `json:"name"`
+	RolePermissions      int    `json:"role_permissions"`
+	Permissions          *int   `json:"permissions"`
+	EffectivePermissions int    `json:"effective_permissions"`
+}
+
 // respondWithError envoie une réponse d'erreur standardisée
 func respondWithError(c *gin.Context, status int, message string) {
 	c.JSON(status, gin.H{"error": message})
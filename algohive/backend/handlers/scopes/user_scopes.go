@@ -13,7 +13,7 @@ import (
 
 // GetUserScopes retrieves all scopes that the user has access to
 // @Summary Get all scopes that the user has access to
-// @Description Get all scopes that the user has access to (based on roles) if the user has the SCOPES permission we return all scopes
+// @Description Get all scopes that the user has access to (based on roles), owners get all scopes
 // @Tags Scopes
 // @Accept json
 // @Produce json
@@ -29,8 +29,8 @@ func GetUserScopes(c *gin.Context) {
 
 	var scopes []models.Scope
 	
-	// If the user has the SCOPES permission, return all scopes
-	if permissions.RolesHavePermission(user.Roles, permissions.SCOPES) {
+	// Owners get all scopes
+	if permissions.IsOwner(user) {
 		if err := database.DB.Preload("Catalogs").Preload("Roles").Preload("Roles.Scopes").Preload("Roles.Scopes.Groups").Preload("Groups").Find(&scopes).Error; err != nil {
 			respondWithError(c, http.StatusInternalServerError, ErrFailedGetScopes)
 			return
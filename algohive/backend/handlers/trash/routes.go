@@ -0,0 +1,19 @@
+package trash
+
+import (
+	"api/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes registers all routes related to the trash
+// r: the RouterGroup to which the routes are added
+func RegisterRoutes(r *gin.RouterGroup) {
+	trash := r.Group("/trash")
+	trash.Use(middleware.AuthMiddleware())
+	{
+		trash.GET("/:type", GetTrash)
+		trash.POST("/:type/:id/restore", RestoreItem)
+		trash.DELETE("/:type/:id", PurgeItem)
+	}
+}
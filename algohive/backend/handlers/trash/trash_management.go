@@ -0,0 +1,186 @@
+package trash
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/utils/pagination"
+	"api/utils/permissions"
+	"api/utils/trash"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// trashSortKeys are the sort keys accepted by the trash list
+var trashSortKeys = map[string]string{
+	"deleted_at": "deleted_at",
+	"label":      "label",
+}
+
+// checkTrashType checks the type of the request and the permission of the user on it
+// returns: the type, or false once the error response is sent
+func checkTrashType(c *gin.Context, user models.User) (string, bool) {
+	entityType := c.Param("type")
+	permission, ok := typePermissions[entityType]
+	if !ok {
+		respondWithError(c, http.StatusBadRequest, ErrUnknownType)
+		return "", false
+	}
+
+	if !permissions.RolesHavePermission(user.Roles, permission) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermission)
+		return "", false
+	}
+
+	return entityType, true
+}
+
+// findTrashedItem loads the trash record of an entity
+// returns: false once the error response is sent
+func findTrashedItem(c *gin.Context, entityType string, item *models.TrashedItem) bool {
+	if err := database.DB.Where("entity_type = ? AND entity_id = ?", entityType, c.Param("id")).First(item).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrItemNotFound)
+		return false
+	}
+	return true
+}
+
+// GetTrash retrieves the deleted entities of a type
+// @Summary Get the trash
+// @Description Get a page of the deleted users, groups, competitions or scopes, purged after TRASH_RETENTION_DAYS.
+// @Description Users require the OWNER permission, groups GROUPS, competitions COMPETITIONS and scopes SCOPES
+// @Tags Trash
+// @Accept json
+// @Produce json
+// @Param type path string true "user, group, competition or scope"
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param sort query string false "Sort key (deleted_at, label), prefixed by - to sort descending"
+// @Param q query string false "Text searched in the label"
+// @Success 200 {object} pagination.Page{items=[]models.TrashedItem}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /trash/{type} [get]
+// @Security Bearer
+func GetTrash(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	entityType, ok := checkTrashType(c, user)
+	if !ok {
+		return
+	}
+
+	params, err := pagination.Parse(c, trashSortKeys, "-deleted_at")
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidListParams)
+		return
+	}
+
+	query := database.DB.Model(&models.TrashedItem{}).Where("entity_type = ?", entityType)
+	query = pagination.Search(query, c.Query("q"), "label")
+
+	var items []models.TrashedItem
+	page, err := pagination.Find(query, params, &items)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedGetTrash)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// RestoreItem restores a deleted entity
+// @Summary Restore an item of the trash
+// @Description Restore a deleted entity and reattach the associations that still exist: roles, groups and teams of a user,
+// @Description users and competitions of a group, groups of a competition, roles and catalogs of a scope
+// @Tags Trash
+// @Accept json
+// @Produce json
+// @Param type path string true "user, group, competition or scope"
+// @Param id path string true "ID of the deleted entity"
+// @Success 204 {object} string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /trash/{type}/{id}/restore [post]
+// @Security Bearer
+func RestoreItem(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	entityType, ok := checkTrashType(c, user)
+	if !ok {
+		return
+	}
+
+	var item models.TrashedItem
+	if !findTrashedItem(c, entityType, &item) {
+		return
+	}
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return trash.Restore(tx, &item)
+	}); err != nil {
+		switch {
+		case errors.Is(err, trash.ErrConflict):
+			respondWithError(c, http.StatusConflict, ErrRestoreConflict)
+		case errors.Is(err, trash.ErrParentTrashed):
+			respondWithError(c, http.StatusConflict, ErrParentTrashed)
+		default:
+			respondWithError(c, http.StatusInternalServerError, ErrFailedRestore)
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PurgeItem permanently deletes an entity of the trash
+// @Summary Purge an item of the trash
+// @Description Permanently delete an entity of the trash with the data depending on it: tries and submissions of a user,
+// @Description tries, teams and puzzles of a competition, time overrides of a group, trashed groups of a scope
+// @Tags Trash
+// @Accept json
+// @Produce json
+// @Param type path string true "user, group, competition or scope"
+// @Param id path string true "ID of the deleted entity"
+// @Success 204 {object} string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /trash/{type}/{id} [delete]
+// @Security Bearer
+func PurgeItem(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	entityType, ok := checkTrashType(c, user)
+	if !ok {
+		return
+	}
+
+	var item models.TrashedItem
+	if !findTrashedItem(c, entityType, &item) {
+		return
+	}
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return trash.Purge(tx, &item)
+	}); err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedPurge)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,34 @@
+package trash
+
+import (
+	"api/models"
+	"api/utils/permissions"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Error messages constants
+const (
+	ErrUnknownType       = "Unknown trash type, expected user, group, competition or scope"
+	ErrItemNotFound      = "Item not found in the trash"
+	ErrNoPermission      = "User does not have permission to manage this trash"
+	ErrInvalidListParams = "Invalid pagination, sort or filter parameters"
+	ErrFailedGetTrash    = "Failed to get the trash"
+	ErrRestoreConflict   = "Cannot restore: a live entity uses the same name or email"
+	ErrParentTrashed     = "Cannot restore: the scope of the group is in the trash, restore it first"
+	ErrFailedRestore     = "Failed to restore the item"
+	ErrFailedPurge       = "Failed to purge the item"
+)
+
+// typePermissions is the permission required to manage each type of the trash
+var typePermissions = map[string]int{
+	models.TrashUser:        permissions.OWNER,
+	models.TrashGroup:       permissions.GROUPS,
+	models.TrashCompetition: permissions.COMPETITIONS,
+	models.TrashScope:       permissions.SCOPES,
+}
+
+// respondWithError sends a JSON response with an error message
+func respondWithError(c *gin.Context, status int, message string) {
+	c.JSON(status, gin.H{"error": message})
+}
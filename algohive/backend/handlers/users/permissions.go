@@ -3,31 +3,26 @@ package users
 import (
 	"api/database"
 	"api/models"
+	"api/utils/audit"
+	"api/utils/escalation"
+	"api/utils/hierarchy"
 	"api/utils/permissions"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
 )
 
-// UserOwnsTargetGroups checks if the authenticated user owns at least one group
-// to which the target user belongs
+// UserOwnsTargetGroups checks if the roles of the authenticated user grant a permission in the scope
+// of at least one group to which the target user belongs, the groups of a scope owning their subgroups
 // userID: ID of the authenticated user
 // targetUserID: ID of the target user
-// returns: true if the authenticated user owns at least one group of the target user
-func UserOwnsTargetGroups(userID string, targetUserID string) bool {
+// permission: the permission required in the scope of the group
+// returns: true if the authenticated user holds the permission over at least one group of the target user
+func UserOwnsTargetGroups(userID string, targetUserID string, permission int) bool {
     var count int64
-    err := database.DB.Raw(`
-        SELECT COUNT(DISTINCT g1.id) 
-        FROM groups g1
-        JOIN user_groups ug ON g1.id = ug.group_id
-        WHERE ug.user_id = ? 
-        AND g1.id IN (
-            SELECT DISTINCT g2.id
-            FROM groups g2
-            JOIN scopes s ON g2.scope_id = s.id
-            JOIN role_scopes rs ON s.id = rs.scope_id
-            JOIN roles r ON rs.role_id = r.id
-            JOIN user_roles ur ON r.id = ur.role_id
-            WHERE ur.user_id = ?
-        )
-    `, targetUserID, userID).Count(&count).Error
+    err := database.DB.Table("user_groups").
+        Where("user_id = ? AND group_id IN (?)", targetUserID, hierarchy.ManagedGroupsWith(userID, permission)).
+        Count(&count).Error
 
     if err != nil {
         return false
@@ -39,14 +34,88 @@ func UserOwnsTargetGroups(userID string, targetUserID string) bool {
 // HasPermissionForUser checks if the user has the necessary permissions to act on the target user
 // user: the authenticated user
 // targetUserID: ID of the target user
-// requiredPermission: required permission if the user is not the owner of the group
+// requiredPermission: permission required in the scope of one of the groups of the target user if the user is not an owner
 func HasPermissionForUser(user models.User, targetUserID string, requiredPermission int) bool {
     // Owners have all permissions
     if permissions.RolesHavePermission(user.Roles, permissions.OWNER) {
         return true
     }
     
-    // Otherwise check if the user owns a group of the target user
-    return UserOwnsTargetGroups(user.ID, targetUserID) || 
-           permissions.RolesHavePermission(user.Roles, requiredPermission)
+    // Otherwise check the permissions granted in the scopes of the groups of the target user
+    return UserOwnsTargetGroups(user.ID, targetUserID, requiredPermission)
+}
+
+// userCanManageGroup checks if the user can manage a group: owners manage every group,
+// other users the groups of the scopes where their roles grant the GROUPS permission, and their subgroups
+// user: the authenticated user
+// groupID: ID of the group
+func userCanManageGroup(user models.User, groupID string) bool {
+    if permissions.RolesHavePermission(user.Roles, permissions.OWNER) {
+        return true
+    }
+
+    var count int64
+    if err := database.DB.Raw(
+        "SELECT COUNT(*) FROM (?) managed WHERE id = ?", hierarchy.ManagedGroupsWith(user.ID, permissions.GROUPS), groupID,
+    ).Scan(&count).Error; err != nil {
+        return false
+    }
+
+    return count > 0
+}
+
+
+// userCanManageRoles checks if the user holds the ROLES permission in every live scope roles are bound to
+// user: the authenticated user
+// roleIDs: IDs of the roles
+func userCanManageRoles(user models.User, roleIDs []string) bool {
+    allowed, err := escalation.CanManageRoles(user, roleIDs)
+    return err == nil && allowed
+}
+
+// checkRolesGrant checks that the user holds every permission granted by roles, otherwise the attempt is logged and rejected
+// user: the authenticated user
+// roleIDs: IDs of the roles given or taken away
+// targetID: ID of the user the operation is about, empty when it is not created yet
+// message: the error message of a rejection
+// returns: true when the operation can proceed
+func checkRolesGrant(c *gin.Context, user models.User, roleIDs []string, targetID string, message string) bool {
+    allowed, err := escalation.CanGrantRoles(user, roleIDs)
+    if err != nil {
+        respondWithError(c, http.StatusInternalServerError, ErrFailedPermissionCheck)
+        return false
+    }
+    if !allowed {
+        escalation.Deny(c, audit.ActionGrantDenied, user.ID, targetID)
+        respondWithError(c, http.StatusUnauthorized, message)
+        return false
+    }
+    return true
+}
+
+// checkCanManageUser checks that the user holds every permission of the roles of the target user,
+// otherwise the attempt is logged and rejected
+// user: the authenticated user
+// targetUserID: ID of the target user
+// returns: true when the operation can proceed
+func checkCanManageUser(c *gin.Context, user models.User, targetUserID string) bool {
+    allowed, err := escalation.CanManageUser(user, targetUserID)
+    if err != nil {
+        respondWithError(c, http.StatusInternalServerError, ErrFailedPermissionCheck)
+        return false
+    }
+    if !allowed {
+        escalation.Deny(c, audit.ActionGrantDenied, user.ID, targetUserID)
+        respondWithError(c, http.StatusUnauthorized, ErrCannotManageStaff)
+        return false
+    }
+    return true
+}
+
+// respondLastOwner logs and rejects an operation that would have removed the last owner
+// user: the authenticated user
+// targetID: ID of the user the operation is about, empty for a bulk operation
+func respondLastOwner(c *gin.Context, user models.User, targetID string) {
+    escalation.Deny(c, audit.ActionLastOwnerDenied, user.ID, targetID)
+    respondWithError(c, http.StatusConflict, ErrLastOwner)
 }
@@ -6,10 +6,12 @@ import (
 	"api/middleware"
 	"api/models"
 	"api/utils"
+	"api/utils/lifecycle"
 	"api/utils/permissions"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // GetUserProfile retrieves the authenticated user's profile
@@ -71,14 +73,17 @@ func UpdateUserProfile(c *gin.Context) {
 
 // UpdateTargetUserProfile updates the target user's profile
 // @Summary Update Target User Profile
-// @Description Update the profile information of the target user
+// @Description Update the profile information of the target user. Changing the expiry date of the account requires the OWNER permission over the user,
+// @Description a user blocked by the expiry job is unblocked once their account is no longer expired
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Param userId path string true "User ID"
-// @Param user body models.User true "User Profile"
+// @Param user body TargetUserUpdate true "User Profile"
 // @Success 200 {object} models.User
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Router /user/{id} [put]
 // @Security Bearer
@@ -108,18 +113,50 @@ func UpdateTargetUserProfile(c *gin.Context) {
 		return
 	}
 	
-	if err := c.ShouldBindJSON(&userUpdate); err != nil {
+	req := TargetUserUpdate{
+		Firstname: userUpdate.Firstname,
+		Lastname:  userUpdate.Lastname,
+		Email:     userUpdate.Email,
+		ExpiresAt: userUpdate.ExpiresAt,
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
 		respondWithError(c, http.StatusBadRequest, err.Error())
 		return
 	}
-	
-	userUpdate.ID = userId
-	
-	if err := database.DB.Save(&userUpdate).Error; err != nil {
+
+	// An expiry date blocks the account once reached, it is changed under the same conditions as blocking the user
+	expiryChanged := (req.ExpiresAt == nil) != (userUpdate.ExpiresAt == nil) ||
+		(req.ExpiresAt != nil && !req.ExpiresAt.Equal(*userUpdate.ExpiresAt))
+	if expiryChanged {
+		if !HasPermissionForUser(user, userUpdate.ID, permissions.OWNER) {
+			respondWithError(c, http.StatusUnauthorized, ErrNoPermissionExpiry)
+			return
+		}
+		if !checkCanManageUser(c, user, userUpdate.ID) {
+			return
+		}
+	}
+
+	userUpdate.Firstname = req.Firstname
+	userUpdate.Lastname = req.Lastname
+	userUpdate.Email = req.Email
+	userUpdate.ExpiresAt = req.ExpiresAt
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&userUpdate).Select("firstname", "lastname", "email", "expires_at").Updates(&userUpdate).Error; err != nil {
+			return err
+		}
+		if !expiryChanged {
+			return nil
+		}
+		_, err := lifecycle.ReactivateUsers(tx, []string{userUpdate.ID})
+		return err
+	}); err != nil {
 		respondWithError(c, http.StatusInternalServerError, "Failed to update profile")
 		return
 	}
-	
+
+	database.DB.Where("id = ?", userUpdate.ID).First(&userUpdate)
 	c.JSON(http.StatusOK, userUpdate)
 }
 
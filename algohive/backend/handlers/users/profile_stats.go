@@ -0,0 +1,143 @@
+package users
+
+import (
+	"api/database"
+	"api/middleware"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recentActivityLimit is the number of tries returned as recent activity
+const recentActivityLimit = 10
+
+// solveStreaks computes the current and longest runs of consecutive solve days
+// days: the distinct days with at least one solve, in ascending order
+func solveStreaks(days []time.Time, today time.Time) (current int, longest int) {
+	run := 0
+	for i, day := range days {
+		if i > 0 && day.Sub(days[i-1]) == 24*time.Hour {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+
+	// The current streak is still alive if the last solve was today or yesterday
+	if len(days) > 0 {
+		last := days[len(days)-1]
+		if today.Sub(last) <= 24*time.Hour {
+			current = run
+		}
+	}
+	return current, longest
+}
+
+// GetUserProfileStats retrieves the performance of the authenticated user across every competition
+// @Summary Get User Profile Statistics
+// @Description Get the solved puzzles by difficulty, average solve time, rank in each competition, solve streaks and recent activity of the authenticated user
+// @Tags Users
+// @Success 200 {object} ProfileStatsResponse
+// @Failure 401 {object} map[string]string
+// @Router /user/profile/stats [get]
+// @Security Bearer
+func GetUserProfileStats(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	stats := ProfileStatsResponse{
+		SolvedByDifficulty: map[string]int{},
+		Competitions:       []CompetitionRank{},
+		RecentActivity:     []RecentTry{},
+	}
+
+	var byDifficulty []struct {
+		PuzzleLvl string
+		Solved    int
+	}
+	if err := database.DB.Raw(`
+		SELECT puzzle_lvl, COUNT(*) AS solved
+		FROM tries
+		WHERE user_id = ? AND end_time IS NOT NULL
+		GROUP BY puzzle_lvl
+	`, user.ID).Scan(&byDifficulty).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedToGetStats)
+		return
+	}
+	for _, row := range byDifficulty {
+		stats.SolvedByDifficulty[row.PuzzleLvl] = row.Solved
+		stats.TotalSolved += row.Solved
+	}
+
+	database.DB.Raw(`
+		SELECT COALESCE(AVG(EXTRACT(EPOCH FROM end_time - start_time)), 0)
+		FROM tries
+		WHERE user_id = ? AND end_time IS NOT NULL
+	`, user.ID).Scan(&stats.AverageSolveTime)
+
+	// Rank the user (or their team) against every participant of the competitions they played,
+	// with the same ordering as the competition leaderboard
+	if err := database.DB.Raw(`
+		WITH played AS (
+			SELECT DISTINCT competition_id, COALESCE(team_id, user_id) AS participant
+			FROM tries
+			WHERE user_id = ?
+		), scores AS (
+			SELECT t.competition_id, COALESCE(t.team_id, t.user_id) AS participant,
+				COALESCE(SUM(t.score) FILTER (WHERE t.end_time IS NOT NULL), 0) AS score,
+				MAX(t.end_time) AS last_solve
+			FROM tries t
+			WHERE t.competition_id IN (SELECT competition_id FROM played)
+			GROUP BY 1, 2
+		), ranked AS (
+			SELECT s.*,
+				RANK() OVER (PARTITION BY s.competition_id ORDER BY s.score DESC, s.last_solve ASC NULLS LAST) AS rank,
+				COUNT(*) OVER (PARTITION BY s.competition_id) AS participants
+			FROM scores s
+		)
+		SELECT r.competition_id, c.title, r.rank, r.participants, r.score,
+			(SELECT COUNT(*) FROM tries t
+				WHERE t.competition_id = r.competition_id AND t.user_id = ? AND t.end_time IS NOT NULL) AS solved
+		FROM ranked r
+		JOIN played p ON p.competition_id = r.competition_id AND p.participant = r.participant
+		JOIN competitions c ON c.id = r.competition_id AND c.deleted_at IS NULL
+		ORDER BY c.title
+	`, user.ID, user.ID).Scan(&stats.Competitions).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedToGetStats)
+		return
+	}
+
+	var days []time.Time
+	if err := database.DB.Raw(`
+		SELECT DISTINCT DATE(end_time) AS day
+		FROM tries
+		WHERE user_id = ? AND end_time IS NOT NULL
+		ORDER BY day
+	`, user.ID).Scan(&days).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedToGetStats)
+		return
+	}
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	stats.CurrentStreak, stats.LongestStreak = solveStreaks(days, today)
+
+	if err := database.DB.Raw(`
+		SELECT t.competition_id, c.title, t.puzzle_index, t.puzzle_lvl, t.step, t.start_time, t.end_time, t.score
+		FROM tries t
+		JOIN competitions c ON c.id = t.competition_id AND c.deleted_at IS NULL
+		WHERE t.user_id = ?
+		ORDER BY t.start_time DESC
+		LIMIT ?
+	`, user.ID, recentActivityLimit).Scan(&stats.RecentActivity).Error; err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedToGetStats)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
@@ -16,13 +16,18 @@ func RegisterRoutes(r *gin.RouterGroup) {
         user.GET("/profile", GetUserProfile)
         user.PUT("/profile", UpdateUserProfile)
         user.PUT("/profile/password", UpdateUserPassword)
+        user.GET("/profile/stats", GetUserProfileStats)
+        user.GET("/profile/export", ExportUserData)
         
         // User management routes
         user.GET("/", GetUsers)
+        user.GET("/search", SearchUsers)
+        user.POST("/bulk", BulkUpdateUsers)
         user.PUT("/:id", UpdateTargetUserProfile)
         user.DELETE("/:id", DeleteUser)
         user.PUT("/block/:id", ToggleBlockUser)
         user.PUT("/resetpass/:id", ResetUserPassword)
+        user.POST("/:id/anonymize", AnonymizeUser)
         
         // User-role relationship routes
         user.GET("/roles", GetUsersFromRoles)
@@ -32,5 +37,6 @@ func RegisterRoutes(r *gin.RouterGroup) {
         // User-group relationship routes
         user.POST("/groups", CreateUserAndAttachGroup)
         user.POST("/group/:group_id/bulk", CreateBulkUsersAndAttachGroup)
+        user.POST("/group/:group_id/import", ImportUsersToGroup)
     }
 }
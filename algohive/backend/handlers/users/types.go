@@ -1,6 +1,9 @@
 package users
 
 import (
+	"api/models"
+	"time"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -22,6 +25,35 @@ const (
 	ErrNoPermissionUsersRoles = "User does not have permission to get users from roles"
 	ErrFailedAssociationRoles = "Failed to remove user role associations"
 	ErrFailedAssociationGroups = "Failed to remove user group associations"
+	ErrFailedToGetStats       = "Failed to compute profile statistics"
+	ErrInvalidListParams      = "Invalid pagination, sort or filter parameters"
+	ErrSearchQueryRequired    = "A search query is required"
+	ErrNoPermissionImport     = "User does not have permission to import users into this group"
+	ErrImportFileRequired     = "A CSV or XLSX file is required"
+	ErrImportUnreadable       = "Failed to read the imported file"
+	ErrImportInvalidMapping   = "Invalid column mapping"
+	ErrImportMissingColumn    = "Missing column in the imported file: "
+	ErrImportTooManyRows      = "Too many rows in the imported file"
+	ErrImportHasErrors        = "The imported file has invalid rows, nothing was imported"
+	ErrFailedImport           = "Failed to import users"
+	ErrBulkInvalidAction      = "Unknown bulk action"
+	ErrBulkNoSelection        = "Select users by user_ids or filter_group_id"
+	ErrBulkTooManyUsers       = "Too many users selected"
+	ErrBulkGroupRequired      = "group_id is required for this action"
+	ErrBulkRoleRequired       = "role_id is required for this action"
+	ErrNoPermissionManageGroup = "User does not have permission to manage this group"
+	ErrFailedBulkOperation    = "Failed to apply the bulk operation, no user was modified"
+	ErrInvalidExportFormat    = "Unknown export format, expected json or zip"
+	ErrFailedExport           = "Failed to export the user data"
+	ErrNoPermissionAnonymize  = "User does not have permission to anonymize this user"
+	ErrCannotAnonymizeSelf    = "You cannot anonymize your own account"
+	ErrFailedAnonymize        = "Failed to anonymize the user"
+	ErrCannotGrant            = "You cannot grant permissions you do not hold"
+	ErrCannotRevoke           = "You cannot revoke permissions you do not hold"
+	ErrCannotManageStaff      = "You cannot act on a user holding permissions you do not hold"
+	ErrLastOwner              = "The last owner cannot be deleted, blocked, anonymized or lose the OWNER permission"
+	ErrFailedPermissionCheck  = "Failed to check the granted permissions"
+	ErrNoPermissionExpiry     = "User does not have permission to change the expiry date of this user"
 )
 
 // UserWithRoles represents a user with associated roles for API requests
@@ -47,12 +79,138 @@ type UserIdWithRoles struct {
 }
 
 
+// TargetUserUpdate represents the profile of a user edited by staff, the fields left out keep their value.
+// A null expires_at removes the expiry date of the account
+type TargetUserUpdate struct {
+	Firstname string     `json:"firstname"`
+	Lastname  string     `json:"lastname"`
+	Email     string     `json:"email"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
 // PasswordUpdate represents a password update request
 type PasswordUpdate struct {
 	OldPassword string `json:"old_password"`
 	NewPassword string `json:"new_password"`
 }
 
+// CompetitionRank represents the standing of a user in one competition
+type CompetitionRank struct {
+	CompetitionID string  `json:"competition_id"`
+	Title         string  `json:"title"`
+	Rank          int     `json:"rank"`
+	Participants  int     `json:"participants"`
+	Score         float64 `json:"score"`
+	Solved        int     `json:"solved"`
+}
+
+// RecentTry represents one of the latest tries of a user
+type RecentTry struct {
+	CompetitionID string  `json:"competition_id"`
+	Title         string  `json:"title"`
+	PuzzleIndex   int     `json:"puzzle_index"`
+	PuzzleLvl     string  `json:"puzzle_lvl"`
+	Step          int     `json:"step"`
+	StartTime     string  `json:"start_time"`
+	EndTime       *string `json:"end_time"`
+	Score         float64 `json:"score"`
+}
+
+// ProfileStatsResponse represents the performance of a user across every competition.
+// Solve times are in seconds, streaks count consecutive days with at least one solve
+type ProfileStatsResponse struct {
+	TotalSolved        int               `json:"total_solved"`
+	SolvedByDifficulty map[string]int    `json:"solved_by_difficulty"`
+	AverageSolveTime   float64           `json:"average_solve_time"`
+	Competitions       []CompetitionRank `json:"competitions"`
+	CurrentStreak      int               `json:"current_streak"`
+	LongestStreak      int               `json:"longest_streak"`
+	RecentActivity     []RecentTry       `json:"recent_activity"`
+}
+
+// ImportColumnMapping maps each user field to the header of its column in an imported file
+type ImportColumnMapping struct {
+	Firstname string `json:"firstname"`
+	Lastname  string `json:"lastname"`
+	Email     string `json:"email"`
+}
+
+// BulkImportUser represents one user of the deprecated bulk creation, imported as a row of a file
+type BulkImportUser struct {
+	Firstname string `json:"firstname"`
+	Lastname  string `json:"lastname"`
+	Email     string `json:"email"`
+}
+
+// ImportRowResult represents the outcome of one row of an imported file.
+// Row is the line number in the file, the header being line 1, or the position in the list of a bulk creation from 1
+type ImportRowResult struct {
+	Row       int    `json:"row"`
+	Firstname string `json:"firstname"`
+	Lastname  string `json:"lastname"`
+	Email     string `json:"email"`
+	Status    string `json:"status"`
+	UserID    string `json:"user_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ImportReport represents the result of a user import, nothing is committed on a dry run or when a row is invalid
+type ImportReport struct {
+	DryRun         bool              `json:"dry_run"`
+	Committed      bool              `json:"committed"`
+	Created        int               `json:"created"`
+	Attached       int               `json:"attached"`
+	AlreadyMembers int               `json:"already_members"`
+	Invalid        int               `json:"invalid"`
+	Rows           []ImportRowResult `json:"rows"`
+}
+
+// BulkUserRequest selects users by ID or by group membership and applies one action to all of them.
+// Actions: block, unblock, delete, add_to_group, remove_from_group, move_to_group (from from_group_id to group_id),
+// add_role, remove_role and set_expiry (to expires_at, the expiry date is removed when it is null or left out)
+type BulkUserRequest struct {
+	Action        string     `json:"action" binding:"required"`
+	UserIDs       []string   `json:"user_ids"`
+	FilterGroupID string     `json:"filter_group_id"`
+	GroupID       string     `json:"group_id"`
+	FromGroupID   string     `json:"from_group_id"`
+	RoleID        string     `json:"role_id"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+}
+
+// BulkUserResult represents the outcome of a bulk action for one user
+type BulkUserResult struct {
+	UserID string `json:"user_id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BulkUserReport represents the outcome of a bulk action, applied in a single transaction
+type BulkUserReport struct {
+	Action  string           `json:"action"`
+	Done    int              `json:"done"`
+	Skipped int              `json:"skipped"`
+	Results []BulkUserResult `json:"results"`
+}
+
+// UserDataExport is every personal data kept about a user, as given to the user on request
+type UserDataExport struct {
+	ExportedAt       time.Time                `json:"exported_at"`
+	Profile          models.User              `json:"profile"`
+	Groups           []models.Group           `json:"groups"`
+	Roles            []models.Role            `json:"roles"`
+	Tries            []models.Try             `json:"tries"`
+	Submissions      []models.Submission      `json:"submissions"`
+	PracticeSessions []models.PracticeSession `json:"practice_sessions"`
+}
+
+// AnonymizeResponse reports an anonymization, the tries and submissions are kept for the statistics
+type AnonymizeResponse struct {
+	UserID      string `json:"user_id"`
+	Tries       int64  `json:"tries"`
+	Submissions int64  `json:"submissions"`
+}
+
 // respondWithError sends a JSON response with an error message
 func respondWithError(c *gin.Context, status int, message string) {
     c.JSON(status, gin.H{"error": message})
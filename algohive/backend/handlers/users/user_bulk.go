@@ -0,0 +1,339 @@
+package users
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/utils/audit"
+	"api/utils/escalation"
+	"api/utils/lifecycle"
+	"api/utils/permissions"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxBulkUsers is the largest number of users a bulk action can select
+const maxBulkUsers = 5000
+
+// Bulk actions
+const (
+	bulkBlock           = "block"
+	bulkUnblock         = "unblock"
+	bulkDelete          = "delete"
+	bulkAddToGroup      = "add_to_group"
+	bulkRemoveFromGroup = "remove_from_group"
+	bulkMoveToGroup     = "move_to_group"
+	bulkAddRole         = "add_role"
+	bulkRemoveRole      = "remove_role"
+	bulkSetExpiry       = "set_expiry"
+)
+
+// Outcomes of a bulk action for one user
+const (
+	bulkStatusDone      = "done"
+	bulkStatusNotFound  = "not_found"
+	bulkStatusForbidden = "forbidden"
+	bulkStatusSkipped   = "skipped"
+)
+
+// bulkActionPermissions is the permission each bulk action requires on every target user
+var bulkActionPermissions = map[string]int{
+	bulkBlock:           permissions.OWNER,
+	bulkUnblock:         permissions.OWNER,
+	bulkDelete:          permissions.OWNER,
+	bulkAddToGroup:      permissions.GROUPS,
+	bulkRemoveFromGroup: permissions.GROUPS,
+	bulkMoveToGroup:     permissions.GROUPS,
+	bulkAddRole:         permissions.ROLES,
+	bulkRemoveRole:      permissions.ROLES,
+	bulkSetExpiry:       permissions.OWNER,
+}
+
+// bulkStaffActions are the bulk actions that cannot touch staff members holding permissions the user does not hold
+var bulkStaffActions = map[string]bool{
+	bulkBlock:     true,
+	bulkUnblock:   true,
+	bulkDelete:    true,
+	bulkSetExpiry: true,
+}
+
+// bulkOwnerRemovals are the bulk actions that can remove an owner
+var bulkOwnerRemovals = map[string]bool{
+	bulkBlock:      true,
+	bulkDelete:     true,
+	bulkRemoveRole: true,
+}
+
+// selectBulkUsers resolves the users selected by a bulk request
+// returns: the selected users, with the requested IDs that do not exist
+func selectBulkUsers(req BulkUserRequest) ([]models.User, []string, error) {
+	var users []models.User
+	query := database.DB.Select("id", "firstname", "lastname", "email")
+	if req.FilterGroupID != "" {
+		query = query.Where("id IN (?)", database.DB.Table("user_groups").Select("user_id").Where("group_id = ?", req.FilterGroupID))
+	}
+	if len(req.UserIDs) > 0 {
+		query = query.Where("id IN ?", req.UserIDs)
+	}
+	if err := query.Find(&users).Error; err != nil {
+		return nil, nil, err
+	}
+
+	found := make(map[string]bool, len(users))
+	for _, u := range users {
+		found[u.ID] = true
+	}
+
+	var missing []string
+	seen := make(map[string]bool)
+	for _, id := range req.UserIDs {
+		if !found[id] && !seen[id] {
+			missing = append(missing, id)
+		}
+		seen[id] = true
+	}
+
+	return users, missing, nil
+}
+
+// applyBulkAction applies a bulk action to the permitted users in a transaction
+// targets: the users the action applies to
+// userID: ID of the authenticated user, recorded as the deleter of trashed users
+func applyBulkAction(tx *gorm.DB, req BulkUserRequest, targets []models.User, userID string) error {
+	ids := make([]string, len(targets))
+	members := make([]*models.User, len(targets))
+	for i := range targets {
+		ids[i] = targets[i].ID
+		members[i] = &targets[i]
+	}
+
+	switch req.Action {
+	case bulkBlock:
+		return tx.Model(&models.User{}).Where("id IN ?", ids).Update("blocked", true).Error
+	case bulkUnblock:
+		// An account unblocked by hand is no longer flagged as blocked by its expiry
+		return tx.Model(&models.User{}).Where("id IN ?", ids).
+			Updates(map[string]interface{}{"blocked": false, "expired_at": nil}).Error
+	case bulkDelete:
+		for i := range targets {
+			if _, err := deleteUser(tx, &targets[i], userID); err != nil {
+				return err
+			}
+		}
+		return nil
+	case bulkAddToGroup:
+		return tx.Model(&models.Group{ID: req.GroupID}).Omit("Users.*").Association("Users").Append(members)
+	case bulkRemoveFromGroup:
+		return tx.Exec("DELETE FROM user_groups WHERE group_id = ? AND user_id IN ?", req.GroupID, ids).Error
+	case bulkMoveToGroup:
+		if err := tx.Exec("DELETE FROM user_groups WHERE group_id = ? AND user_id IN ?", req.FromGroupID, ids).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Group{ID: req.GroupID}).Omit("Users.*").Association("Users").Append(members)
+	case bulkAddRole:
+		return tx.Model(&models.Role{ID: req.RoleID}).Omit("Users.*").Association("Users").Append(members)
+	case bulkRemoveRole:
+		return tx.Exec("DELETE FROM user_roles WHERE role_id = ? AND user_id IN ?", req.RoleID, ids).Error
+	case bulkSetExpiry:
+		if err := tx.Model(&models.User{}).Where("id IN ?", ids).Update("expires_at", req.ExpiresAt).Error; err != nil {
+			return err
+		}
+		// Accounts blocked by the expiry job are unblocked once no longer expired
+		_, err := lifecycle.ReactivateUsers(tx, ids)
+		return err
+	}
+	return nil
+}
+
+// staffAbove finds the selected staff members holding permissions the user does not hold
+// user: the authenticated user
+// selected: the users selected by the bulk request
+// returns: the IDs of these staff members
+func staffAbove(user models.User, selected []models.User) (map[string]bool, error) {
+	above := make(map[string]bool)
+	if permissions.IsOwner(user) || len(selected) == 0 {
+		return above, nil
+	}
+
+	ids := make([]string, len(selected))
+	for i := range selected {
+		ids[i] = selected[i].ID
+	}
+	var staffIDs []string
+	if err := database.DB.Table("user_roles").Distinct("user_id").Where("user_id IN ?", ids).Pluck("user_id", &staffIDs).Error; err != nil {
+		return nil, err
+	}
+
+	for _, id := range staffIDs {
+		allowed, err := escalation.CanManageUser(user, id)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			above[id] = true
+		}
+	}
+	return above, nil
+}
+
+// checkBulkTargets checks that the group or role of a bulk action exists and that the user can manage it
+// returns: the HTTP status and error message, or 0 when the action can proceed
+func checkBulkTargets(user models.User, req BulkUserRequest) (int, string) {
+	switch req.Action {
+	case bulkAddToGroup, bulkRemoveFromGroup, bulkMoveToGroup:
+		groupIDs := []string{req.GroupID}
+		if req.Action == bulkMoveToGroup {
+			groupIDs = append(groupIDs, req.FromGroupID)
+		}
+		for _, groupID := range groupIDs {
+			if groupID == "" {
+				return http.StatusBadRequest, ErrBulkGroupRequired
+			}
+			if err := database.DB.Select("id").First(&models.Group{}, "id = ?", groupID).Error; err != nil {
+				return http.StatusNotFound, ErrGroupNotFound
+			}
+			if !userCanManageGroup(user, groupID) {
+				return http.StatusUnauthorized, ErrNoPermissionManageGroup
+			}
+		}
+	case bulkAddRole, bulkRemoveRole:
+		if req.RoleID == "" {
+			return http.StatusBadRequest, ErrBulkRoleRequired
+		}
+		if err := database.DB.Select("id").First(&models.Role{}, "id = ?", req.RoleID).Error; err != nil {
+			return http.StatusNotFound, ErrRoleNotFound
+		}
+		if !userCanManageRoles(user, []string{req.RoleID}) {
+			return http.StatusUnauthorized, ErrNoPermissionRoles
+		}
+	}
+	return 0, ""
+}
+
+// BulkUpdateUsers applies one action to a selection of users
+// @Summary Apply an action to a selection of users
+// @Description Block, unblock, delete, add to a group, remove from a group, move between groups, add or remove a role or set the expiry date of the account for the users selected by ID or by group.
+// @Description Each user is checked individually, the action is applied to the permitted ones in a single transaction and the outcome is reported per user.
+// @Description Roles can only be added or removed by users holding the ROLES permission in their scopes and their permissions, staff members only blocked, deleted or given an expiry date by users holding the permissions of their roles,
+// @Description and nothing is applied when the action would leave no owner.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body BulkUserRequest true "Action and selection"
+// @Success 200 {object} BulkUserReport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /user/bulk [post]
+// @Security Bearer
+func BulkUpdateUsers(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	var req BulkUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	requiredPermission, ok := bulkActionPermissions[req.Action]
+	if !ok {
+		respondWithError(c, http.StatusBadRequest, ErrBulkInvalidAction)
+		return
+	}
+
+	if len(req.UserIDs) == 0 && req.FilterGroupID == "" {
+		respondWithError(c, http.StatusBadRequest, ErrBulkNoSelection)
+		return
+	}
+	if len(req.UserIDs) > maxBulkUsers {
+		respondWithError(c, http.StatusBadRequest, ErrBulkTooManyUsers)
+		return
+	}
+
+	if status, message := checkBulkTargets(user, req); status != 0 {
+		respondWithError(c, status, message)
+		return
+	}
+
+	// The user cannot grant nor revoke more than they hold
+	if req.Action == bulkAddRole && !checkRolesGrant(c, user, []string{req.RoleID}, "", ErrCannotGrant) {
+		return
+	}
+	if req.Action == bulkRemoveRole && !checkRolesGrant(c, user, []string{req.RoleID}, "", ErrCannotRevoke) {
+		return
+	}
+
+	selected, missing, err := selectBulkUsers(req)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedToGetUsers)
+		return
+	}
+	if len(selected) > maxBulkUsers {
+		respondWithError(c, http.StatusBadRequest, ErrBulkTooManyUsers)
+		return
+	}
+
+	above := map[string]bool{}
+	if bulkStaffActions[req.Action] {
+		if above, err = staffAbove(user, selected); err != nil {
+			respondWithError(c, http.StatusInternalServerError, ErrFailedPermissionCheck)
+			return
+		}
+	}
+
+	report := BulkUserReport{Action: req.Action, Results: []BulkUserResult{}}
+	for _, id := range missing {
+		report.Results = append(report.Results, BulkUserResult{UserID: id, Status: bulkStatusNotFound, Error: ErrUserNotFound})
+	}
+
+	var targets []models.User
+	for _, target := range selected {
+		switch {
+		case target.ID == user.ID && (req.Action == bulkBlock || req.Action == bulkDelete):
+			report.Results = append(report.Results, BulkUserResult{UserID: target.ID, Status: bulkStatusSkipped, Error: "cannot " + req.Action + " yourself"})
+		case !HasPermissionForUser(user, target.ID, requiredPermission):
+			report.Results = append(report.Results, BulkUserResult{UserID: target.ID, Status: bulkStatusForbidden, Error: ErrUnauthorized})
+		case above[target.ID]:
+			escalation.Deny(c, audit.ActionGrantDenied, user.ID, target.ID)
+			report.Results = append(report.Results, BulkUserResult{UserID: target.ID, Status: bulkStatusForbidden, Error: ErrCannotManageStaff})
+		default:
+			targets = append(targets, target)
+		}
+	}
+	report.Skipped = len(report.Results)
+
+	if len(targets) > 0 {
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := applyBulkAction(tx, req, targets, user.ID); err != nil {
+				return err
+			}
+			// The last owner cannot be blocked, deleted or lose their OWNER role
+			if bulkOwnerRemovals[req.Action] {
+				return escalation.EnsureOwnerRemains(tx)
+			}
+			return nil
+		})
+		if errors.Is(err, escalation.ErrLastOwner) {
+			respondLastOwner(c, user, "")
+			return
+		}
+		if err != nil {
+			respondWithError(c, http.StatusInternalServerError, ErrFailedBulkOperation)
+			return
+		}
+	}
+
+	for _, target := range targets {
+		report.Results = append(report.Results, BulkUserResult{UserID: target.ID, Status: bulkStatusDone})
+	}
+	report.Done = len(targets)
+
+	c.JSON(http.StatusOK, report)
+}
@@ -4,7 +4,6 @@ import (
 	"api/database"
 	"api/middleware"
 	"api/models"
-	"api/utils"
 	"api/utils/permissions"
 	"net/http"
 
@@ -52,6 +51,13 @@ func CreateUserAndAttachGroup(c *gin.Context) {
 		return
 	}
 
+	for i := range groups {
+		if !userCanManageGroup(user, groups[i].ID) {
+			respondWithError(c, http.StatusUnauthorized, ErrNoPermissionManageGroup)
+			return
+		}
+	}
+
 	// Create the user
 	targetUser, err := createUser(userWithGroups.FirstName, userWithGroups.LastName, userWithGroups.Email)
 	if err != nil {
@@ -72,16 +78,22 @@ func CreateUserAndAttachGroup(c *gin.Context) {
 
 // CreateBulkUsersAndAttachGroup creates multiple users and attaches a group to them
 // @Summary Create Bulk Users and attach a Group
-// @Description Create multiple new users and attach a group to them
+// @Description Create multiple new users and attach a group to them. Deprecated in favor of the CSV/XLSX import of /user/group/{group_id}/import,
+// @Description the users go through the same checks: existing accounts are attached, new ones get the default password and no role,
+// @Description and nothing is created if any user is invalid or duplicated
 // @Tags Users
 // @Accept json
 // @Produce json
-// @Param users body []models.User true "Users Profiles"
+// @Param users body []BulkImportUser true "Users Profiles"
 // @Param group_id path string true "Group ID"
-// @Success 201 {object} models.User
+// @Success 201 {object} ImportReport
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} ImportReport "Report of a list with invalid users"
 // @Router /user/group/{group_id}/bulk [post]
 // @Security Bearer
+// @Deprecated
 func CreateBulkUsersAndAttachGroup(c *gin.Context) {
 	user, err := middleware.GetUserFromRequest(c)
 	if err != nil {
@@ -91,7 +103,7 @@ func CreateBulkUsersAndAttachGroup(c *gin.Context) {
 	groupID := c.Param("group_id")
 
 	// Check permissions
-	if !UserOwnsTargetGroups(user.ID, groupID) {
+	if !userCanManageGroup(user, groupID) {
 		respondWithError(c, http.StatusUnauthorized, "User does not have permission to create users")
 		return
 	}
@@ -104,26 +116,36 @@ func CreateBulkUsersAndAttachGroup(c *gin.Context) {
 	}
 	
 	// Retrieve users to be created
-	var users []models.User
+	var users []BulkImportUser
 	if err := c.ShouldBindJSON(&users); err != nil {
 		respondWithError(c, http.StatusBadRequest, err.Error())
 		return
 	}
-	
-	// Create the users and associate the group
-	for i := range users {
-		users[i].Groups = append(users[i].Groups, &group)
-		hashedPassword, err := utils.HashPassword(users[i].Password)
-		if err != nil {
-			respondWithError(c, http.StatusInternalServerError, ErrFailedToHashPassword)
-			return
-		}
-		users[i].Password = hashedPassword
-		if err := database.DB.Create(&users[i]).Error; err != nil {
-			respondWithError(c, http.StatusInternalServerError, "Failed to create users")
-			return
-		}
+	if len(users) > maxImportRows {
+		respondWithError(c, http.StatusBadRequest, ErrImportTooManyRows)
+		return
 	}
-	
-	c.JSON(http.StatusCreated, users)
+
+	// The users are imported as the rows of a file, so they get the same checks and are created all or nothing
+	rows := make([][]string, 0, len(users))
+	for _, u := range users {
+		rows = append(rows, []string{u.Firstname, u.Lastname, u.Email})
+	}
+
+	report, err := buildImportReport(rows, [3]int{0, 1, 2}, group.ID, 1)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedImport)
+		return
+	}
+	if report.Invalid > 0 {
+		c.JSON(http.StatusUnprocessableEntity, report)
+		return
+	}
+
+	if err := commitImport(report, &group); err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedImport)
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
 }
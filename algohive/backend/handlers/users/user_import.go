@@ -0,0 +1,341 @@
+package users
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/utils"
+	"api/utils/spreadsheet"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportRows is the largest number of users a single file can import
+const maxImportRows = 5000
+
+// Outcomes of an imported row
+const (
+	importStatusCreate    = "create"
+	importStatusAttach    = "attach"
+	importStatusMember    = "already_member"
+	importStatusInvalid   = "invalid"
+	importStatusDuplicate = "duplicate"
+)
+
+// defaultImportMapping is used when the request does not map the columns
+var defaultImportMapping = ImportColumnMapping{
+	Firstname: "firstname",
+	Lastname:  "lastname",
+	Email:     "email",
+}
+
+// importColumns locates the mapped columns in the header row of an imported file
+// returns: the index of the firstname, lastname and email columns, and the header of a missing column
+func importColumns(header []string, mapping ImportColumnMapping) ([3]int, string) {
+	var columns [3]int
+	for i, name := range []string{mapping.Firstname, mapping.Lastname, mapping.Email} {
+		columns[i] = -1
+		for j, cell := range header {
+			if strings.EqualFold(strings.TrimSpace(cell), strings.TrimSpace(name)) {
+				columns[i] = j
+				break
+			}
+		}
+		if columns[i] < 0 {
+			return columns, name
+		}
+	}
+	return columns, ""
+}
+
+// cellAt returns the trimmed cell of a row, rows of spreadsheets can be shorter than the header
+func cellAt(row []string, index int) string {
+	if index >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[index])
+}
+
+// validateImportRow returns why an imported row cannot be imported, or an empty string
+func validateImportRow(result ImportRowResult) string {
+	switch {
+	case result.Firstname == "" || result.Lastname == "" || result.Email == "":
+		return "firstname, lastname and email are required"
+	case len(result.Firstname) > 50 || len(result.Lastname) > 50:
+		return "firstname and lastname are limited to 50 characters"
+	case len(result.Email) > 255:
+		return "email is limited to 255 characters"
+	}
+
+	address, err := mail.ParseAddress(result.Email)
+	if err != nil || address.Address != result.Email {
+		return "invalid email"
+	}
+	return ""
+}
+
+// buildImportReport checks every row of an imported file against the file itself and the existing users
+// rows: the data rows, without the header
+// columns: the index of the firstname, lastname and email columns
+// firstRow: the number reported for the first data row, 2 in a file under its header
+func buildImportReport(rows [][]string, columns [3]int, groupID string, firstRow int) (*ImportReport, error) {
+	report := &ImportReport{Rows: []ImportRowResult{}}
+	firstRowByEmail := make(map[string]int)
+	var emails []string
+
+	for i, row := range rows {
+		result := ImportRowResult{
+			Row:       i + firstRow,
+			Firstname: cellAt(row, columns[0]),
+			Lastname:  cellAt(row, columns[1]),
+			Email:     strings.ToLower(cellAt(row, columns[2])),
+		}
+
+		// Spreadsheets often end with blank lines
+		if result.Firstname == "" && result.Lastname == "" && result.Email == "" {
+			continue
+		}
+
+		if reason := validateImportRow(result); reason != "" {
+			result.Status = importStatusInvalid
+			result.Error = reason
+		} else if first, ok := firstRowByEmail[result.Email]; ok {
+			result.Status = importStatusDuplicate
+			result.Error = "same email as row " + strconv.Itoa(first)
+		} else {
+			firstRowByEmail[result.Email] = result.Row
+			emails = append(emails, result.Email)
+		}
+
+		report.Rows = append(report.Rows, result)
+	}
+
+	existing := make(map[string]string)
+	members := make(map[string]bool)
+	if len(emails) > 0 {
+		var users []models.User
+		if err := database.DB.Select("id", "email").Where("LOWER(email) IN ?", emails).Find(&users).Error; err != nil {
+			return nil, err
+		}
+
+		userIDs := make([]string, 0, len(users))
+		for _, u := range users {
+			existing[strings.ToLower(u.Email)] = u.ID
+			userIDs = append(userIDs, u.ID)
+		}
+
+		if len(userIDs) > 0 {
+			var memberIDs []string
+			if err := database.DB.Table("user_groups").Where("group_id = ? AND user_id IN ?", groupID, userIDs).
+				Pluck("user_id", &memberIDs).Error; err != nil {
+				return nil, err
+			}
+			for _, id := range memberIDs {
+				members[id] = true
+			}
+		}
+	}
+
+	for i := range report.Rows {
+		result := &report.Rows[i]
+		switch {
+		case result.Status != "":
+			report.Invalid++
+		case existing[result.Email] == "":
+			result.Status = importStatusCreate
+			report.Created++
+		case members[existing[result.Email]]:
+			result.Status = importStatusMember
+			result.UserID = existing[result.Email]
+			report.AlreadyMembers++
+		default:
+			result.Status = importStatusAttach
+			result.UserID = existing[result.Email]
+			report.Attached++
+		}
+	}
+
+	return report, nil
+}
+
+// commitImport creates the new users of a report and attaches them, with the existing ones, to the group
+// in a single transaction, filling the IDs of the created users in the report
+func commitImport(report *ImportReport, group *models.Group) error {
+	// Every imported account starts with the default password, hashing it once keeps large imports fast
+	hashedPassword, err := utils.CreateDefaultPassword()
+	if err != nil {
+		return err
+	}
+
+	var created []*models.User
+	var attached []*models.User
+	for _, result := range report.Rows {
+		switch result.Status {
+		case importStatusCreate:
+			created = append(created, &models.User{
+				Firstname: result.Firstname,
+				Lastname:  result.Lastname,
+				Email:     result.Email,
+				Password:  hashedPassword,
+			})
+		case importStatusAttach:
+			attached = append(attached, &models.User{ID: result.UserID})
+		}
+	}
+
+	tx := database.DB.Begin()
+
+	if len(created) > 0 {
+		if err := tx.Omit("Groups", "Roles").CreateInBatches(created, 500).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if members := append(created, attached...); len(members) > 0 {
+		if err := tx.Model(group).Omit("Users.*").Association("Users").Append(members); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	createdIDs := make(map[string]string, len(created))
+	for _, u := range created {
+		createdIDs[u.Email] = u.ID
+	}
+	for i := range report.Rows {
+		if report.Rows[i].Status == importStatusCreate {
+			report.Rows[i].UserID = createdIDs[report.Rows[i].Email]
+		}
+	}
+	report.Committed = true
+
+	return nil
+}
+
+// ImportUsersToGroup imports users from a CSV or XLSX file into a group
+// @Summary Import users into a group from a CSV or XLSX file
+// @Description Import the users of a CSV or XLSX file (first sheet) into a group. The first row holds the headers, mapped to the user fields by the optional mapping (default: firstname, lastname, email).
+// @Description Existing accounts are attached to the group, new ones are created with the default password. The import is all or nothing: if any row is invalid or duplicated nothing is imported.
+// @Description A dry run only returns the report.
+// @Tags Users
+// @Accept multipart/form-data
+// @Produce json
+// @Param group_id path string true "Group ID"
+// @Param file formData file true "CSV or XLSX file"
+// @Param mapping formData string false "Column mapping as JSON, e.g. {\"firstname\":\"Prénom\",\"lastname\":\"Nom\",\"email\":\"Mail\"}"
+// @Param dry_run formData bool false "Only report what the import would do"
+// @Success 200 {object} ImportReport "Dry run report"
+// @Success 201 {object} ImportReport "Committed import report"
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} ImportReport "Report of a file with invalid rows"
+// @Router /user/group/{group_id}/import [post]
+// @Security Bearer
+func ImportUsersToGroup(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	var group models.Group
+	if err := database.DB.Where("id = ?", c.Param("group_id")).First(&group).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrGroupNotFound)
+		return
+	}
+
+	if !userCanManageGroup(user, group.ID) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionImport)
+		return
+	}
+
+	mapping := defaultImportMapping
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil ||
+			mapping.Firstname == "" || mapping.Lastname == "" || mapping.Email == "" {
+			respondWithError(c, http.StatusBadRequest, ErrImportInvalidMapping)
+			return
+		}
+	}
+
+	dryRun := false
+	if raw := c.PostForm("dry_run"); raw != "" {
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			respondWithError(c, http.StatusBadRequest, ErrInvalidListParams)
+			return
+		}
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrImportFileRequired)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrImportUnreadable)
+		return
+	}
+	defer file.Close()
+
+	rows, err := spreadsheet.ReadRows(file, fileHeader.Filename)
+	if err != nil {
+		if errors.Is(err, spreadsheet.ErrUnsupportedFormat) {
+			respondWithError(c, http.StatusBadRequest, ErrImportFileRequired)
+			return
+		}
+		respondWithError(c, http.StatusBadRequest, ErrImportUnreadable)
+		return
+	}
+
+	if len(rows) == 0 {
+		respondWithError(c, http.StatusBadRequest, ErrImportMissingColumn+mapping.Firstname)
+		return
+	}
+	if len(rows)-1 > maxImportRows {
+		respondWithError(c, http.StatusBadRequest, ErrImportTooManyRows)
+		return
+	}
+
+	columns, missing := importColumns(rows[0], mapping)
+	if missing != "" {
+		respondWithError(c, http.StatusBadRequest, ErrImportMissingColumn+missing)
+		return
+	}
+
+	report, err := buildImportReport(rows[1:], columns, group.ID, 2)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedImport)
+		return
+	}
+	report.DryRun = dryRun
+
+	if dryRun {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	if report.Invalid > 0 {
+		c.JSON(http.StatusUnprocessableEntity, report)
+		return
+	}
+
+	if err := commitImport(report, &group); err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedImport)
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
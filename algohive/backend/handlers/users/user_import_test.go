@@ -0,0 +1,62 @@
+package users
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImportColumns(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      []string
+		mapping     ImportColumnMapping
+		want        [3]int
+		wantMissing string
+	}{
+		{"default mapping", []string{"firstname", "lastname", "email"}, defaultImportMapping, [3]int{0, 1, 2}, ""},
+		{"any order, case and spacing", []string{" Email ", "LASTNAME", "Firstname"}, defaultImportMapping, [3]int{2, 1, 0}, ""},
+		{"extra columns", []string{"id", "email", "group", "firstname", "lastname"}, defaultImportMapping, [3]int{3, 4, 1}, ""},
+		{
+			"custom mapping",
+			[]string{"Prénom", "Nom", "Mail"},
+			ImportColumnMapping{Firstname: "Prénom", Lastname: "Nom", Email: "Mail"},
+			[3]int{0, 1, 2}, "",
+		},
+		{"missing column", []string{"firstname", "email"}, defaultImportMapping, [3]int{}, "lastname"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, missing := importColumns(tt.header, tt.mapping)
+			if missing != tt.wantMissing {
+				t.Fatalf("importColumns() missing = %q, want %q", missing, tt.wantMissing)
+			}
+			if missing == "" && got != tt.want {
+				t.Errorf("importColumns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateImportRow(t *testing.T) {
+	tests := []struct {
+		name  string
+		row   ImportRowResult
+		valid bool
+	}{
+		{"valid", ImportRowResult{Firstname: "Ada", Lastname: "Lovelace", Email: "ada@example.com"}, true},
+		{"missing firstname", ImportRowResult{Lastname: "Lovelace", Email: "ada@example.com"}, false},
+		{"missing email", ImportRowResult{Firstname: "Ada", Lastname: "Lovelace"}, false},
+		{"name too long", ImportRowResult{Firstname: strings.Repeat("a", 51), Lastname: "Lovelace", Email: "ada@example.com"}, false},
+		{"invalid email", ImportRowResult{Firstname: "Ada", Lastname: "Lovelace", Email: "ada.example.com"}, false},
+		{"email with a display name", ImportRowResult{Firstname: "Ada", Lastname: "Lovelace", Email: "Ada <ada@example.com>"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if reason := validateImportRow(tt.row); (reason == "") != tt.valid {
+				t.Errorf("validateImportRow() = %q, want valid %v", reason, tt.valid)
+			}
+		})
+	}
+}
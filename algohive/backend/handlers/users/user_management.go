@@ -5,10 +5,16 @@ import (
 	"api/middleware"
 	"api/models"
 	"api/utils"
+	"api/utils/escalation"
+	"api/utils/hierarchy"
+	"api/utils/pagination"
 	"api/utils/permissions"
+	"api/utils/trash"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // createUser creates a new user with basic information
@@ -35,11 +41,33 @@ func createUser(firstName, lastName, email string) (*models.User, error) {
 	return &user, nil
 }
 
+// userSortKeys are the sort keys accepted by the user lists
+var userSortKeys = map[string]string{
+	"firstname":      "firstname",
+	"lastname":       "lastname",
+	"email":          "email",
+	"last_connected": "last_connected",
+}
+
+// preloadUserAssociations loads the roles and groups of a page of users
+func preloadUserAssociations(db *gorm.DB) *gorm.DB {
+	return db.Preload("Roles").Preload("Groups")
+}
+
 // GetUsers retrieves all users accessible to the authenticated user
 // @Summary Get All users that the curren user has access to 
-// @Description Get all users that the current user has access to from his roles -> scopes -> groups
+// @Description Get a page of the users that the current user has access to from his roles -> scopes -> groups
 // @Tags Users
-// @Success 200 {object} []models.User
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param sort query string false "Sort keys among firstname, lastname, email, last_connected, prefixed by - to sort descending"
+// @Param q query string false "Text searched in the first name, last name and email"
+// @Param blocked query bool false "Only blocked or unblocked users"
+// @Param group_id query string false "Only members of this group"
+// @Param recursive query bool false "With group_id, also the members of its subgroups"
+// @Param role_id query string false "Only holders of this role"
+// @Success 200 {object} pagination.Page{items=[]models.User}
+// @Failure 400 {object} map[string]string
 // @Router /user/ [get]
 // @Security Bearer
 func GetUsers(c *gin.Context) {
@@ -48,87 +76,101 @@ func GetUsers(c *gin.Context) {
 		return
 	}
 
+	params, err := pagination.Parse(c, userSortKeys, "lastname,firstname")
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidListParams)
+		return
+	}
+
+	query := pagination.Search(visibleUsers(user), c.Query("q"), "firstname", "lastname", "email")
+
+	blocked, err := pagination.Bool(c, "blocked")
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidListParams)
+		return
+	}
+	if blocked != nil {
+		query = query.Where("blocked = ?", *blocked)
+	}
+	query = filterUsersByMembership(c, query)
+
 	var users []models.User
-	
+	page, err := pagination.Find(query, params, &users, preloadUserAssociations)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedToGetUsers)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// visibleUsers builds the query of the users the authenticated user has access to
+// user: the authenticated user
+// returns: the query on the users model, to filter further
+func visibleUsers(user models.User) *gorm.DB {
+	query := database.DB.Model(&models.User{})
+
 	// Owners can see all users
 	if permissions.RolesHavePermission(user.Roles, permissions.OWNER) {
-		if err := database.DB.Preload("Roles").Preload("Groups").Find(&users).Error; err != nil {
-			respondWithError(c, http.StatusInternalServerError, ErrFailedToGetUsers)
-			return
-		}
-	} else {
-			// For users without roles, only those in the same groups
-		if len(user.Roles) == 0 {
-			if err := getUsersInSameGroups(user.ID, &users); err != nil {
-				respondWithError(c, http.StatusInternalServerError, ErrFailedToGetUsers)
-				return
-			}
+		return query
+	}
+
+	// For users without roles, only those in the same groups
+	if len(user.Roles) == 0 {
+		return query.Where("id IN (?)", usersInSameGroups(user.ID))
+	}
+
+	// For users with roles, use the role->scope->group hierarchy
+	return query.Where("id IN (?)", usersFromRoleScopes(user.ID))
+}
+
+// filterUsersByMembership keeps the users of the group_id and role_id query parameters, when set.
+// With recursive=true, the members of the subgroups of group_id are kept too
+func filterUsersByMembership(c *gin.Context, query *gorm.DB) *gorm.DB {
+	if groupID := c.Query("group_id"); groupID != "" {
+		if c.Query("recursive") == "true" {
+			query = query.Where("id IN (?)", hierarchy.Members([]string{groupID}))
 		} else {
-				// For users with roles, use the role->scope->group hierarchy
-			if err := getUsersFromRoleScopes(user.ID, &users); err != nil {
-				respondWithError(c, http.StatusInternalServerError, ErrFailedToGetUsers)
-				return
-			}
+			query = query.Where("id IN (?)", database.DB.Table("user_groups").Select("user_id").Where("group_id = ?", groupID))
 		}
 	}
-
-	c.JSON(http.StatusOK, users)
+	if roleID := c.Query("role_id"); roleID != "" {
+		query = query.Where("id IN (?)", database.DB.Table("user_roles").Select("user_id").Where("role_id = ?", roleID))
+	}
+	return query
 }
 
-// getUsersInSameGroups retrieves all users who are in the same groups as the user
+// usersInSameGroups selects the IDs of the users who are in the same groups as the user
 // userID: ID of the user
-// users: pointer to the slice of users to fill
-// returns: any error
-func getUsersInSameGroups(userID string, users *[]models.User) error {
+// returns: the subquery of user IDs
+func usersInSameGroups(userID string) *gorm.DB {
 	return database.DB.Raw(`
-		SELECT DISTINCT u.*
-			FROM users u
-			JOIN user_groups ug ON u.id = ug.user_id
-			JOIN groups g ON ug.group_id = g.id
-			JOIN user_groups aug ON g.id = aug.group_id
-			JOIN users au ON aug.user_id = au.id
-			WHERE au.id = ?
-	`, userID).Scan(users).Error
+		SELECT DISTINCT ug.user_id
+			FROM user_groups ug
+			JOIN user_groups aug ON ug.group_id = aug.group_id
+			WHERE aug.user_id = ?
+	`, userID)
 }
 
-// getUsersFromRoleScopes retrieves all users accessible via roles->scopes->groups
+// usersFromRoleScopes selects the IDs of the users accessible via roles->scopes->groups and their subgroups
 // userID: ID of the user
-// users: pointer to the slice of users to fill
-// returns: any error
-func getUsersFromRoleScopes(userID string, users *[]models.User) error {
-	var userIDs []string
-	if err := database.DB.Raw(`
-		SELECT DISTINCT u.id
-			FROM users u
-			JOIN user_groups ug ON u.id = ug.user_id
-			JOIN groups g ON ug.group_id = g.id
-			JOIN scopes s ON g.scope_id = s.id
-			JOIN role_scopes rs ON s.id = rs.scope_id
-			JOIN roles r ON rs.role_id = r.id
-			JOIN user_roles ur ON r.id = ur.role_id
-			WHERE ur.user_id = ?
-	`, userID).Pluck("id", &userIDs).Error; err != nil {
-		return err
-	}
-	
-	// If no users found, return empty slice
-	if len(userIDs) == 0 {
-		*users = []models.User{}
-		return nil
-	}
-	
-	// Retrieve users with their associations
-	return database.DB.Preload("Roles").Preload("Groups").Where("id IN ?", userIDs).Find(users).Error
+// returns: the subquery of user IDs
+func usersFromRoleScopes(userID string) *gorm.DB {
+	return database.DB.Table("user_groups").
+		Distinct("user_id").
+		Where("group_id IN (?)", hierarchy.ManagedGroups(userID))
 }
 
 // DeleteUser deletes a user by ID
 // @Summary Delete User
-// @Description Delete a user by ID, if user isStaff, required ownership permission
+// @Description Move a user to the trash by ID, if user isStaff, required ownership permission
 // @Tags Users
 // @Param id path string true "User ID"
 // @Success 204
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /user/{id} [delete]
 // @Security Bearer
 func DeleteUser(c *gin.Context) {
@@ -151,26 +193,26 @@ func DeleteUser(c *gin.Context) {
         respondWithError(c, http.StatusUnauthorized, ErrNoPermissionDelete)
         return
     }
+    if !checkCanManageUser(c, user, targetUser.ID) {
+        return
+    }
 
     // Start a transaction to ensure atomicity of operations
     tx := database.DB.Begin()
 
-    // Delete associations first
-    if err := tx.Model(&targetUser).Association("Roles").Clear(); err != nil {
-        tx.Rollback()
-        respondWithError(c, http.StatusInternalServerError, ErrFailedAssociationRoles)
-        return
-    }
-    
-    if err := tx.Model(&targetUser).Association("Groups").Clear(); err != nil {
+    if message, err := deleteUser(tx, &targetUser, user.ID); err != nil {
         tx.Rollback()
-        respondWithError(c, http.StatusInternalServerError, ErrFailedAssociationGroups)
+        respondWithError(c, http.StatusInternalServerError, message)
         return
     }
 
-    // Delete the user
-    if err := tx.Delete(&targetUser).Error; err != nil {
+    // The last owner cannot be deleted
+    if err := escalation.EnsureOwnerRemains(tx); err != nil {
         tx.Rollback()
+        if errors.Is(err, escalation.ErrLastOwner) {
+            respondLastOwner(c, user, targetUser.ID)
+            return
+        }
         respondWithError(c, http.StatusInternalServerError, ErrFailedToDeleteUser)
         return
     }
@@ -181,6 +223,21 @@ func DeleteUser(c *gin.Context) {
     c.Status(http.StatusNoContent)
 }
 
+// deleteUser moves a user to the trash, its roles, groups and team memberships are detached
+// until it is restored, its tries and competition time overrides are kept until it is purged
+// tx: the transaction to delete the user in
+// targetUser: the user to delete
+// deletedBy: ID of the user deleting the target user
+// returns: the error message of the failed step and the error
+func deleteUser(tx *gorm.DB, targetUser *models.User, deletedBy string) (string, error) {
+    label := targetUser.Firstname + " " + targetUser.Lastname + " <" + targetUser.Email + ">"
+    if err := trash.MoveToTrash(tx, models.TrashUser, targetUser.ID, label, deletedBy); err != nil {
+        return ErrFailedToDeleteUser, err
+    }
+
+    return "", nil
+}
+
 // ToggleBlockUser toggles the block status of a user
 // @Summary Toggle block user
 // @Description Toggle the block status of a user
@@ -188,7 +245,9 @@ func DeleteUser(c *gin.Context) {
 // @Param id path string true "User ID"
 // @Success 200 {object} models.User
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /user/block/{id} [put]
 // @Security Bearer
 func ToggleBlockUser(c *gin.Context) {
@@ -211,10 +270,27 @@ func ToggleBlockUser(c *gin.Context) {
 		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionBlock)
 		return
 	}
+	if !checkCanManageUser(c, user, targetUser.ID) {
+		return
+	}
 
-	// Toggle block status
+	// Toggle block status, an account unblocked by hand is no longer flagged as blocked by its expiry
 	targetUser.Blocked = !targetUser.Blocked
-	if err := database.DB.Save(&targetUser).Error; err != nil {
+	if !targetUser.Blocked {
+		targetUser.ExpiredAt = nil
+	}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&targetUser).Error; err != nil {
+			return err
+		}
+		// The last owner cannot be blocked
+		return escalation.EnsureOwnerRemains(tx)
+	})
+	if errors.Is(err, escalation.ErrLastOwner) {
+		respondLastOwner(c, user, targetUser.ID)
+		return
+	}
+	if err != nil {
 		respondWithError(c, http.StatusInternalServerError, "Failed to update user")
 		return
 	}
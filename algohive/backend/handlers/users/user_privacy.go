@@ -0,0 +1,240 @@
+package users
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/utils/audit"
+	"api/utils/escalation"
+	"api/utils/permissions"
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// anonymizedPassword is stored in place of the password hash of an anonymized user, no password matches it
+const anonymizedPassword = "!"
+
+// collectUserData gathers every personal data kept about a user
+// userID: ID of the user
+func collectUserData(userID string) (UserDataExport, error) {
+	export := UserDataExport{ExportedAt: time.Now()}
+
+	var user models.User
+	if err := database.DB.Where("id = ?", userID).Preload("Groups").Preload("Roles").First(&user).Error; err != nil {
+		return export, err
+	}
+
+	export.Groups = make([]models.Group, 0, len(user.Groups))
+	for _, group := range user.Groups {
+		export.Groups = append(export.Groups, models.Group{
+			ID: group.ID, Name: group.Name, Description: group.Description, ScopeID: group.ScopeID, ExpiresAt: group.ExpiresAt,
+		})
+	}
+	export.Roles = make([]models.Role, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		export.Roles = append(export.Roles, models.Role{ID: role.ID, Name: role.Name, Permissions: role.Permissions})
+	}
+
+	user.Password = ""
+	user.Groups = nil
+	user.Roles = nil
+	export.Profile = user
+
+	if err := database.DB.Where("user_id = ?", userID).Order("start_time").Find(&export.Tries).Error; err != nil {
+		return export, err
+	}
+	if err := database.DB.Where("user_id = ?", userID).Order("submitted_at").Find(&export.Submissions).Error; err != nil {
+		return export, err
+	}
+	if err := database.DB.Where("user_id = ?", userID).Order("start_time").Find(&export.PracticeSessions).Error; err != nil {
+		return export, err
+	}
+
+	return export, nil
+}
+
+// writeExportZip writes an export as a ZIP archive with one JSON file per section
+func writeExportZip(c *gin.Context, export UserDataExport) error {
+	sections := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"groups.json", export.Groups},
+		{"roles.json", export.Roles},
+		{"tries.json", export.Tries},
+		{"submissions.json", export.Submissions},
+		{"practice_sessions.json", export.PracticeSessions},
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", `attachment; filename="algohive-export-`+export.Profile.ID+`.zip"`)
+	c.Status(http.StatusOK)
+
+	archive := zip.NewWriter(c.Writer)
+	for _, section := range sections {
+		file, err := archive.CreateHeader(&zip.FileHeader{Name: section.name, Method: zip.Deflate, Modified: export.ExportedAt})
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(section.data); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// ExportUserData exports the personal data of the authenticated user
+// @Summary Export my data
+// @Description Export the profile, groups, roles, tries, submissions and practice sessions of the authenticated user,
+// @Description as a single JSON document or as a ZIP archive of one JSON file per section. The export is recorded in the audit trail
+// @Tags Users
+// @Produce json
+// @Produce application/zip
+// @Param format query string false "json (default) or zip"
+// @Success 200 {object} UserDataExport
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /user/profile/export [get]
+// @Security Bearer
+func ExportUserData(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "zip" {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidExportFormat)
+		return
+	}
+
+	export, err := collectUserData(user.ID)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedExport)
+		return
+	}
+
+	if err := audit.Record(database.DB, c, audit.ActionUserExported, user.ID, user.ID, gin.H{
+		"format":      format,
+		"tries":       len(export.Tries),
+		"submissions": len(export.Submissions),
+	}); err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedExport)
+		return
+	}
+
+	if format == "zip" {
+		if err := writeExportZip(c, export); err != nil {
+			c.Error(err)
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="algohive-export-`+user.ID+`.json"`)
+	c.JSON(http.StatusOK, export)
+}
+
+// AnonymizeUser scrubs the personal data of a user
+// @Summary Anonymize a user
+// @Description Replace the name and email of a user, disable the account and remove its roles. Its tries and submissions
+// @Description are kept for the statistics, without the IP and user agent of the submissions. Requires the OWNER permission on the user
+// @Description and is recorded in the audit trail
+// @Tags Users
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} AnonymizeResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /user/{id}/anonymize [post]
+// @Security Bearer
+func AnonymizeUser(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	var targetUser models.User
+	if err := database.DB.Where("id = ?", c.Param("id")).First(&targetUser).Error; err != nil {
+		respondWithError(c, http.StatusNotFound, ErrNotFound)
+		return
+	}
+
+	if targetUser.ID == user.ID {
+		respondWithError(c, http.StatusBadRequest, ErrCannotAnonymizeSelf)
+		return
+	}
+
+	if !HasPermissionForUser(user, targetUser.ID, permissions.OWNER) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionAnonymize)
+		return
+	}
+	if !checkCanManageUser(c, user, targetUser.ID) {
+		return
+	}
+
+	response := AnonymizeResponse{UserID: targetUser.ID}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// The account expires now so the tokens it still holds are rejected
+		now := time.Now()
+		if err := tx.Model(&targetUser).Updates(map[string]interface{}{
+			"firstname":      "Anonymous",
+			"lastname":       "User",
+			"email":          "anonymized-" + targetUser.ID + "@anonymized.invalid",
+			"password":       anonymizedPassword,
+			"last_connected": nil,
+			"blocked":        true,
+			"expires_at":     now,
+			"expired_at":     nil,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec("DELETE FROM user_roles WHERE user_id = ?", targetUser.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM email_verifications WHERE user_id = ?", targetUser.ID).Error; err != nil {
+			return err
+		}
+		// The last owner cannot be anonymized
+		if err := escalation.EnsureOwnerRemains(tx); err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.Submission{}).Where("user_id = ?", targetUser.ID).
+			Updates(map[string]interface{}{"ip": "", "user_agent": ""})
+		if result.Error != nil {
+			return result.Error
+		}
+		response.Submissions = result.RowsAffected
+
+		if err := tx.Model(&models.Try{}).Where("user_id = ?", targetUser.ID).Count(&response.Tries).Error; err != nil {
+			return err
+		}
+
+		return audit.Record(tx, c, audit.ActionUserAnonymized, user.ID, targetUser.ID, gin.H{
+			"tries":       response.Tries,
+			"submissions": response.Submissions,
+		})
+	})
+	if errors.Is(err, escalation.ErrLastOwner) {
+		respondLastOwner(c, user, targetUser.ID)
+		return
+	}
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedAnonymize)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -4,22 +4,28 @@ import (
 	"api/database"
 	"api/middleware"
 	"api/models"
+	"api/utils/escalation"
+	"api/utils/hierarchy"
 	"api/utils/permissions"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // CreateUserAndAttachRoles creates a user and attaches roles to it
 // @Summary Create a user and attach one or more roles
-// @Description Create a new user and attach one or more roles to it
+// @Description Create a new user and attach one or more roles to it, the user must hold the ROLES permission in the scopes of the roles
+// @Description and the permissions the roles grant
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Param UserWithRoles body UserWithRoles true "User Profile with Roles"
 // @Success 201 {object} models.User
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
 // @Router /user/roles [post]
 // @Security Bearer
 func CreateUserAndAttachRoles(c *gin.Context) {
@@ -28,12 +34,6 @@ func CreateUserAndAttachRoles(c *gin.Context) {
 		return
 	}
 
-	// Check permissions
-	if !permissions.RolesHavePermission(user.Roles, permissions.ROLES) {
-		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionRoles)
-		return
-	}
-
 	var userWithRoles UserWithRoles
 	if err := c.ShouldBindJSON(&userWithRoles); err != nil {
 		respondWithError(c, http.StatusBadRequest, err.Error())
@@ -52,6 +52,19 @@ func CreateUserAndAttachRoles(c *gin.Context) {
 		return
 	}
 
+	// The user must manage roles in the scopes of the roles and cannot grant more than they hold
+	roleIDs := make([]string, len(roles))
+	for i := range roles {
+		roleIDs[i] = roles[i].ID
+	}
+	if !userCanManageRoles(user, roleIDs) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionRoles)
+		return
+	}
+	if !checkRolesGrant(c, user, roleIDs, "", ErrCannotGrant) {
+		return
+	}
+
 	// Create the user
 	targetUser, err := createUser(userWithRoles.FirstName, userWithRoles.LastName, userWithRoles.Email)
 	if err != nil {
@@ -117,21 +130,16 @@ func GetUsersFromRoles(c *gin.Context) {
 	c.JSON(http.StatusOK, users)
 }
 
-// getUsersFromRoleIDs retrieves all users accessible via the specified roles
+// getUsersFromRoleIDs retrieves all users accessible via the specified roles,
+// through the groups of their scopes and the subgroups of these groups
 // roleIDs: IDs of the roles
 // returns: the list of users and any error
 func getUsersFromRoleIDs(roleIDs []string) ([]models.User, error) {
 	var userIDs []string
-	if err := database.DB.Raw(`
-		SELECT DISTINCT u.id
-			FROM users u
-			JOIN user_groups ug ON u.id = ug.user_id
-			JOIN groups g ON ug.group_id = g.id
-			JOIN scopes s ON g.scope_id = s.id
-			JOIN role_scopes rs ON s.id = rs.scope_id
-			JOIN roles r ON rs.role_id = r.id
-			WHERE r.id IN ?
-	`, roleIDs).Pluck("id", &userIDs).Error; err != nil {
+	if err := database.DB.Table("user_groups").
+		Distinct("user_id").
+		Where("group_id IN (?)", hierarchy.RoleGroups(roleIDs)).
+		Pluck("user_id", &userIDs).Error; err != nil {
 		return nil, err
 	}
 
@@ -147,14 +155,17 @@ func getUsersFromRoleIDs(roleIDs []string) ([]models.User, error) {
 
 // UpdateUserRoles updates a user's roles
 // @Summary Update the roles of a user
-// @Description Update the roles of a user
+// @Description Replace the roles of a user. The user must hold the ROLES permission in the scopes of the roles given and taken away and their permissions,
+// @Description and the last owner keeps their OWNER role
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Param roles body UserIdWithRoles true "User ID with Roles"
 // @Success 200 {object} models.User
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /user/roles/{userId} [put]
 // @Security Bearer
 func UpdateUserRoles(c *gin.Context) {
@@ -163,12 +174,6 @@ func UpdateUserRoles(c *gin.Context) {
 		return
 	}
 
-	// Check permissions
-	if !permissions.IsStaff(user) {
-		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionUsersRoles)
-		return
-	}
-
 	var userIdWithRoles UserIdWithRoles
 	if err := c.ShouldBindJSON(&userIdWithRoles); err != nil {
 		respondWithError(c, http.StatusBadRequest, err.Error())
@@ -190,17 +195,52 @@ func UpdateUserRoles(c *gin.Context) {
 		respondWithError(c, http.StatusNotFound, ErrRoleNotFound)
 		return
 	}
-	// Remove existing associations
-	if err := database.DB.Model(&targetUser).Association("Roles").Clear(); err != nil {
-		respondWithError(c, http.StatusInternalServerError, "Failed to clear user roles")
-		return
+
+	// The user cannot grant nor revoke more than they hold
+	current := make(map[string]bool, len(targetUser.Roles))
+	for _, role := range targetUser.Roles {
+		current[role.ID] = true
 	}
-	// Attach new roles to the user
+	var added, removed []string
 	for i := range roles {
-		if err := database.DB.Model(&targetUser).Association("Roles").Append(&roles[i]); err != nil {
-			respondWithError(c, http.StatusInternalServerError, "Failed to attach role to user")
-			return
+		if !current[roles[i].ID] {
+			added = append(added, roles[i].ID)
+		}
+		delete(current, roles[i].ID)
+	}
+	for roleID := range current {
+		removed = append(removed, roleID)
+	}
+	if !userCanManageRoles(user, append(append([]string{}, added...), removed...)) {
+		respondWithError(c, http.StatusUnauthorized, ErrNoPermissionRoles)
+		return
+	}
+	if !checkRolesGrant(c, user, added, targetUser.ID, ErrCannotGrant) ||
+		!checkRolesGrant(c, user, removed, targetUser.ID, ErrCannotRevoke) {
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// Remove existing associations
+		if err := tx.Model(&targetUser).Association("Roles").Clear(); err != nil {
+			return err
+		}
+		// Attach new roles to the user
+		for i := range roles {
+			if err := tx.Model(&targetUser).Association("Roles").Append(&roles[i]); err != nil {
+				return err
+			}
 		}
+		// The last owner keeps their OWNER role
+		return escalation.EnsureOwnerRemains(tx)
+	})
+	if errors.Is(err, escalation.ErrLastOwner) {
+		respondLastOwner(c, user, targetUser.ID)
+		return
+	}
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, "Failed to update user roles")
+		return
 	}
 	c.JSON(http.StatusOK, targetUser)
 }
\ No newline at end of file
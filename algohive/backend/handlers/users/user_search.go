@@ -0,0 +1,78 @@
+package users
+
+import (
+	"api/database"
+	"api/middleware"
+	"api/models"
+	"api/utils/pagination"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// userSearchText is the text searched for each user, it must match the expression of the idx_users_search index
+const userSearchText = "(firstname || ' ' || lastname || ' ' || email)"
+
+// SearchUsers searches the users accessible to the authenticated user
+// @Summary Search users
+// @Description Search the users the current user has access to by first name, last name, email or group name, tolerating typos. Results are ranked by relevance unless a sort is given
+// @Tags Users
+// @Param q query string true "Searched text"
+// @Param group_id query string false "Only members of this group"
+// @Param recursive query bool false "With group_id, also the members of its subgroups"
+// @Param role_id query string false "Only holders of this role"
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param sort query string false "Sort keys among firstname, lastname, email, last_connected, prefixed by - to sort descending"
+// @Success 200 {object} pagination.Page{items=[]models.User}
+// @Failure 400 {object} map[string]string
+// @Router /user/search [get]
+// @Security Bearer
+func SearchUsers(c *gin.Context) {
+	user, err := middleware.GetUserFromRequest(c)
+	if err != nil {
+		return
+	}
+
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		respondWithError(c, http.StatusBadRequest, ErrSearchQueryRequired)
+		return
+	}
+
+	params, err := pagination.Parse(c, userSortKeys, "lastname,firstname")
+	if err != nil {
+		respondWithError(c, http.StatusBadRequest, ErrInvalidListParams)
+		return
+	}
+
+	// Trigram similarity catches typos, the substring match catches queries too short for trigrams
+	// and the group match finds the members of the groups named like the query
+	matchingGroups := pagination.Search(database.DB.Table("groups").Select("id").Where("deleted_at IS NULL"), q, "name")
+	query := visibleUsers(user).Where(
+		"? <% "+userSearchText+" OR "+userSearchText+" ILIKE ? OR id IN (?)",
+		q, pagination.LikePattern(q),
+		database.DB.Table("user_groups").Select("user_id").Where("group_id IN (?)", matchingGroups),
+	)
+
+	query = filterUsersByMembership(c, query)
+
+	scopes := []func(*gorm.DB) *gorm.DB{preloadUserAssociations}
+	if c.Query("sort") == "" {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Select("*, word_similarity(?, "+userSearchText+") AS relevance", q)
+		})
+		params.Order = "relevance DESC, " + params.Order
+	}
+
+	var users []models.User
+	page, err := pagination.Find(query, params, &users, scopes...)
+	if err != nil {
+		respondWithError(c, http.StatusInternalServerError, ErrFailedToGetUsers)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
@@ -5,8 +5,11 @@ import (
 	"api/database"
 	docs "api/docs"
 	v1 "api/routes/v1"
+	"api/utils/lifecycle"
+	"api/utils/trash"
 
 	"log"
+	"os"
 	"strings"
 
 	"github.com/gin-contrib/cors"
@@ -32,6 +35,11 @@ import (
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
 func main() {
+    if len(os.Args) > 1 && os.Args[1] == "migrate" {
+        runMigrate(os.Args[2:])
+        return
+    }
+
     config.LoadConfig()
     log.Println("Config loaded")
 
@@ -41,6 +49,9 @@ func main() {
     database.InitRedis()
     log.Println("Redis connected")
 
+    trash.StartRetentionJob(config.TrashRetentionDays)
+    lifecycle.StartExpiryJob()
+
     gin.SetMode(gin.ReleaseMode)
     r := gin.Default()
 
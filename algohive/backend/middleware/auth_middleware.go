@@ -3,6 +3,7 @@ package middleware
 import (
 	"api/database"
 	"api/utils"
+	"api/utils/lifecycle"
 	"fmt"
 	"net/http"
 	"strings"
@@ -54,6 +55,13 @@ func AuthMiddleware() gin.HandlerFunc {
             return
         }
 
+        // Expired accounts are rejected before the daily job blocks them
+        if lifecycle.IsExpired(claims.UserID) {
+            c.JSON(http.StatusUnauthorized, gin.H{"error": "Your account has expired"})
+            c.Abort()
+            return
+        }
+
         // Set user ID in context
         c.Set("userID", claims.UserID)
         c.Set("email", claims.Email)
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"api/config"
+	"api/database"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+)
+
+const migrateUsage = `usage: main migrate <command>
+
+commands:
+  up        apply every pending migration
+  down [n]  revert the last n applied migrations (default 1)
+  status    list the migrations and whether they are applied
+  schema    print the schema of the current models, to start a new migration from`
+
+// runMigrate runs the migrate subcommand
+// args: the arguments following "migrate"
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		fmt.Println(migrateUsage)
+		os.Exit(2)
+	}
+
+	config.LoadConfig()
+
+	switch args[0] {
+	case "up":
+		database.Connect()
+		count, err := database.MigrateUp()
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Println("Applied", count, "migration(s)")
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				log.Fatal("the number of migrations to revert must be a positive integer")
+			}
+			steps = n
+		}
+
+		database.Connect()
+		count, err := database.MigrateDown(steps)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Println("Reverted", count, "migration(s)")
+	case "status":
+		database.Connect()
+		states, err := database.MigrationStatus()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, state := range states {
+			applied := "pending"
+			if state.AppliedAt != nil {
+				applied = "applied " + state.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", state.Version, state.Name, applied)
+		}
+	case "schema":
+		schema, err := database.SchemaSQL()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(schema)
+	default:
+		fmt.Println(migrateUsage)
+		os.Exit(2)
+	}
+}
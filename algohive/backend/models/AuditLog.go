@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// AuditLog records a sensitive operation: who did it, on which entity and from where.
+// Details is a JSON object describing the operation, it never holds the personal data it is about
+type AuditLog struct {
+	ID        string    `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
+	Action    string    `gorm:"type:varchar(50);not null;index" json:"action"`
+	ActorID   *string   `gorm:"type:uuid;column:actor_id;index" json:"actor_id"`
+	TargetID  *string   `gorm:"type:uuid;column:target_id;index" json:"target_id"`
+	Details   string    `gorm:"type:text;not null" json:"details"`
+	IP        string    `gorm:"type:varchar(45);column:ip" json:"ip"`
+	CreatedAt time.Time `gorm:"type:timestamp;not null;column:created_at;index" json:"created_at"`
+}
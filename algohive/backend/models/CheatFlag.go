@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Kinds of suspicious patterns detected between two users of a competition
+const (
+	CheatWrongAnswerCollision = "wrong_answer_collision"
+	CheatCopiedSolution       = "copied_solution"
+	CheatTimingPattern        = "timing_pattern"
+	CheatSharedIP             = "shared_ip"
+)
+
+// CheatFlag is a suspicious pattern found between two users by the anti-cheat analysis of a competition
+type CheatFlag struct {
+	ID            string       `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
+	CompetitionID string       `gorm:"type:uuid;not null;column:competition_id;index" json:"competition_id"`
+	UserID        string       `gorm:"type:uuid;not null;column:user_id" json:"user_id"`
+	OtherUserID   string       `gorm:"type:uuid;not null;column:other_user_id" json:"other_user_id"`
+	Kind          string       `gorm:"type:varchar(50);not null" json:"kind"`
+	PuzzleIndex   *int         `gorm:"type:integer;column:puzzle_index" json:"puzzle_index"`
+	Step          *int         `gorm:"type:integer" json:"step"`
+	Evidence      string       `gorm:"type:text;not null" json:"evidence"`
+	DetectedAt    time.Time    `gorm:"type:timestamp;not null;column:detected_at" json:"detected_at"`
+	Competition   *Competition `gorm:"foreignKey:CompetitionID" json:"-"`
+}
@@ -1,14 +1,24 @@
 package models
 
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
 type Competition struct {
 	ID              string    `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
-	Title           string    `gorm:"type:varchar(100);not null;unique" json:"title"`
+	Title           string    `gorm:"type:varchar(100);not null;index:idx_competitions_title,unique,where:deleted_at IS NULL" json:"title"`
 	Description     string    `gorm:"type:text;not null" json:"description"`
 	Finished        bool      `gorm:"not null" json:"finished"`
 	Show            bool      `gorm:"not null" json:"show"`
-	CatalogTheme        string    `gorm:"type:varchar(50);not null;column:catalog_theme" json:"catalog_theme"`
-	CatalogID string    `gorm:"type:uuid;not null;column:catalog_id" json:"catalog_id"`
-	Catalog  *Catalog   `gorm:"foreignKey:CatalogID" json:"catalog,omitempty"`
+	TeamMode        bool      `gorm:"not null;default:false;column:team_mode" json:"team_mode"`
+	StartsAt        *time.Time `gorm:"type:timestamp;column:starts_at" json:"starts_at"`
+	EndsAt          *time.Time `gorm:"type:timestamp;column:ends_at" json:"ends_at"`
+	Duration        *int      `gorm:"type:integer;column:duration" json:"duration"`
 	Groups         []*Group   `gorm:"many2many:competition_groups;" json:"groups,omitempty"`
 	Tries          []*Try     `gorm:"foreignKey:CompetitionID" json:"tries,omitempty"`
-}
\ No newline at end of file
+	Puzzles        []*CompetitionPuzzle `gorm:"foreignKey:CompetitionID" json:"puzzles,omitempty"`
+	Teams          []*Team    `gorm:"foreignKey:CompetitionID" json:"teams,omitempty"`
+	DeletedAt      gorm.DeletedAt `gorm:"type:timestamp;index" json:"deleted_at,omitempty" swaggertype:"string"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// CompetitionPuzzle is one entry of the ordered puzzle set of a competition.
+// Puzzle metadata is snapshotted from the catalog when the competition is created or opened,
+// so that PuzzleIndex values stay stable if the catalog changes afterwards
+type CompetitionPuzzle struct {
+	ID            string    `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
+	CompetitionID string    `gorm:"type:uuid;not null;column:competition_id;uniqueIndex:idx_competition_puzzle_index" json:"competition_id"`
+	PuzzleIndex   int       `gorm:"type:integer;not null;column:puzzle_index;uniqueIndex:idx_competition_puzzle_index" json:"puzzle_index"`
+	CatalogID     string    `gorm:"type:uuid;not null;column:catalog_id" json:"catalog_id"`
+	Theme         string    `gorm:"type:varchar(50);not null" json:"theme"`
+	PuzzleID      string    `gorm:"type:varchar(50);not null;column:puzzle_id" json:"puzzle_id"`
+	Weight        float64   `gorm:"type:numeric(10,2);not null;default:1" json:"weight"`
+	TimeLimit     *int      `gorm:"type:integer;column:time_limit" json:"time_limit"`
+	Name          string    `gorm:"type:varchar(100);not null" json:"name"`
+	Difficulty    string    `gorm:"type:varchar(50);not null" json:"difficulty"`
+	StatementHash string    `gorm:"type:varchar(64);not null;column:statement_hash" json:"statement_hash"`
+	SnapshotAt    time.Time `gorm:"type:timestamp;not null;column:snapshot_at" json:"snapshot_at"`
+	Catalog       *Catalog  `gorm:"foreignKey:CatalogID" json:"catalog,omitempty"`
+}
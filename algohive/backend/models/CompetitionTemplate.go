@@ -0,0 +1,26 @@
+package models
+
+// CompetitionTemplate is a reusable competition configuration, instantiated with new dates and groups
+type CompetitionTemplate struct {
+	ID          string                       `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
+	Name        string                       `gorm:"type:varchar(100);not null;unique" json:"name"`
+	Title       string                       `gorm:"type:varchar(100);not null" json:"title"`
+	Description string                       `gorm:"type:text;not null" json:"description"`
+	TeamMode    bool                         `gorm:"not null;default:false;column:team_mode" json:"team_mode"`
+	Duration    *int                         `gorm:"type:integer;column:duration" json:"duration"`
+	Puzzles     []*CompetitionTemplatePuzzle `gorm:"foreignKey:TemplateID" json:"puzzles,omitempty"`
+}
+
+// CompetitionTemplatePuzzle is one entry of the ordered puzzle set of a template.
+// Only the catalog reference is kept, metadata is snapshotted when the template is instantiated
+type CompetitionTemplatePuzzle struct {
+	ID          string   `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
+	TemplateID  string   `gorm:"type:uuid;not null;column:template_id;uniqueIndex:idx_template_puzzle_index" json:"template_id"`
+	PuzzleIndex int      `gorm:"type:integer;not null;column:puzzle_index;uniqueIndex:idx_template_puzzle_index" json:"puzzle_index"`
+	CatalogID   string   `gorm:"type:uuid;not null;column:catalog_id" json:"catalog_id"`
+	Theme       string   `gorm:"type:varchar(50);not null" json:"theme"`
+	PuzzleID    string   `gorm:"type:varchar(50);not null;column:puzzle_id" json:"puzzle_id"`
+	Weight      float64  `gorm:"type:numeric(10,2);not null;default:1" json:"weight"`
+	TimeLimit   *int     `gorm:"type:integer;column:time_limit" json:"time_limit"`
+	Catalog     *Catalog `gorm:"foreignKey:CatalogID" json:"catalog,omitempty"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// CompetitionTimeOverride replaces the start, end or duration of a competition for one user or one group.
+// Fields left empty keep the value of the competition
+type CompetitionTimeOverride struct {
+	ID            string       `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
+	CompetitionID string       `gorm:"type:uuid;not null;column:competition_id;index" json:"competition_id"`
+	UserID        *string      `gorm:"type:uuid;column:user_id" json:"user_id"`
+	GroupID       *string      `gorm:"type:uuid;column:group_id" json:"group_id"`
+	StartsAt      *time.Time   `gorm:"type:timestamp;column:starts_at" json:"starts_at"`
+	EndsAt        *time.Time   `gorm:"type:timestamp;column:ends_at" json:"ends_at"`
+	Duration      *int         `gorm:"type:integer;column:duration" json:"duration"`
+	Competition   *Competition `gorm:"foreignKey:CompetitionID" json:"-"`
+	User          *User        `gorm:"foreignKey:UserID" json:"-"`
+	Group         *Group       `gorm:"foreignKey:GroupID" json:"-"`
+}
@@ -1,10 +1,20 @@
 package models
 
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
 type Group struct {
     ID           string        `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
     Name         string        `gorm:"type:varchar(50);not null" json:"name"`
     Description  string        `gorm:"type:varchar(255)" json:"description"`
     ScopeID      string        `gorm:"type:uuid;not null" json:"scope_id"`
+    ParentID     *string       `gorm:"type:uuid;column:parent_id;index" json:"parent_id"`
+    ExpiresAt    *time.Time    `gorm:"type:timestamp;index" json:"expires_at"`
     Users        []*User       `gorm:"many2many:user_groups;" json:"users"`
     Competitions []*Competition `gorm:"many2many:competition_groups;" json:"competitions"`
+    Children     []*Group      `gorm:"foreignKey:ParentID" json:"children,omitempty"`
+    DeletedAt    gorm.DeletedAt `gorm:"type:timestamp;index" json:"deleted_at,omitempty" swaggertype:"string"`
 }
\ No newline at end of file
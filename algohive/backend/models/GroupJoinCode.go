@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// GroupJoinCode lets users enroll themselves in a group with a code, until it expires or reaches its maximum uses.
+// A group has at most one code, regenerating it replaces the previous one
+type GroupJoinCode struct {
+	ID        string     `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
+	GroupID   string     `gorm:"type:uuid;not null;column:group_id;uniqueIndex" json:"group_id"`
+	Code      string     `gorm:"type:varchar(16);not null;uniqueIndex" json:"code"`
+	ExpiresAt *time.Time `gorm:"type:timestamp;column:expires_at" json:"expires_at"`
+	MaxUses   *int       `gorm:"type:integer;column:max_uses" json:"max_uses"`
+	Uses      int        `gorm:"type:integer;not null;default:0" json:"uses"`
+	CreatedBy *string    `gorm:"type:uuid;column:created_by" json:"created_by"`
+	CreatedAt time.Time  `gorm:"type:timestamp;not null;column:created_at" json:"created_at"`
+	Group     *Group     `gorm:"foreignKey:GroupID" json:"-"`
+}
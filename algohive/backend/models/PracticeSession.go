@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// PracticeSession represents a user training on a catalog puzzle outside of any competition.
+// It is kept apart from Try so that competition statistics are not affected
+type PracticeSession struct {
+	ID             string     `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
+	UserID         string     `gorm:"type:uuid;not null;column:user_id;index" json:"user_id"`
+	CatalogID      string     `gorm:"type:uuid;not null;column:catalog_id" json:"catalog_id"`
+	Theme          string     `gorm:"type:varchar(50);not null" json:"theme"`
+	PuzzleID       string     `gorm:"type:varchar(50);not null;column:puzzle_id" json:"puzzle_id"`
+	PuzzleName     string     `gorm:"type:varchar(100);not null;column:puzzle_name" json:"puzzle_name"`
+	Difficulty     string     `gorm:"type:varchar(50);not null" json:"difficulty"`
+	StartTime      time.Time  `gorm:"type:timestamp;not null;column:start_time" json:"start_time"`
+	FirstSolvedAt  *time.Time `gorm:"type:timestamp;column:first_solved_at" json:"first_solved_at"`
+	SecondSolvedAt *time.Time `gorm:"type:timestamp;column:second_solved_at" json:"second_solved_at"`
+	Attempts       int        `gorm:"type:integer;not null;default:0" json:"attempts"`
+	User           *User      `gorm:"foreignKey:UserID" json:"-"`
+	Catalog        *Catalog   `gorm:"foreignKey:CatalogID" json:"-"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Registration modes
+const (
+	RegistrationModeDisabled = "disabled"
+	RegistrationModeOpen     = "open"
+	RegistrationModeDomain   = "domain"
+	RegistrationModeInvite   = "invite"
+)
+
+// RegistrationSettings controls self-registration at runtime, the table holds a single row
+type RegistrationSettings struct {
+	ID                       int       `gorm:"primary_key;autoIncrement:false" json:"-"`
+	Mode                     string    `gorm:"type:varchar(20);not null" json:"mode"`
+	RequireEmailVerification bool      `gorm:"not null;column:require_email_verification" json:"require_email_verification"`
+	UpdatedBy                *string   `gorm:"type:uuid;column:updated_by" json:"updated_by"`
+	UpdatedAt                time.Time `gorm:"type:timestamp;not null;column:updated_at" json:"updated_at"`
+}
+
+// RegistrationDomain allows the email addresses of a domain to register, in the domain mode,
+// and places the users registering with it in a group, or in the group named after the domain in a scope
+type RegistrationDomain struct {
+	ID        string    `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
+	Domain    string    `gorm:"type:varchar(255);not null;uniqueIndex" json:"domain"`
+	GroupID   *string   `gorm:"type:uuid;column:group_id" json:"group_id"`
+	ScopeID   *string   `gorm:"type:uuid;column:scope_id" json:"scope_id"`
+	CreatedAt time.Time `gorm:"type:timestamp;not null;column:created_at" json:"created_at"`
+}
+
+// RegistrationInvite lets one email address register, in the invite mode, optionally joining a group.
+// Only the SHA-256 hash of the token is stored
+type RegistrationInvite struct {
+	ID        string     `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
+	Email     string     `gorm:"type:varchar(255);not null;index" json:"email"`
+	TokenHash string     `gorm:"type:varchar(64);not null;uniqueIndex;column:token_hash" json:"-"`
+	GroupID   *string    `gorm:"type:uuid;column:group_id" json:"group_id"`
+	ExpiresAt time.Time  `gorm:"type:timestamp;not null;column:expires_at" json:"expires_at"`
+	UsedAt    *time.Time `gorm:"type:timestamp;column:used_at" json:"used_at"`
+	CreatedBy *string    `gorm:"type:uuid;column:created_by" json:"created_by"`
+	CreatedAt time.Time  `gorm:"type:timestamp;not null;column:created_at" json:"created_at"`
+}
+
+// EmailVerification is a single-use token confirming the email address of a registered user.
+// Only the SHA-256 hash of the token is stored
+type EmailVerification struct {
+	ID        string     `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
+	UserID    string     `gorm:"type:uuid;not null;column:user_id;index" json:"user_id"`
+	TokenHash string     `gorm:"type:varchar(64);not null;uniqueIndex;column:token_hash" json:"-"`
+	ExpiresAt time.Time  `gorm:"type:timestamp;not null;column:expires_at" json:"expires_at"`
+	UsedAt    *time.Time `gorm:"type:timestamp;column:used_at" json:"used_at"`
+	CreatedAt time.Time  `gorm:"type:timestamp;not null;column:created_at" json:"created_at"`
+}
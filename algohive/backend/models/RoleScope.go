@@ -0,0 +1,12 @@
+package models
+
+// RoleScope binds a role to a scope. Permissions is the permission mask the role grants in the scope,
+// nil when the role grants its own permissions there. OWNER in a mask makes the holders owners of the scope only,
+// the OWNER permission of the role itself stays the only global one
+type RoleScope struct {
+	RoleID      string `gorm:"type:uuid;primaryKey;column:role_id" json:"role_id"`
+	ScopeID     string `gorm:"type:uuid;primaryKey;column:scope_id" json:"scope_id"`
+	Permissions *int   `gorm:"type:integer;column:permissions" json:"permissions"`
+	Role        *Role  `gorm:"foreignKey:RoleID" json:"-"`
+	Scope       *Scope `gorm:"foreignKey:ScopeID" json:"-"`
+}
@@ -1,10 +1,13 @@
 package models
 
+import "gorm.io/gorm"
+
 type Scope struct {
     ID              string            `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
-    Name            string            `gorm:"type:varchar(50);unique;not null" json:"name"`
+    Name            string            `gorm:"type:varchar(50);not null;index:idx_scopes_name,unique,where:deleted_at IS NULL" json:"name"`
     Description     string            `gorm:"type:varchar(255)" json:"description"`
     Roles           []*Role           `gorm:"many2many:role_scopes;" json:"roles"`
     Catalogs        []*Catalog        `gorm:"many2many:scope_catalogs;" json:"catalogs"`
     Groups          []*Group          `gorm:"foreignKey:ScopeID" json:"groups"`
+    DeletedAt       gorm.DeletedAt    `gorm:"type:timestamp;index" json:"deleted_at,omitempty" swaggertype:"string"`
 }
\ No newline at end of file
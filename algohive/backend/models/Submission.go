@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Submission is one answer submitted for a try, logged with the client it came from.
+// LatencyMs is the time elapsed since the start of the try
+type Submission struct {
+	ID            string       `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
+	TryID         string       `gorm:"type:uuid;not null;column:try_id;index" json:"try_id"`
+	CompetitionID string       `gorm:"type:uuid;not null;column:competition_id;index" json:"competition_id"`
+	UserID        string       `gorm:"type:uuid;not null;column:user_id" json:"user_id"`
+	TeamID        *string      `gorm:"type:uuid;column:team_id" json:"team_id"`
+	PuzzleIndex   int          `gorm:"type:integer;not null;column:puzzle_index" json:"puzzle_index"`
+	Step          int          `gorm:"type:integer;not null" json:"step"`
+	Answer        string       `gorm:"type:text;not null" json:"answer"`
+	Correct       bool         `gorm:"not null" json:"correct"`
+	IP            string       `gorm:"type:varchar(45);column:ip" json:"ip"`
+	UserAgent     string       `gorm:"type:text;column:user_agent" json:"user_agent"`
+	SubmittedAt   time.Time    `gorm:"type:timestamp;not null;column:submitted_at" json:"submitted_at"`
+	LatencyMs     int64        `gorm:"type:bigint;not null;default:0;column:latency_ms" json:"latency_ms"`
+	Try           *Try         `gorm:"foreignKey:TryID" json:"-"`
+	Competition   *Competition `gorm:"foreignKey:CompetitionID" json:"-"`
+	User          *User        `gorm:"foreignKey:UserID" json:"-"`
+}
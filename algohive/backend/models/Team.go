@@ -0,0 +1,14 @@
+package models
+
+// Team groups users sharing one scoreboard entry and one input in a team competition.
+// team_members also holds the competition of the team, set by a trigger, with a unique index
+// on (competition_id, user_id) so a user belongs to one team per competition
+type Team struct {
+	ID            string       `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
+	Name          string       `gorm:"type:varchar(100);not null;uniqueIndex:idx_team_competition_name" json:"name"`
+	CompetitionID string       `gorm:"type:uuid;not null;column:competition_id;uniqueIndex:idx_team_competition_name" json:"competition_id"`
+	GroupID       *string      `gorm:"type:uuid;column:group_id" json:"group_id"`
+	Members       []*User      `gorm:"many2many:team_members;" json:"members"`
+	Competition   *Competition `gorm:"foreignKey:CompetitionID" json:"-"`
+	Group         *Group       `gorm:"foreignKey:GroupID" json:"-"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Types of the entities that go to the trash when deleted
+const (
+	TrashUser        = "user"
+	TrashGroup       = "group"
+	TrashCompetition = "competition"
+	TrashScope       = "scope"
+)
+
+// TrashedItem records a soft deleted entity with the associations cleared when it was deleted,
+// so restoring it can reattach them. Associations is a JSON object of the associated IDs by join table
+type TrashedItem struct {
+	ID           string    `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
+	EntityType   string    `gorm:"type:varchar(20);not null;column:entity_type;uniqueIndex:idx_trashed_entity,priority:1" json:"entity_type"`
+	EntityID     string    `gorm:"type:uuid;not null;column:entity_id;uniqueIndex:idx_trashed_entity,priority:2" json:"entity_id"`
+	Label        string    `gorm:"type:varchar(255);not null" json:"label"`
+	Associations string    `gorm:"type:text;not null" json:"-"`
+	DeletedBy    *string   `gorm:"type:uuid;column:deleted_by" json:"deleted_by"`
+	DeletedAt    time.Time `gorm:"type:timestamp;not null;column:deleted_at;index" json:"deleted_at"`
+}
@@ -8,11 +8,13 @@ type Try struct {
 	PuzzleLvl     string      `gorm:"type:varchar(50);not null;column:puzzle_lvl" json:"puzzle_lvl"`
 	Step          int         `gorm:"type:integer;not null" json:"step"`
 	StartTime     string      `gorm:"type:timestamp;not null;column:start_time" json:"start_time"`
-	EndTime       *string     `gorm:"type:timestamp;column:end_time" json:"end_time"`
+	EndTime       *string     `gorm:"type:timestamp;column:end_time;index:idx_tries_user_end,priority:2" json:"end_time"`
 	Attempts      int         `gorm:"type:integer;not null" json:"attempts"`
 	Score         float64     `gorm:"type:numeric(15,2);not null" json:"score"`
-	CompetitionID string      `gorm:"type:uuid;not null;column:competition_id" json:"competition_id"`
-	UserID        string      `gorm:"type:uuid;not null;column:user_id" json:"user_id"`
+	CompetitionID string      `gorm:"type:uuid;not null;column:competition_id;index:idx_tries_competition_user,priority:1;index:idx_tries_competition_team,priority:1" json:"competition_id"`
+	UserID        string      `gorm:"type:uuid;not null;column:user_id;index:idx_tries_user_end,priority:1;index:idx_tries_competition_user,priority:2" json:"user_id"`
+	TeamID        *string     `gorm:"type:uuid;column:team_id;index:idx_tries_competition_team,priority:2" json:"team_id"`
 	Competition   *Competition `gorm:"foreignKey:CompetitionID" json:"-"`
 	User          *User        `gorm:"foreignKey:UserID" json:"-"`
+	Team          *Team        `gorm:"foreignKey:TeamID" json:"-"`
 }
\ No newline at end of file
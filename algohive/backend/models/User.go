@@ -2,16 +2,22 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type User struct {
     ID            string     `gorm:"type:uuid;default:gen_random_uuid();primary_key" json:"id"`
     Firstname     string     `gorm:"type:varchar(50);not null" json:"firstname"`
     Lastname      string     `gorm:"type:varchar(50);not null" json:"lastname"`
-    Email         string     `gorm:"type:varchar(255);unique;not null" json:"email"`
+    Email         string     `gorm:"type:varchar(255);not null;index:idx_users_email,unique,where:deleted_at IS NULL" json:"email"`
     Password      string     `gorm:"type:varchar(255);not null" json:"password"`
     LastConnected *time.Time `gorm:"type:timestamp" json:"last_connected"` 
     Blocked       bool       `gorm:"not null;default:false" json:"blocked"`
+    ExpiresAt     *time.Time `gorm:"type:timestamp;index" json:"expires_at"`
+    ExpiredAt     *time.Time `gorm:"type:timestamp" json:"expired_at"`
+    EmailUnverified bool     `gorm:"not null;default:false;column:email_unverified" json:"email_unverified"`
     Groups        []*Group   `gorm:"many2many:user_groups;" json:"groups"`
     Roles         []*Role    `gorm:"many2many:user_roles;" json:"roles"`
+    DeletedAt     gorm.DeletedAt `gorm:"type:timestamp;index" json:"deleted_at,omitempty" swaggertype:"string"`
 }
\ No newline at end of file
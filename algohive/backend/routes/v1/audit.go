@@ -0,0 +1,12 @@
+package v1
+
+import (
+	"api/handlers/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterAuditRoutes registers the routes for API v1 audit trail
+func RegisterAuditRoutes(r *gin.RouterGroup) {
+	audit.RegisterRoutes(r)
+}
@@ -16,4 +16,8 @@ func Register(r *gin.Engine) {
 	RegisterGroupsRoutes(v1)
 	RegisterRolesRoutes(v1)
 	RegisterCompetitionsRoutes(v1)
+	RegisterPracticeRoutes(v1)
+	RegisterTrashRoutes(v1)
+	RegisterAuditRoutes(v1)
+	RegisterRegistrationRoutes(v1)
 }
\ No newline at end of file
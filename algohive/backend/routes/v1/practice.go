@@ -0,0 +1,13 @@
+package v1
+
+import (
+	"api/handlers/practice"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterPracticeRoutes registers routes for the practice v1 API
+// This function serves as a proxy to the dedicated handlers package
+func RegisterPracticeRoutes(r *gin.RouterGroup) {
+	practice.RegisterRoutes(r)
+}
@@ -0,0 +1,12 @@
+package v1
+
+import (
+	"api/handlers/registration"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRegistrationRoutes registers the routes for API v1 registration settings
+func RegisterRegistrationRoutes(r *gin.RouterGroup) {
+	registration.RegisterRoutes(r)
+}
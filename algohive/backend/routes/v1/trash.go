@@ -0,0 +1,12 @@
+package v1
+
+import (
+	"api/handlers/trash"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterTrashRoutes registers the routes for API v1 trash
+func RegisterTrashRoutes(r *gin.RouterGroup) {
+	trash.RegisterRoutes(r)
+}
@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"api/models"
+	"encoding/json"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Audited actions
+const (
+	ActionUserExported    = "user.exported"
+	ActionUserAnonymized  = "user.anonymized"
+	ActionGrantDenied     = "role.grant_denied"
+	ActionLastOwnerDenied = "role.last_owner_denied"
+)
+
+// Record adds an operation to the audit trail
+// tx: the transaction of the operation, so the record is only kept when the operation succeeds
+// c: the request of the operation, for the IP of the client
+// action: one of the Action* constants
+// actorID: ID of the user doing the operation
+// targetID: ID of the entity the operation is about, empty when there is none
+// details: JSON-encodable description of the operation, nil when there is none
+func Record(tx *gorm.DB, c *gin.Context, action string, actorID string, targetID string, details interface{}) error {
+	encoded := []byte("{}")
+	if details != nil {
+		var err error
+		if encoded, err = json.Marshal(details); err != nil {
+			return err
+		}
+	}
+
+	entry := models.AuditLog{
+		Action:    action,
+		ActorID:   &actorID,
+		Details:   string(encoded),
+		IP:        c.ClientIP(),
+		CreatedAt: time.Now(),
+	}
+	if targetID != "" {
+		entry.TargetID = &targetID
+	}
+
+	return tx.Create(&entry).Error
+}
@@ -0,0 +1,150 @@
+package beeapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrThemeNotFound is returned when the BeeAPI does not know the requested theme
+	ErrThemeNotFound = errors.New("theme not found")
+	// ErrPuzzleNotFound is returned when the BeeAPI does not know the requested puzzle
+	ErrPuzzleNotFound = errors.New("puzzle not found")
+)
+
+// Puzzle represents a puzzle as exposed by a BeeAPI catalog
+type Puzzle struct {
+	Author           string `json:"author"`
+	Cipher           string `json:"cipher"`
+	CompressedSize   int    `json:"compressedSize"`
+	CreatedAt        string `json:"createdAt"`
+	Difficulty       string `json:"difficulty"`
+	ID               string `json:"id"`
+	Language         string `json:"language"`
+	Name             string `json:"name"`
+	Obscure          string `json:"obscure"`
+	UncompressedSize int    `json:"uncompressedSize"`
+	UpdatedAt        string `json:"updatedAt"`
+}
+
+// Theme represents a theme as exposed by a BeeAPI catalog
+type Theme struct {
+	EnigmesCount int      `json:"enigmes_count"`
+	Name         string   `json:"name"`
+	Puzzles      []Puzzle `json:"puzzles"`
+	Size         int      `json:"size"`
+}
+
+// StatementHash returns a stable hash of the puzzle statement (cipher and obscure parts)
+func (p Puzzle) StatementHash() string {
+	sum := sha256.Sum256([]byte(p.Cipher + "\n" + p.Obscure))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetTheme fetches a single theme and its ordered puzzle list from a catalog
+// address: base address of the BeeAPI catalog
+// name: name of the theme
+func GetTheme(address string, name string) (*Theme, error) {
+	resp, err := http.Get(fmt.Sprintf("%s/theme?name=%s", address, url.QueryEscape(name)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrThemeNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status from catalog: %s", resp.Status)
+	}
+
+	var theme Theme
+	if err := json.NewDecoder(resp.Body).Decode(&theme); err != nil {
+		return nil, err
+	}
+
+	return &theme, nil
+}
+
+// Generation is a puzzle input generated for a unique ID, with the solutions of both steps
+type Generation struct {
+	InputLines     []string `json:"input_lines"`
+	FirstSolution  string   `json:"first_solution"`
+	SecondSolution string   `json:"second_solution"`
+}
+
+// Solution returns the expected answer of a puzzle step (1 or 2)
+func (g Generation) Solution(step int) string {
+	if step == 2 {
+		return g.SecondSolution
+	}
+	return g.FirstSolution
+}
+
+// GeneratePuzzle generates the input of a puzzle for a unique ID; the same ID always yields the same input
+// address: base address of the BeeAPI catalog
+// theme: name of the theme
+// puzzleName: name of the puzzle inside the theme
+// uniqueID: seed of the generated input
+func GeneratePuzzle(address string, theme string, puzzleName string, uniqueID string) (*Generation, error) {
+	query := url.Values{}
+	query.Set("theme", theme)
+	query.Set("puzzle", puzzleName)
+	query.Set("unique_id", uniqueID)
+
+	resp, err := http.Get(fmt.Sprintf("%s/puzzle/generate?%s", address, query.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status from catalog: %s", resp.Status)
+	}
+
+	var raw struct {
+		InputLines     []string    `json:"input_lines"`
+		FirstSolution  interface{} `json:"first_solution"`
+		SecondSolution interface{} `json:"second_solution"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	// The BeeAPI answers 200 with a message when the theme or the puzzle does not exist
+	if raw.InputLines == nil {
+		return nil, ErrPuzzleNotFound
+	}
+
+	return &Generation{
+		InputLines:     raw.InputLines,
+		FirstSolution:  formatSolution(raw.FirstSolution),
+		SecondSolution: formatSolution(raw.SecondSolution),
+	}, nil
+}
+
+// formatSolution normalizes a JSON solution (number or string) to the string users submit
+func formatSolution(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return strings.TrimSpace(v)
+	case nil:
+		return ""
+	default:
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
+
+// CheckAnswer compares a submitted answer with the expected solution
+func CheckAnswer(answer string, solution string) bool {
+	return solution != "" && strings.TrimSpace(answer) == solution
+}
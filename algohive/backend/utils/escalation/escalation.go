@@ -0,0 +1,233 @@
+package escalation
+
+import (
+	"api/database"
+	"api/models"
+	"api/utils/audit"
+	"api/utils/hierarchy"
+	"api/utils/permissions"
+	"errors"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ErrLastOwner aborts an operation that would leave no active user holding the OWNER permission
+var ErrLastOwner = errors.New("the last owner cannot lose the OWNER permission")
+
+// scopedPermissions are the permissions that only apply in the scopes a role is bound to,
+// the others are checked against the permissions of the roles themselves
+const scopedPermissions = permissions.GROUPS | permissions.COMPETITIONS | permissions.OWNER
+
+// ownerLockKey is the transaction-level advisory lock serializing the operations that can remove an owner,
+// so two of them cannot each leave the other as the last owner and both succeed
+const ownerLockKey = 320001
+
+// Grant describes the permissions held through roles: the permissions of the roles themselves
+// and the permission mask of each live scope they are bound to
+type Grant struct {
+	Permissions int
+	Scopes      map[string]int
+}
+
+// rolesGrant merges what roles grant
+// roleIDs: slice of role IDs or subquery selecting them
+func rolesGrant(roleIDs interface{}) (Grant, error) {
+	grant := Grant{Scopes: map[string]int{}}
+
+	var roles []models.Role
+	if err := database.DB.Select("id", "permissions").Where("id IN (?)", roleIDs).Find(&roles).Error; err != nil {
+		return grant, err
+	}
+	for _, role := range roles {
+		grant.Permissions |= role.Permissions
+	}
+
+	var bindings []struct {
+		ScopeID     string
+		Permissions int
+	}
+	if err := database.DB.Raw(`
+		SELECT rs.scope_id, COALESCE(rs.permissions, r.permissions) AS permissions FROM role_scopes rs
+		JOIN roles r ON r.id = rs.role_id
+		JOIN scopes s ON s.id = rs.scope_id AND s.deleted_at IS NULL
+		WHERE rs.role_id IN (?)
+	`, roleIDs).Scan(&bindings).Error; err != nil {
+		return grant, err
+	}
+	for _, binding := range bindings {
+		grant.Scopes[binding.ScopeID] |= binding.Permissions
+	}
+
+	return grant, nil
+}
+
+// RoleGrant returns what a role grants to its holders
+// roleID: ID of the role
+func RoleGrant(roleID string) (Grant, error) {
+	return rolesGrant([]string{roleID})
+}
+
+// UserGrant returns what the roles of a user grant them
+// userID: ID of the user
+func UserGrant(userID string) (Grant, error) {
+	return rolesGrant(database.DB.Table("user_roles").Select("role_id").Where("user_id = ?", userID))
+}
+
+// CanGrant checks that a user holds every permission of a grant, so they can give it to someone,
+// take it away or change it: the permissions of the roles among the permissions of their own roles,
+// and the scoped permissions of each scope among those their roles grant in that scope. Owners can grant anything
+// user: the authenticated user, with their roles
+// grant: the permissions given, taken away or changed
+func CanGrant(user models.User, grant Grant) (bool, error) {
+	if permissions.IsOwner(user) {
+		return true, nil
+	}
+
+	held := permissions.MergeRolePermissions(user.Roles)
+	if grant.Permissions&^held != 0 {
+		return false, nil
+	}
+	if len(grant.Scopes) == 0 {
+		return true, nil
+	}
+
+	heldScopes, err := hierarchy.UserScopePermissions(user.ID)
+	if err != nil {
+		return false, err
+	}
+	return holdsGrant(held, heldScopes, grant), nil
+}
+
+// holdsGrant checks that held permissions cover a grant, see CanGrant
+// held: the permissions of the roles of the user
+// heldScopes: the permission mask the roles of the user grant in each of their scopes, by scope ID
+// grant: the permissions given, taken away or changed
+func holdsGrant(held int, heldScopes map[string]int, grant Grant) bool {
+	if grant.Permissions&^held != 0 {
+		return false
+	}
+	for scopeID, mask := range grant.Scopes {
+		if mask&scopedPermissions&^heldScopes[scopeID] != 0 || mask&^scopedPermissions&^held != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// CanGrantRoles checks that a user holds every permission granted by roles
+// user: the authenticated user, with their roles
+// roleIDs: IDs of the roles
+func CanGrantRoles(user models.User, roleIDs []string) (bool, error) {
+	if permissions.IsOwner(user) || len(roleIDs) == 0 {
+		return true, nil
+	}
+
+	grant, err := rolesGrant(roleIDs)
+	if err != nil {
+		return false, err
+	}
+	return CanGrant(user, grant)
+}
+
+// CanManageUser checks that a user holds every permission the roles of a target user grant,
+// so staff members cannot block, delete or strip the roles of someone above them
+// user: the authenticated user, with their roles
+// targetUserID: ID of the target user
+func CanManageUser(user models.User, targetUserID string) (bool, error) {
+	if permissions.IsOwner(user) {
+		return true, nil
+	}
+
+	grant, err := UserGrant(targetUserID)
+	if err != nil {
+		return false, err
+	}
+	return CanGrant(user, grant)
+}
+
+// CanManageIn checks that a user holds a permission in every live scope of a list, so they can manage the roles
+// or the scopes of the list. What is bound to no scope belongs to no scope administrator, holding the permission
+// in one of their scopes is enough, as for competitions without groups. Owners manage everything
+// user: the authenticated user, with their roles
+// permission: permissions.ROLES or permissions.SCOPES
+// scopeIDs: slice of scope IDs or subquery selecting them
+func CanManageIn(user models.User, permission int, scopeIDs interface{}) (bool, error) {
+	if permissions.IsOwner(user) {
+		return true, nil
+	}
+
+	var counts struct {
+		Bound   int64
+		Missing int64
+	}
+	if err := database.DB.Raw(`
+		SELECT COUNT(*) AS bound, COUNT(*) FILTER (WHERE s.id NOT IN (?)) AS missing FROM scopes s
+		WHERE s.id IN (?) AND s.deleted_at IS NULL
+	`, hierarchy.ScopesWith(user.ID, permission), scopeIDs).Scan(&counts).Error; err != nil {
+		return false, err
+	}
+	if counts.Bound > 0 {
+		return counts.Missing == 0, nil
+	}
+
+	var held int64
+	if err := database.DB.Raw("SELECT COUNT(*) FROM (?) held", hierarchy.ScopesWith(user.ID, permission)).Scan(&held).Error; err != nil {
+		return false, err
+	}
+	return held > 0, nil
+}
+
+// CanManageRoles checks that a user holds the ROLES permission in every live scope roles are bound to,
+// so they can change or delete the roles, or give them to users and take them away
+// user: the authenticated user, with their roles
+// roleIDs: IDs of the roles
+func CanManageRoles(user models.User, roleIDs []string) (bool, error) {
+	return CanManageIn(user, permissions.ROLES, hierarchy.RoleScopes(roleIDs))
+}
+
+// LockOwners serializes a transaction that can remove an owner with the others, until it ends
+// tx: the transaction of the operation
+func LockOwners(tx *gorm.DB) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(?)", ownerLockKey).Error
+}
+
+// EnsureOwnerRemains checks, at the end of a transaction that can remove an owner, that an active user
+// still holds the OWNER permission through one of their roles, so the last owner role and user cannot be removed
+// tx: the transaction of the operation
+// returns: ErrLastOwner when no owner remains
+func EnsureOwnerRemains(tx *gorm.DB) error {
+	if err := LockOwners(tx); err != nil {
+		return err
+	}
+
+	var owners int64
+	if err := tx.Raw(`
+		SELECT COUNT(DISTINCT u.id) FROM users u
+		JOIN user_roles ur ON ur.user_id = u.id
+		JOIN roles r ON r.id = ur.role_id
+		WHERE (r.permissions & ?) = ? AND u.deleted_at IS NULL AND NOT u.blocked
+	`, permissions.OWNER, permissions.OWNER).Scan(&owners).Error; err != nil {
+		return err
+	}
+	if owners == 0 {
+		return ErrLastOwner
+	}
+
+	return nil
+}
+
+// Deny logs a rejected attempt to grant permissions or to remove the last owner and records it in the audit trail
+// c: the rejected request
+// action: audit.ActionGrantDenied or audit.ActionLastOwnerDenied
+// actorID: ID of the user whose attempt was rejected
+// targetID: ID of the role or user the attempt was about
+func Deny(c *gin.Context, action string, actorID string, targetID string) {
+	request := c.Request.Method + " " + c.FullPath()
+	log.Printf("Rejected %s: %s by user %s on %s", action, request, actorID, targetID)
+
+	if err := audit.Record(database.DB, c, action, actorID, targetID, gin.H{"request": request}); err != nil {
+		log.Printf("Failed to record the rejected %s of user %s: %v", action, actorID, err)
+	}
+}
@@ -0,0 +1,84 @@
+package escalation
+
+import (
+	"api/models"
+	"api/utils/permissions"
+	"testing"
+)
+
+func TestCanGrantWithoutScopes(t *testing.T) {
+	owner := models.User{Roles: []*models.Role{{Permissions: permissions.OWNER}}}
+	staff := models.User{Roles: []*models.Role{
+		{Permissions: permissions.GROUPS},
+		{Permissions: permissions.ROLES | permissions.API_ENV},
+	}}
+
+	tests := []struct {
+		name  string
+		user  models.User
+		grant Grant
+		want  bool
+	}{
+		{"owner grants anything", owner, Grant{Permissions: permissions.GetAdminPermissions()}, true},
+		{"owner grants in any scope", owner, Grant{Scopes: map[string]int{"s1": permissions.OWNER}}, true},
+		{"nothing to grant", staff, Grant{}, true},
+		{"permission of one role", staff, Grant{Permissions: permissions.GROUPS}, true},
+		{"permissions merged from several roles", staff, Grant{Permissions: permissions.GROUPS | permissions.ROLES}, true},
+		{"permission not held", staff, Grant{Permissions: permissions.COMPETITIONS}, false},
+		{"owner permission not held", staff, Grant{Permissions: permissions.OWNER}, false},
+		{"user without roles", models.User{}, Grant{Permissions: permissions.SCOPES}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CanGrant(tt.user, tt.grant)
+			if err != nil {
+				t.Fatalf("CanGrant() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CanGrant() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHoldsGrant(t *testing.T) {
+	held := permissions.GROUPS | permissions.COMPETITIONS | permissions.ROLES
+	heldScopes := map[string]int{
+		"toulouse":    permissions.GROUPS | permissions.COMPETITIONS,
+		"montpellier": permissions.COMPETITIONS,
+	}
+
+	tests := []struct {
+		name  string
+		grant Grant
+		want  bool
+	}{
+		{"empty grant", Grant{}, true},
+		{"role permissions held", Grant{Permissions: permissions.GROUPS | permissions.ROLES}, true},
+		{"role permission not held", Grant{Permissions: permissions.SCOPES}, false},
+		{"scoped permissions held in the scope", Grant{Scopes: map[string]int{"toulouse": permissions.GROUPS | permissions.COMPETITIONS}}, true},
+		{"scoped permission held in another scope only", Grant{Scopes: map[string]int{"montpellier": permissions.GROUPS}}, false},
+		{"scope of no role of the user", Grant{Scopes: map[string]int{"paris": permissions.COMPETITIONS}}, false},
+		{"empty mask in an unknown scope", Grant{Scopes: map[string]int{"paris": 0}}, true},
+		{"owner of a scope not held", Grant{Scopes: map[string]int{"toulouse": permissions.OWNER}}, false},
+		{"unscoped permission held through the roles", Grant{Scopes: map[string]int{"paris": permissions.ROLES}}, true},
+		{"unscoped permission not held", Grant{Scopes: map[string]int{"toulouse": permissions.API_ENV}}, false},
+		{"every scope must be held", Grant{Scopes: map[string]int{
+			"toulouse":    permissions.GROUPS,
+			"montpellier": permissions.GROUPS,
+		}}, false},
+		{"role and scopes held", Grant{
+			Permissions: permissions.COMPETITIONS,
+			Scopes:      map[string]int{"toulouse": permissions.GROUPS, "montpellier": permissions.COMPETITIONS},
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := holdsGrant(held, heldScopes, tt.grant); got != tt.want {
+				t.Errorf("holdsGrant() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package hierarchy
+
+import (
+	"api/database"
+
+	"gorm.io/gorm"
+)
+
+// Groups form trees through their parent_id: a member of a group is a member of all its ancestors,
+// and whatever is granted to a group (competitions, management through a scope) is granted to its descendants.
+// The recursive queries skip the groups in the trash and use UNION so a cycle cannot loop forever
+
+// Subtree selects the IDs of groups and of all their descendants
+// groupIDs: slice of group IDs or subquery selecting them
+// returns: the subquery of group IDs
+func Subtree(groupIDs interface{}) *gorm.DB {
+	return database.DB.Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT g.id FROM groups g
+			WHERE g.id IN (?) AND g.deleted_at IS NULL
+			UNION
+			SELECT child.id FROM groups child
+			JOIN subtree s ON child.parent_id = s.id
+			WHERE child.deleted_at IS NULL
+		)
+		SELECT id FROM subtree
+	`, groupIDs)
+}
+
+// MemberGroups selects the IDs of the groups a user belongs to, directly or through one of their subgroups
+// userID: ID of the user
+// returns: the subquery of group IDs
+func MemberGroups(userID string) *gorm.DB {
+	return database.DB.Raw(`
+		WITH RECURSIVE member_groups AS (
+			SELECT g.id, g.parent_id FROM groups g
+			JOIN user_groups ug ON ug.group_id = g.id
+			WHERE ug.user_id = ? AND g.deleted_at IS NULL
+			UNION
+			SELECT parent.id, parent.parent_id FROM groups parent
+			JOIN member_groups mg ON parent.id = mg.parent_id
+			WHERE parent.deleted_at IS NULL
+		)
+		SELECT id FROM member_groups
+	`, userID)
+}
+
+// ManagedGroups selects the IDs of the groups of the scopes reachable through the roles of a user, with their descendants
+// userID: ID of the user
+// returns: the subquery of group IDs
+func ManagedGroups(userID string) *gorm.DB {
+	return Subtree(database.DB.Raw(`
+		SELECT g.id FROM groups g
+		JOIN role_scopes rs ON rs.scope_id = g.scope_id
+		JOIN scopes s ON s.id = rs.scope_id AND s.deleted_at IS NULL
+		JOIN user_roles ur ON ur.role_id = rs.role_id
+		WHERE ur.user_id = ?
+	`, userID))
+}
+
+// scopeMask is the permission mask a role grants in one of its scopes: the mask of the binding, or the permissions of the role
+const scopeMask = "COALESCE(rs.permissions, r.permissions)"
+
+// ScopesWith selects the IDs of the scopes where a user holds a permission through one of their roles
+// userID: ID of the user
+// permission: the permission bits required, 0 selects every scope of the roles of the user
+// returns: the subquery of scope IDs
+func ScopesWith(userID string, permission int) *gorm.DB {
+	return database.DB.Raw(`
+		SELECT rs.scope_id FROM role_scopes rs
+		JOIN roles r ON r.id = rs.role_id
+		JOIN scopes s ON s.id = rs.scope_id AND s.deleted_at IS NULL
+		JOIN user_roles ur ON ur.role_id = rs.role_id
+		WHERE ur.user_id = ? AND (`+scopeMask+` & ?) = ?
+	`, userID, permission, permission)
+}
+
+// ManagedGroupsWith selects the IDs of the groups of the scopes where a user holds a permission, with their descendants
+// userID: ID of the user
+// permission: the permission bits required
+// returns: the subquery of group IDs
+func ManagedGroupsWith(userID string, permission int) *gorm.DB {
+	return Subtree(database.DB.Raw(`
+		SELECT g.id FROM groups g
+		JOIN scopes s ON s.id = g.scope_id AND s.deleted_at IS NULL
+		WHERE g.scope_id IN (?)
+	`, ScopesWith(userID, permission)))
+}
+
+// ScopePermissions merges the permissions a user holds in a scope through their roles
+// userID: ID of the user
+// scopeID: ID of the scope
+// returns: the permission mask, 0 when no role of the user is bound to the scope
+func ScopePermissions(userID string, scopeID string) (int, error) {
+	var masks []int
+	if err := database.DB.Raw(`
+		SELECT `+scopeMask+` FROM role_scopes rs
+		JOIN roles r ON r.id = rs.role_id
+		JOIN scopes s ON s.id = rs.scope_id AND s.deleted_at IS NULL
+		JOIN user_roles ur ON ur.role_id = rs.role_id
+		WHERE ur.user_id = ? AND rs.scope_id = ?
+	`, userID, scopeID).Scan(&masks).Error; err != nil {
+		return 0, err
+	}
+
+	permissions := 0
+	for _, mask := range masks {
+		permissions |= mask
+	}
+	return permissions, nil
+}
+
+// UserScopePermissions merges the permissions a user holds in each scope of their roles
+// userID: ID of the user
+// returns: the permission mask of each scope, by scope ID
+func UserScopePermissions(userID string) (map[string]int, error) {
+	var rows []struct {
+		ScopeID     string
+		Permissions int
+	}
+	if err := database.DB.Raw(`
+		SELECT rs.scope_id, BIT_OR(`+scopeMask+`) AS permissions FROM role_scopes rs
+		JOIN roles r ON r.id = rs.role_id
+		JOIN user_roles ur ON ur.role_id = rs.role_id
+		JOIN scopes s ON s.id = rs.scope_id AND s.deleted_at IS NULL
+		WHERE ur.user_id = ?
+		GROUP BY rs.scope_id
+	`, userID).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	permissions := make(map[string]int, len(rows))
+	for _, row := range rows {
+		permissions[row.ScopeID] = row.Permissions
+	}
+	return permissions, nil
+}
+
+// RoleGroups selects the IDs of the groups of the scopes of roles, with their descendants
+// roleIDs: IDs of the roles
+// returns: the subquery of group IDs
+func RoleGroups(roleIDs []string) *gorm.DB {
+	return Subtree(database.DB.Raw(`
+		SELECT g.id FROM groups g
+		JOIN role_scopes rs ON rs.scope_id = g.scope_id
+		JOIN scopes s ON s.id = rs.scope_id AND s.deleted_at IS NULL
+		WHERE rs.role_id IN ?
+	`, roleIDs))
+}
+
+// RoleScopes selects the IDs of the live scopes roles are bound to
+// roleIDs: IDs of the roles
+// returns: the subquery of scope IDs
+func RoleScopes(roleIDs []string) *gorm.DB {
+	return database.DB.Raw(`
+		SELECT rs.scope_id FROM role_scopes rs
+		JOIN scopes s ON s.id = rs.scope_id AND s.deleted_at IS NULL
+		WHERE rs.role_id IN ?
+	`, roleIDs)
+}
+
+// Members selects the IDs of the users of groups and of their descendants
+// groupIDs: slice of group IDs or subquery selecting them
+// returns: the subquery of user IDs
+func Members(groupIDs interface{}) *gorm.DB {
+	return database.DB.Table("user_groups").Distinct("user_id").Where("group_id IN (?)", Subtree(groupIDs))
+}
+
+// SubtreeMembers selects the members of groups, each one under every group of the list they belong to
+// directly or through one of its descendants
+// groupIDs: slice of group IDs or subquery selecting them
+// returns: the subquery of (group_id, user_id) pairs
+func SubtreeMembers(groupIDs interface{}) *gorm.DB {
+	return database.DB.Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT g.id AS root_id, g.id FROM groups g
+			WHERE g.id IN (?) AND g.deleted_at IS NULL
+			UNION
+			SELECT s.root_id, child.id FROM groups child
+			JOIN subtree s ON child.parent_id = s.id
+			WHERE child.deleted_at IS NULL
+		)
+		SELECT DISTINCT s.root_id AS group_id, ug.user_id FROM subtree s
+		JOIN user_groups ug ON ug.group_id = s.id
+	`, groupIDs)
+}
+
+// IsInSubtree checks if a group is one of the descendants of another group, or the group itself
+// groupID: ID of the root of the subtree
+// candidateID: ID of the group looked for
+func IsInSubtree(groupID string, candidateID string) (bool, error) {
+	var count int64
+	if err := database.DB.Raw("SELECT COUNT(*) FROM (?) subtree WHERE id = ?", Subtree([]string{groupID}), candidateID).
+		Scan(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
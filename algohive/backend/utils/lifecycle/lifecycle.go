@@ -0,0 +1,122 @@
+package lifecycle
+
+import (
+	"api/database"
+	"api/models"
+	"api/utils/escalation"
+	"api/utils/permissions"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// expiredTemplate matches the expired accounts of the users table aliased %[1]s at @now.
+// An account expires on its own expires_at, or, for users without roles, once every group
+// they belong to has expired or is in the trash: a student of a finished cohort still in a running one keeps access
+const expiredTemplate = `(COALESCE(%[1]s.expires_at <= @now, false) OR (
+	NOT EXISTS (SELECT 1 FROM user_roles ur WHERE ur.user_id = %[1]s.id)
+	AND EXISTS (SELECT 1 FROM user_groups ug WHERE ug.user_id = %[1]s.id)
+	AND NOT EXISTS (
+		SELECT 1 FROM user_groups ug
+		JOIN groups g ON g.id = ug.group_id
+		WHERE ug.user_id = %[1]s.id AND g.deleted_at IS NULL AND (g.expires_at IS NULL OR g.expires_at > @now)
+	)
+))`
+
+// ownerTemplate matches the users of the users table aliased %[1]s holding the OWNER permission through one of their roles
+const ownerTemplate = `EXISTS (
+	SELECT 1 FROM user_roles our
+	JOIN roles oro ON oro.id = our.role_id
+	WHERE our.user_id = %[1]s.id AND (oro.permissions & %[2]d) = %[2]d
+)`
+
+// expiredCondition matches the expired accounts of the users table aliased u at @now.
+// Owners do not expire while no other active owner remains, so the last owner is never locked out,
+// as on every other path that blocks accounts (see escalation.EnsureOwnerRemains)
+var expiredCondition = fmt.Sprintf(`(%[1]s AND NOT (%[2]s AND NOT EXISTS (
+	SELECT 1 FROM users o
+	WHERE o.deleted_at IS NULL AND NOT o.blocked AND %[3]s AND NOT %[4]s
+)))`,
+	fmt.Sprintf(expiredTemplate, "u"),
+	fmt.Sprintf(ownerTemplate, "u", permissions.OWNER),
+	fmt.Sprintf(ownerTemplate, "o", permissions.OWNER),
+	fmt.Sprintf(expiredTemplate, "o"),
+)
+
+// IsExpired checks if the account of a user has expired
+// userID: ID of the user
+func IsExpired(userID string) bool {
+	var expired bool
+	if err := database.DB.Raw(
+		"SELECT EXISTS (SELECT 1 FROM users u WHERE u.id = @id AND "+expiredCondition+")",
+		map[string]interface{}{"id": userID, "now": time.Now()},
+	).Scan(&expired).Error; err != nil {
+		return false
+	}
+	return expired
+}
+
+// BlockExpired blocks the expired accounts that are not blocked yet, recording when they expired
+// returns: the blocked users
+func BlockExpired() ([]models.User, error) {
+	now := time.Now()
+	var users []models.User
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// Owners blocked or deleted meanwhile would change which owners can expire
+		if err := escalation.LockOwners(tx); err != nil {
+			return err
+		}
+		return tx.Raw(`
+			UPDATE users u SET blocked = true, expired_at = @now
+			WHERE u.blocked = false AND u.deleted_at IS NULL AND `+expiredCondition+`
+			RETURNING u.id, u.firstname, u.lastname, u.email, u.expires_at
+		`, map[string]interface{}{"now": now}).Scan(&users).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// ReactivateUsers unblocks the users blocked by the expiry job whose account is no longer expired
+// tx: the transaction to update the users in
+// userIDs: slice of user IDs or subquery selecting them
+// returns: the number of reactivated users
+func ReactivateUsers(tx *gorm.DB, userIDs interface{}) (int64, error) {
+	result := tx.Exec(`
+		UPDATE users u SET blocked = false, expired_at = NULL
+		WHERE u.expired_at IS NOT NULL AND u.blocked = true
+		AND u.id IN (@users)
+		AND NOT `+expiredCondition,
+		map[string]interface{}{"users": userIDs, "now": time.Now()},
+	)
+	return result.RowsAffected, result.Error
+}
+
+// ReactivateMembers unblocks the members of a group blocked by the expiry job whose account is no longer expired
+// tx: the transaction to update the members in
+// groupID: ID of the group
+// returns: the number of reactivated members
+func ReactivateMembers(tx *gorm.DB, groupID string) (int64, error) {
+	return ReactivateUsers(tx, tx.Table("user_groups").Select("user_id").Where("group_id = ?", groupID))
+}
+
+// StartExpiryJob blocks the expired accounts now and then every day, reporting each of them in the logs
+func StartExpiryJob() {
+	go func() {
+		for {
+			users, err := BlockExpired()
+			if err != nil {
+				log.Println("Failed to block the expired accounts:", err)
+			} else if len(users) > 0 {
+				log.Println("Blocked", len(users), "expired account(s):")
+				for _, user := range users {
+					log.Println(" -", user.Email, user.Firstname, user.Lastname)
+				}
+			}
+			time.Sleep(24 * time.Hour)
+		}
+	}()
+}
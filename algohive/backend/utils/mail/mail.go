@@ -0,0 +1,37 @@
+package mail
+
+import (
+	"api/config"
+	"log"
+	"net/smtp"
+	"strings"
+)
+
+// Send sends a plain text email. Without SMTP_HOST, the email is written to the logs instead,
+// so registrations can be completed on a development setup
+// to: address of the recipient
+// subject: subject of the email
+// body: plain text content of the email
+func Send(to string, subject string, body string) error {
+	if config.SMTPHost == "" {
+		log.Printf("Email to %s (SMTP_HOST not set): %s\n%s", to, subject, body)
+		return nil
+	}
+
+	message := strings.Join([]string{
+		"From: " + config.SMTPFrom,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	var auth smtp.Auth
+	if config.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", config.SMTPUsername, config.SMTPPassword, config.SMTPHost)
+	}
+
+	return smtp.SendMail(config.SMTPHost+":"+config.SMTPPort, auth, config.SMTPFrom, []string{to}, []byte(message))
+}
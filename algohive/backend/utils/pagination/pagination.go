@@ -0,0 +1,166 @@
+package pagination
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultLimit is the page size used when the request does not set one
+	DefaultLimit = 50
+	// MaxLimit is the largest page size a request can ask for
+	MaxLimit = 200
+)
+
+var (
+	// ErrInvalidPage is returned when page or limit are not positive integers
+	ErrInvalidPage = errors.New("page and limit must be positive integers")
+	// ErrInvalidSort is returned when the sort parameter uses an unknown key
+	ErrInvalidSort = errors.New("unknown sort key")
+	// ErrInvalidFilter is returned when a filter parameter cannot be parsed
+	ErrInvalidFilter = errors.New("invalid filter value")
+)
+
+// Params are the paging and sorting parameters of a list request
+type Params struct {
+	Page  int
+	Limit int
+	Order string
+}
+
+// Page is the envelope of every paginated list response
+type Page struct {
+	Items interface{} `json:"items"`
+	Total int64       `json:"total"`
+	Page  int         `json:"page"`
+	Limit int         `json:"limit"`
+	Pages int64       `json:"pages"`
+}
+
+// Parse reads the page, limit and sort query parameters of a list request.
+// sort is a comma separated list of keys, a leading "-" sorts descending: ?sort=-score,lastname
+// sortable: the accepted sort keys and the column each one orders by
+// defaultSort: the sort used when the request does not set one, in the same format
+func Parse(c *gin.Context, sortable map[string]string, defaultSort string) (Params, error) {
+	params := Params{Page: 1, Limit: DefaultLimit}
+
+	if page := c.Query("page"); page != "" {
+		value, err := strconv.Atoi(page)
+		if err != nil || value < 1 {
+			return params, ErrInvalidPage
+		}
+		params.Page = value
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 1 {
+			return params, ErrInvalidPage
+		}
+		params.Limit = min(value, MaxLimit)
+	}
+
+	sort := c.DefaultQuery("sort", defaultSort)
+	var order []string
+	for _, key := range strings.Split(sort, ",") {
+		direction := "ASC"
+		if strings.HasPrefix(key, "-") {
+			direction = "DESC"
+			key = key[1:]
+		}
+
+		column, ok := sortable[key]
+		if !ok {
+			return params, ErrInvalidSort
+		}
+		order = append(order, column+" "+direction)
+	}
+	// The ID breaks ties so pages never overlap
+	params.Order = strings.Join(append(order, "id"), ", ")
+
+	return params, nil
+}
+
+// Find counts the rows matched by a query, then loads the requested page of them into dest
+// query: the filtered query, on a model so it can be counted
+// scopes: applied to the page query only, typically the preloads
+func Find(query *gorm.DB, params Params, dest interface{}, scopes ...func(*gorm.DB) *gorm.DB) (*Page, error) {
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	if err := query.Scopes(scopes...).
+		Order(params.Order).
+		Offset((params.Page - 1) * params.Limit).
+		Limit(params.Limit).
+		Find(dest).Error; err != nil {
+		return nil, err
+	}
+
+	return &Page{
+		Items: dest,
+		Total: total,
+		Page:  params.Page,
+		Limit: params.Limit,
+		Pages: (total + int64(params.Limit) - 1) / int64(params.Limit),
+	}, nil
+}
+
+// Search filters a query on the rows where any of the columns contains the text, case insensitive
+// q: the searched text, the query is returned unchanged when it is empty
+func Search(query *gorm.DB, q string, columns ...string) *gorm.DB {
+	q = strings.TrimSpace(q)
+	if q == "" || len(columns) == 0 {
+		return query
+	}
+
+	pattern := LikePattern(q)
+	conditions := make([]string, len(columns))
+	args := make([]interface{}, len(columns))
+	for i, column := range columns {
+		conditions[i] = column + " ILIKE ?"
+		args[i] = pattern
+	}
+
+	return query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+}
+
+// LikePattern returns the ILIKE pattern matching the rows that contain the text, wildcards in the text are escaped
+func LikePattern(text string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(text) + "%"
+}
+
+// Bool reads an optional boolean filter from the query string
+// returns: nil when the parameter is absent
+func Bool(c *gin.Context, name string) (*bool, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, ErrInvalidFilter
+	}
+	return &value, nil
+}
+
+// Int reads an optional integer filter from the query string
+// returns: nil when the parameter is absent
+func Int(c *gin.Context, name string) (*int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, ErrInvalidFilter
+	}
+	return &value, nil
+}
@@ -0,0 +1,52 @@
+package pagination
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParse(t *testing.T) {
+	sortable := map[string]string{"name": "name", "score": "total_score"}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    Params
+		wantErr error
+	}{
+		{"defaults", "", Params{Page: 1, Limit: DefaultLimit, Order: "name ASC, id"}, nil},
+		{"page and limit", "page=3&limit=20", Params{Page: 3, Limit: 20, Order: "name ASC, id"}, nil},
+		{"limit capped", "limit=1000", Params{Page: 1, Limit: MaxLimit, Order: "name ASC, id"}, nil},
+		{"several keys mapped to their column", "sort=-score,name", Params{Page: 1, Limit: DefaultLimit, Order: "total_score DESC, name ASC, id"}, nil},
+		{"page zero", "page=0", Params{}, ErrInvalidPage},
+		{"negative limit", "limit=-5", Params{}, ErrInvalidPage},
+		{"page not a number", "page=two", Params{}, ErrInvalidPage},
+		{"unknown sort key", "sort=password", Params{}, ErrInvalidSort},
+		{"empty sort key", "sort=name,", Params{}, ErrInvalidSort},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/?"+tt.query, nil)
+
+			got, err := Parse(c, sortable, "name")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package registration
+
+import (
+	"api/config"
+	"api/database"
+	"api/models"
+	"api/utils/mail"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// VerificationValidity is how long an email verification link can be used
+	VerificationValidity = 48 * time.Hour
+	// InviteValidity is how long an invitation can be used when it does not set its own expiry
+	InviteValidity = 14 * 24 * time.Hour
+)
+
+// settingsID is the ID of the single row of the registration settings
+const settingsID = 1
+
+// Modes lists the valid registration modes
+var Modes = []string{
+	models.RegistrationModeDisabled,
+	models.RegistrationModeOpen,
+	models.RegistrationModeDomain,
+	models.RegistrationModeInvite,
+}
+
+// LoadSettings reads the registration settings, registration is open with email verification when they were never saved
+func LoadSettings() (models.RegistrationSettings, error) {
+	settings := models.RegistrationSettings{ID: settingsID, Mode: models.RegistrationModeOpen, RequireEmailVerification: true}
+	err := database.DB.First(&settings, settingsID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return settings, nil
+	}
+	return settings, err
+}
+
+// SaveSettings replaces the registration settings
+func SaveSettings(settings models.RegistrationSettings) error {
+	settings.ID = settingsID
+	settings.UpdatedAt = time.Now()
+	return database.DB.Save(&settings).Error
+}
+
+// NewToken draws a random token to send to a user
+// returns: the token and its hash, the only part to store
+func NewToken() (string, string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	token := hex.EncodeToString(raw)
+	return token, HashToken(token), nil
+}
+
+// HashToken hashes a token received from a user to look it up
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// EmailDomain extracts the lowercase domain of an email address
+func EmailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ""
+	}
+	return strings.ToLower(email[at+1:])
+}
+
+// FindDomainRule looks up the rule of the domain of an email address
+// returns: the rule, nil when the domain has none
+func FindDomainRule(email string) (*models.RegistrationDomain, error) {
+	var rule models.RegistrationDomain
+	err := database.DB.Where("domain = ?", EmailDomain(email)).First(&rule).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// DomainGroup resolves the group a domain rule places new users in: its group, or the group named
+// after the domain in its scope, created on the first registration. Rules pointing to a group or scope
+// in the trash place users nowhere
+// tx: the transaction of the registration
+// rule: the rule of the domain of the user
+// returns: the ID of the group, empty when there is none
+func DomainGroup(tx *gorm.DB, rule *models.RegistrationDomain) (string, error) {
+	if rule.GroupID != nil {
+		var group models.Group
+		if err := tx.Select("id").Where("id = ?", *rule.GroupID).First(&group).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return "", nil
+			}
+			return "", err
+		}
+		return group.ID, nil
+	}
+
+	if rule.ScopeID == nil {
+		return "", nil
+	}
+
+	var scope models.Scope
+	if err := tx.Select("id").Where("id = ?", *rule.ScopeID).First(&scope).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	name := "@" + rule.Domain
+	if len(name) > 50 {
+		name = name[:50]
+	}
+	group := models.Group{Name: name, ScopeID: scope.ID, Description: "Users registered with an address of " + rule.Domain}
+	if err := tx.Where("scope_id = ? AND name = ?", scope.ID, name).FirstOrCreate(&group).Error; err != nil {
+		return "", err
+	}
+	return group.ID, nil
+}
+
+// CreateVerification replaces the pending email verifications of a user with a new one
+// tx: the transaction of the registration
+// userID: ID of the user
+// returns: the token to send to the user
+func CreateVerification(tx *gorm.DB, userID string) (string, error) {
+	token, hash, err := NewToken()
+	if err != nil {
+		return "", err
+	}
+
+	if err := tx.Where("user_id = ? AND used_at IS NULL", userID).Delete(&models.EmailVerification{}).Error; err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	return token, tx.Create(&models.EmailVerification{
+		UserID:    userID,
+		TokenHash: hash,
+		ExpiresAt: now.Add(VerificationValidity),
+		CreatedAt: now,
+	}).Error
+}
+
+// SendVerificationEmail sends the link confirming the email address of a user
+func SendVerificationEmail(user models.User, token string) error {
+	link := config.AppURL + "/verify-email?token=" + url.QueryEscape(token)
+	return mail.Send(user.Email, "Confirm your AlgoHive account",
+		"Hello "+user.Firstname+",\n\n"+
+			"Open this link to confirm your email address and activate your account:\n"+link+"\n\n"+
+			"The link expires in 48 hours. If you did not register, ignore this email.\n")
+}
+
+// SendInviteEmail sends the link to register with an invitation
+func SendInviteEmail(invite models.RegistrationInvite, token string) error {
+	link := config.AppURL + "/register?invite=" + url.QueryEscape(token) + "&email=" + url.QueryEscape(invite.Email)
+	return mail.Send(invite.Email, "You are invited to AlgoHive",
+		"Hello,\n\n"+
+			"You are invited to create an AlgoHive account with this address. Open this link to register:\n"+link+"\n\n"+
+			"The invitation expires on "+invite.ExpiresAt.Format("2006-01-02 15:04")+".\n")
+}
@@ -0,0 +1,302 @@
+package trash
+
+import (
+	"api/database"
+	"api/models"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrUnknownType is returned for an entity type that does not go to the trash
+	ErrUnknownType = errors.New("unknown trash type")
+	// ErrConflict is returned when a live entity took the unique name of the restored one
+	ErrConflict = errors.New("a live entity uses the same unique value")
+	// ErrParentTrashed is returned when restoring a group whose scope is in the trash
+	ErrParentTrashed = errors.New("the parent of the entity is in the trash")
+)
+
+// maxLabelLength is the size of the label column of the trash
+const maxLabelLength = 255
+
+// association is a many-to-many link cleared when an entity goes to the trash and reattached on restore
+type association struct {
+	table       string // join table
+	column      string // column referencing the trashed entity
+	otherColumn string // column referencing the associated row
+	otherTable  string // table of the associated rows
+	softDeleted bool   // whether the associated rows can be in the trash themselves
+}
+
+// entity describes how a type of entity goes to the trash
+type entity struct {
+	table        string
+	associations []association
+	// parent is the table referenced by parentColumn, it must be live to restore the entity
+	parentColumn string
+	parentTable  string
+	// purge are the statements removing the rows that depend on the entity, before the entity itself
+	purge []string
+}
+
+var entities = map[string]entity{
+	models.TrashUser: {
+		table: "users",
+		associations: []association{
+			{"user_roles", "user_id", "role_id", "roles", false},
+			{"user_groups", "user_id", "group_id", "groups", true},
+			{"team_members", "user_id", "team_id", "teams", false},
+		},
+		purge: []string{
+			"DELETE FROM submissions WHERE user_id = @id OR try_id IN (SELECT id FROM tries WHERE user_id = @id)",
+			"DELETE FROM tries WHERE user_id = @id",
+			"DELETE FROM practice_sessions WHERE user_id = @id",
+			"DELETE FROM cheat_flags WHERE user_id = @id OR other_user_id = @id",
+			"DELETE FROM competition_time_overrides WHERE user_id = @id",
+			"DELETE FROM email_verifications WHERE user_id = @id",
+		},
+	},
+	models.TrashGroup: {
+		table: "groups",
+		associations: []association{
+			{"user_groups", "group_id", "user_id", "users", true},
+			{"competition_groups", "group_id", "competition_id", "competitions", true},
+		},
+		parentColumn: "scope_id",
+		parentTable:  "scopes",
+		purge: []string{
+			"DELETE FROM competition_time_overrides WHERE group_id = @id",
+			"UPDATE teams SET group_id = NULL WHERE group_id = @id",
+			"UPDATE groups SET parent_id = NULL WHERE parent_id = @id",
+			"DELETE FROM group_join_codes WHERE group_id = @id",
+			"UPDATE registration_domains SET group_id = NULL WHERE group_id = @id",
+			"UPDATE registration_invites SET group_id = NULL WHERE group_id = @id",
+		},
+	},
+	models.TrashCompetition: {
+		table: "competitions",
+		associations: []association{
+			{"competition_groups", "competition_id", "group_id", "groups", true},
+		},
+		purge: []string{
+			"DELETE FROM submissions WHERE competition_id = @id",
+			"DELETE FROM tries WHERE competition_id = @id",
+			"DELETE FROM competition_puzzles WHERE competition_id = @id",
+			"DELETE FROM cheat_flags WHERE competition_id = @id",
+			"DELETE FROM team_members WHERE team_id IN (SELECT id FROM teams WHERE competition_id = @id)",
+			"DELETE FROM teams WHERE competition_id = @id",
+			"DELETE FROM competition_time_overrides WHERE competition_id = @id",
+		},
+	},
+	models.TrashScope: {
+		table: "scopes",
+		// The roles stay bound to a trashed scope so the permissions of the bindings survive a restore,
+		// nothing is reachable through them since the groups of the scope are in the trash too
+		associations: []association{
+			{"scope_catalogs", "scope_id", "catalog_id", "catalogs", false},
+		},
+		purge: []string{
+			"UPDATE registration_domains SET scope_id = NULL WHERE scope_id = @id",
+			"DELETE FROM role_scopes WHERE scope_id = @id",
+		},
+	},
+}
+
+// IsType checks if an entity type goes to the trash
+func IsType(entityType string) bool {
+	_, ok := entities[entityType]
+	return ok
+}
+
+// MoveToTrash soft deletes an entity, recording then clearing its associations
+// tx: the transaction to delete the entity in
+// entityType: one of the models.Trash* types
+// entityID: ID of the entity
+// label: name of the entity shown in the trash
+// deletedBy: ID of the user deleting the entity
+func MoveToTrash(tx *gorm.DB, entityType string, entityID string, label string, deletedBy string) error {
+	e, ok := entities[entityType]
+	if !ok {
+		return ErrUnknownType
+	}
+
+	snapshot := make(map[string][]string, len(e.associations))
+	for _, a := range e.associations {
+		var ids []string
+		if err := tx.Table(a.table).Where(a.column+" = ?", entityID).Pluck(a.otherColumn, &ids).Error; err != nil {
+			return err
+		}
+		snapshot[a.table] = ids
+
+		if err := tx.Exec("DELETE FROM "+a.table+" WHERE "+a.column+" = ?", entityID).Error; err != nil {
+			return err
+		}
+	}
+
+	associations, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	if runes := []rune(label); len(runes) > maxLabelLength {
+		label = string(runes[:maxLabelLength])
+	}
+
+	now := time.Now()
+	if err := tx.Table(e.table).Where("id = ?", entityID).Update("deleted_at", now).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(&models.TrashedItem{
+		EntityType:   entityType,
+		EntityID:     entityID,
+		Label:        label,
+		Associations: string(associations),
+		DeletedBy:    &deletedBy,
+		DeletedAt:    now,
+	}).Error
+}
+
+// Restore brings an entity back from the trash and reattaches the associated rows that still exist
+// tx: the transaction to restore the entity in
+// item: the trash record of the entity
+func Restore(tx *gorm.DB, item *models.TrashedItem) error {
+	e, ok := entities[item.EntityType]
+	if !ok {
+		return ErrUnknownType
+	}
+
+	if e.parentTable != "" {
+		var live int64
+		if err := tx.Table(e.parentTable).
+			Where("id = (SELECT "+e.parentColumn+" FROM "+e.table+" WHERE id = ?) AND deleted_at IS NULL", item.EntityID).
+			Count(&live).Error; err != nil {
+			return err
+		}
+		if live == 0 {
+			return ErrParentTrashed
+		}
+	}
+
+	if err := tx.Table(e.table).Where("id = ?", item.EntityID).Update("deleted_at", nil).Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrConflict
+		}
+		return err
+	}
+
+	var snapshot map[string][]string
+	if err := json.Unmarshal([]byte(item.Associations), &snapshot); err != nil {
+		return err
+	}
+
+	for _, a := range e.associations {
+		ids := snapshot[a.table]
+		if len(ids) == 0 {
+			continue
+		}
+
+		// Rows deleted or trashed since then are not reattached
+		live := ""
+		if a.softDeleted {
+			live = " AND deleted_at IS NULL"
+		}
+		if err := tx.Exec(
+			"INSERT INTO "+a.table+" ("+a.column+", "+a.otherColumn+") "+
+				"SELECT ?, id FROM "+a.otherTable+" WHERE id IN ?"+live+" ON CONFLICT DO NOTHING",
+			item.EntityID, ids,
+		).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Delete(item).Error
+}
+
+// Purge permanently deletes an entity of the trash with the rows depending on it
+// tx: the transaction to delete the entity in
+// item: the trash record of the entity
+func Purge(tx *gorm.DB, item *models.TrashedItem) error {
+	e, ok := entities[item.EntityType]
+	if !ok {
+		return ErrUnknownType
+	}
+
+	// The groups of a trashed scope are in the trash too, they go first
+	if item.EntityType == models.TrashScope {
+		var groups []models.TrashedItem
+		if err := tx.Where("entity_type = ? AND entity_id IN (?)", models.TrashGroup,
+			tx.Table("groups").Select("id").Where("scope_id = ?", item.EntityID)).
+			Find(&groups).Error; err != nil {
+			return err
+		}
+		for i := range groups {
+			if err := Purge(tx, &groups[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, statement := range e.purge {
+		if err := tx.Exec(statement, map[string]interface{}{"id": item.EntityID}).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Exec("DELETE FROM "+e.table+" WHERE id = ?", item.EntityID).Error; err != nil {
+		return err
+	}
+
+	return tx.Delete(item).Error
+}
+
+// PurgeExpired purges the entities that have been in the trash longer than the retention period
+// returns: the number of purged entities
+func PurgeExpired(retention time.Duration) (int, error) {
+	var items []models.TrashedItem
+	if err := database.DB.Where("deleted_at < ?", time.Now().Add(-retention)).
+		Order("deleted_at").Find(&items).Error; err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for i := range items {
+		if err := database.DB.Transaction(func(tx *gorm.DB) error {
+			return Purge(tx, &items[i])
+		}); err != nil {
+			// The item may have been purged with its scope, or restored meanwhile
+			log.Println("Failed to purge", items[i].EntityType, items[i].EntityID, "from the trash:", err)
+			continue
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// StartRetentionJob purges the expired entities of the trash now and then every day
+// retentionDays: how long entities stay in the trash, 0 keeps them forever
+func StartRetentionJob(retentionDays int) {
+	if retentionDays <= 0 {
+		return
+	}
+
+	retention := time.Duration(retentionDays) * 24 * time.Hour
+	go func() {
+		for {
+			purged, err := PurgeExpired(retention)
+			if err != nil {
+				log.Println("Failed to purge the trash:", err)
+			} else if purged > 0 {
+				log.Println("Purged", purged, "item(s) from the trash")
+			}
+			time.Sleep(24 * time.Hour)
+		}
+	}()
+}
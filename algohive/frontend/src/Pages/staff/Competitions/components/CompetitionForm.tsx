@@ -4,6 +4,7 @@ import { Dialog } from "primereact/dialog";
 import { Button } from "primereact/button";
 import { InputText } from "primereact/inputtext";
 import { InputTextarea } from "primereact/inputtextarea";
+import { InputNumber } from "primereact/inputnumber";
 import { Dropdown } from "primereact/dropdown";
 import { MultiSelect } from "primereact/multiselect";
 import { Checkbox } from "primereact/checkbox";
@@ -22,10 +23,33 @@ import {
   removeGroupFromCompetition,
   fetchCompetitionGroups,
 } from "../../../../services/competitionsService";
-import { Competition } from "../../../../models/Competition";
-import { Catalog } from "../../../../models/Catalogs";
+import {
+  Competition,
+  CompetitionPayload,
+  CompetitionPuzzleInput,
+} from "../../../../models/Competition";
+import { Catalog, Puzzle, Theme } from "../../../../models/Catalogs";
 import { fetchScopes } from "../../../../services/scopesService";
 
+// A puzzle of the edited set, with its name for display
+interface PuzzleEntry extends CompetitionPuzzleInput {
+  name: string;
+}
+
+// Compares puzzle sets on what the backend stores, in order
+const samePuzzles = (
+  a: CompetitionPuzzleInput[],
+  b: CompetitionPuzzleInput[]
+) =>
+  a.length === b.length &&
+  a.every(
+    (puzzle, i) =>
+      puzzle.catalog_id === b[i].catalog_id &&
+      puzzle.theme === b[i].theme &&
+      puzzle.puzzle_id === b[i].puzzle_id &&
+      (puzzle.weight ?? 1) === (b[i].weight ?? 1)
+  );
+
 interface CompetitionFormProps {
   visible: boolean;
   mode: "create" | "edit";
@@ -47,8 +71,11 @@ export default function CompetitionForm({
   // Form state
   const [title, setTitle] = useState<string>("");
   const [description, setDescription] = useState<string>("");
+  const [puzzles, setPuzzles] = useState<PuzzleEntry[]>([]);
   const [selectedCatalog, setSelectedCatalog] = useState<Catalog | null>(null);
-  const [selectedTheme, setSelectedTheme] = useState<string | null>(null);
+  const [selectedTheme, setSelectedTheme] = useState<Theme | null>(null);
+  const [selectedPuzzle, setSelectedPuzzle] = useState<Puzzle | null>(null);
+  const [weight, setWeight] = useState<number>(1);
   const [groups, setGroups] = useState<{ id: string; name: string }[]>([]);
   const [isVisible, setIsVisible] = useState<boolean>(true);
   const [isFinished, setIsFinished] = useState<boolean>(false);
@@ -56,7 +83,7 @@ export default function CompetitionForm({
 
   // Data lists
   const [catalogs, setCatalogs] = useState<Catalog[]>([]);
-  const [themes, setThemes] = useState<string[]>([]);
+  const [themes, setThemes] = useState<Theme[]>([]);
   const [availableGroups, setAvailableGroups] = useState<
     { id: string; name: string }[]
   >([]);
@@ -97,18 +124,22 @@ export default function CompetitionForm({
 
   useEffect(() => {
     if (mode === "edit" && competition) {
-      const selectedCatalog = catalogs.find(
-        (catalog) => catalog.id === competition.catalog_id
-      );
-
       const selectedGroups = availableGroups.filter((group) =>
         competition.groups?.some((g) => g.id === group.id)
       );
       setGroups(selectedGroups);
       setTitle(competition.title || "");
       setDescription(competition.description || "");
-      setSelectedCatalog(selectedCatalog as Catalog);
-      setSelectedTheme(competition.catalog_theme || "");
+      setPuzzles(
+        (competition.puzzles || []).map((puzzle) => ({
+          catalog_id: puzzle.catalog_id,
+          theme: puzzle.theme,
+          puzzle_id: puzzle.puzzle_id,
+          weight: puzzle.weight,
+          time_limit: puzzle.time_limit,
+          name: puzzle.name,
+        }))
+      );
       setIsVisible(competition.show);
       setIsFinished(competition.finished);
     } else {
@@ -121,10 +152,12 @@ export default function CompetitionForm({
     const loadThemes = async () => {
       if (selectedCatalog) {
         const data = await fetchCatalogThemes(selectedCatalog.id);
-        setThemes(data.map((theme) => theme.name));
+        setThemes(data);
       }
     };
 
+    setSelectedTheme(null);
+    setSelectedPuzzle(null);
     if (selectedCatalog) {
       loadThemes();
     } else {
@@ -132,6 +165,37 @@ export default function CompetitionForm({
     }
   }, [selectedCatalog]);
 
+  const addPuzzle = () => {
+    if (!selectedCatalog || !selectedTheme || !selectedPuzzle) return;
+    setPuzzles([
+      ...puzzles,
+      {
+        catalog_id: selectedCatalog.id,
+        theme: selectedTheme.name,
+        puzzle_id: selectedPuzzle.id,
+        weight,
+        name: selectedPuzzle.name,
+      },
+    ]);
+    setSelectedPuzzle(null);
+    setWeight(1);
+  };
+
+  const movePuzzle = (index: number, offset: number) => {
+    const target = index + offset;
+    if (target < 0 || target >= puzzles.length) return;
+    const reordered = [...puzzles];
+    [reordered[index], reordered[target]] = [
+      reordered[target],
+      reordered[index],
+    ];
+    setPuzzles(reordered);
+  };
+
+  const removePuzzle = (index: number) => {
+    setPuzzles(puzzles.filter((_, i) => i !== index));
+  };
+
   const loadCompetitionGroups = async (competitionId: string) => {
     try {
       const data = await fetchCompetitionGroups(competitionId);
@@ -149,8 +213,11 @@ export default function CompetitionForm({
   const resetForm = () => {
     setTitle("");
     setDescription("");
-    setSelectedTheme("");
+    setPuzzles([]);
+    setSelectedTheme(null);
     setSelectedCatalog(null);
+    setSelectedPuzzle(null);
+    setWeight(1);
     setGroups([]);
     setIsVisible(true);
     setIsFinished(false);
@@ -181,32 +248,36 @@ export default function CompetitionForm({
       );
       return;
     }
-    if (!selectedTheme) {
-      showToast(
-        "error",
-        t("staffTabs.competitions.messages.catalogThemeRequired")
-      );
-      return;
-    }
-    if (!selectedCatalog) {
-      showToast(
-        "error",
-        t("staffTabs.competitions.messages.apiEnvironmentRequired")
-      );
+    if (puzzles.length === 0) {
+      showToast("error", t("staffTabs.competitions.messages.puzzlesRequired"));
       return;
     }
 
     try {
       setSubmitting(true);
 
-      const competitionData = {
+      const puzzleSet: CompetitionPuzzleInput[] = puzzles.map(
+        (puzzle) => ({
+          catalog_id: puzzle.catalog_id,
+          theme: puzzle.theme,
+          puzzle_id: puzzle.puzzle_id,
+          weight: puzzle.weight,
+          time_limit: puzzle.time_limit,
+        })
+      );
+      const competitionData: CompetitionPayload = {
         title,
         description,
-        catalog_theme: selectedTheme,
-        catalog_id: selectedCatalog.id,
         show: isVisible,
         finished: isFinished,
       };
+      // The puzzle set is only replaced when it changed, it cannot change once somebody played
+      if (
+        mode === "create" ||
+        !samePuzzles(puzzleSet, competition?.puzzles || [])
+      ) {
+        competitionData.puzzles = puzzleSet;
+      }
 
       let result;
       if (mode === "create") {
@@ -326,6 +397,54 @@ export default function CompetitionForm({
             )}
           </div>
 
+          <div className="field mb-4">
+            <label>{t("staffTabs.competitions.form.puzzles")}</label>
+            {puzzles.length === 0 ? (
+              <small className="p-error block">
+                {t("staffTabs.competitions.messages.puzzlesRequired")}
+              </small>
+            ) : (
+              <ol className="list-decimal pl-6 mb-2">
+                {puzzles.map((puzzle, index) => (
+                  <li
+                    key={`${puzzle.catalog_id}-${puzzle.puzzle_id}-${index}`}
+                    className="mb-1"
+                  >
+                    <div className="flex items-center justify-between gap-2">
+                      <span>
+                        {puzzle.name}{" "}
+                        <span className="text-gray-500">
+                          ({puzzle.theme},{" "}
+                          {t("staffTabs.competitions.form.weight")}{" "}
+                          {puzzle.weight ?? 1})
+                        </span>
+                      </span>
+                      <span className="flex gap-1">
+                        <Button
+                          icon="pi pi-arrow-up"
+                          className="p-button-text p-button-sm"
+                          disabled={index === 0}
+                          onClick={() => movePuzzle(index, -1)}
+                        />
+                        <Button
+                          icon="pi pi-arrow-down"
+                          className="p-button-text p-button-sm"
+                          disabled={index === puzzles.length - 1}
+                          onClick={() => movePuzzle(index, 1)}
+                        />
+                        <Button
+                          icon="pi pi-trash"
+                          className="p-button-text p-button-danger p-button-sm"
+                          onClick={() => removePuzzle(index)}
+                        />
+                      </span>
+                    </div>
+                  </li>
+                ))}
+              </ol>
+            )}
+          </div>
+
           <div className="field mb-4">
             <label htmlFor="apiEnvironment">
               {t("staffTabs.competitions.form.apiEnvironment")}
@@ -337,14 +456,8 @@ export default function CompetitionForm({
               options={catalogs}
               optionLabel="name"
               placeholder={t("common.selects.catalogs")}
-              className={!selectedCatalog ? "p-invalid" : ""}
               filter
             />
-            {!selectedCatalog && (
-              <small className="p-error">
-                {t("staffTabs.competitions.messages.apiEnvironmentRequired")}
-              </small>
-            )}
           </div>
 
           {selectedCatalog && (
@@ -357,18 +470,49 @@ export default function CompetitionForm({
                 value={selectedTheme}
                 onChange={(e) => {
                   setSelectedTheme(e.value);
+                  setSelectedPuzzle(null);
                 }}
                 options={themes}
                 optionLabel="name"
                 placeholder={t("common.selects.themes")}
-                className={!selectedTheme ? "p-invalid" : ""}
                 filter
               />
-              {!selectedTheme && (
-                <small className="p-error">
-                  {t("staffTabs.competitions.messages.catalogThemeRequired")}
-                </small>
-              )}
+            </div>
+          )}
+
+          {selectedTheme && (
+            <div className="field mb-4">
+              <label htmlFor="puzzle">
+                {t("staffTabs.competitions.form.puzzle")}
+              </label>
+              <div className="flex gap-2 items-center">
+                <Dropdown
+                  id="puzzle"
+                  value={selectedPuzzle}
+                  onChange={(e) => setSelectedPuzzle(e.value)}
+                  options={selectedTheme.puzzles}
+                  optionLabel="name"
+                  placeholder={t("common.selects.puzzles")}
+                  className="flex-1"
+                  filter
+                />
+                <InputNumber
+                  value={weight}
+                  onValueChange={(e) => setWeight(e.value ?? 1)}
+                  min={0}
+                  minFractionDigits={0}
+                  maxFractionDigits={2}
+                  placeholder={t("staffTabs.competitions.form.weight")}
+                  style={{ width: "8rem" }}
+                />
+                <Button
+                  icon="pi pi-plus"
+                  label={t("staffTabs.competitions.form.addPuzzle")}
+                  disabled={!selectedPuzzle}
+                  onClick={addPuzzle}
+                  style={{ width: "auto" }}
+                />
+              </div>
             </div>
           )}
 
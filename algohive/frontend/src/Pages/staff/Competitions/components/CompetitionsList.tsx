@@ -128,9 +128,8 @@ export default function CompetitionsList({
           )}
         />
         <Column
-          field="catalog_theme"
-          header={t("staffTabs.competitions.form.catalogTheme")}
-          sortable
+          header={t("staffTabs.competitions.form.puzzles")}
+          body={(rowData: Competition) => rowData.puzzles?.length ?? 0}
           style={{ width: "15rem" }}
         />
         <Column
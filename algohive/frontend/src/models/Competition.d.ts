@@ -2,15 +2,49 @@ import { Catalog } from "./Catalogs";
 import { Group } from "./Group";
 import { Try } from "./Try";
 
+// One puzzle of the ordered puzzle set of a competition, snapshotted from its catalog
+export interface CompetitionPuzzle {
+  id: string;
+  competition_id: string;
+  puzzle_index: number;
+  catalog_id: string;
+  theme: string;
+  puzzle_id: string;
+  weight: number;
+  time_limit: number | null;
+  name: string;
+  difficulty: string;
+  statement_hash: string;
+  snapshot_at: string;
+  catalog?: Catalog;
+}
+
+// A puzzle of the set sent when creating or updating a competition
+export interface CompetitionPuzzleInput {
+  catalog_id: string;
+  theme: string;
+  puzzle_id: string;
+  weight?: number;
+  time_limit?: number | null;
+}
+
 export interface Competition {
   id: string;
   title: string;
   description: string;
   finished: boolean;
   show: boolean;
-  catalog_theme: string;
-  catalog_id: string;
-  catalog?: Catalog;
+  puzzles?: CompetitionPuzzle[];
   groups?: Group[];
   tries: Try[];
 }
+
+// Body of the create and update requests, puzzles are only sent to replace the puzzle set
+export interface CompetitionPayload {
+  title?: string;
+  description?: string;
+  puzzles?: CompetitionPuzzleInput[];
+  group_ids?: string[];
+  show?: boolean;
+  finished?: boolean;
+}
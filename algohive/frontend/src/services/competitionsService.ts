@@ -1,7 +1,8 @@
 import { ApiClient } from "../config/ApiClient";
-import { Competition } from "../models/Competition";
+import { Competition, CompetitionPayload } from "../models/Competition";
 import { Group } from "../models/Group";
 import { Try } from "../models/Try";
+import { fetchAllPages } from "./pagination";
 
 export interface CompetitionStatistics {
   competition_id: string;
@@ -15,8 +16,7 @@ export interface CompetitionStatistics {
 
 // Get all competitions
 export const fetchCompetitions = async (): Promise<Competition[]> => {
-  const response = await ApiClient.get("/competitions/");
-  return response.data;
+  return fetchAllPages<Competition>("/competitions/");
 };
 
 // Get competition details
@@ -29,7 +29,7 @@ export const fetchCompetitionDetails = async (
 
 // Create a new competition
 export const createCompetition = async (
-  competition: Partial<Competition>
+  competition: CompetitionPayload
 ): Promise<Competition> => {
   const response = await ApiClient.post("/competitions/", competition);
   return response.data;
@@ -38,7 +38,7 @@ export const createCompetition = async (
 // Update an existing competition
 export const updateCompetition = async (
   id: string,
-  competition: Partial<Competition>
+  competition: CompetitionPayload
 ): Promise<Competition> => {
   const response = await ApiClient.put(`/competitions/${id}`, competition);
   return response.data;
@@ -104,8 +104,7 @@ export const removeGroupFromCompetition = async (
 
 // Get competition tries
 export const fetchCompetitionTries = async (id: string): Promise<Try[]> => {
-  const response = await ApiClient.get(`/competitions/${id}/tries`);
-  return response.data;
+  return fetchAllPages<Try>(`/competitions/${id}/tries`);
 };
 
 // Get user competition tries
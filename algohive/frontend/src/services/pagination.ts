@@ -0,0 +1,39 @@
+import { ApiClient } from "../config/ApiClient";
+
+// Envelope of the paginated list endpoints
+export interface Page<T> {
+  items: T[];
+  total: number;
+  page: number;
+  limit: number;
+  pages: number;
+}
+
+// Largest page size accepted by the API
+const MAX_PAGE_SIZE = 200;
+
+// Fetch every page of a paginated list endpoint
+export async function fetchAllPages<T>(
+  url: string,
+  params: Record<string, string | number | boolean> = {}
+): Promise<T[]> {
+  const items: T[] = [];
+  let page = 1;
+  let pages = 1;
+
+  do {
+    const response = await ApiClient.get<Page<T>>(url, {
+      params: { ...params, page, limit: MAX_PAGE_SIZE },
+    });
+
+    if (response.status !== 200) {
+      throw new Error(`Error: ${response.status}`);
+    }
+
+    items.push(...response.data.items);
+    pages = response.data.pages;
+    page++;
+  } while (page <= pages);
+
+  return items;
+}
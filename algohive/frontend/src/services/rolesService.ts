@@ -1,15 +1,10 @@
 import { ApiClient } from "../config/ApiClient";
 import { Role } from "../models/Role";
+import { fetchAllPages } from "./pagination";
 
 export async function fetchRoles(): Promise<Role[]> {
   try {
-    const response = await ApiClient.get("/roles/");
-
-    if (response.status !== 200) {
-      throw new Error(`Error: ${response.status}`);
-    }
-
-    return response.data;
+    return await fetchAllPages<Role>("/roles/");
   } catch (error) {
     console.error("Error fetching roles:", error);
     throw error;
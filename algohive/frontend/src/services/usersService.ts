@@ -1,15 +1,10 @@
 import { ApiClient } from "../config/ApiClient";
 import { User } from "../models/User";
+import { fetchAllPages } from "./pagination";
 
 export async function fetchUsers(): Promise<User[]> {
   try {
-    const response = await ApiClient.get("/user/");
-
-    if (response.status !== 200) {
-      throw new Error(`Error: ${response.status}`);
-    }
-
-    return response.data;
+    return await fetchAllPages<User>("/user/");
   } catch (error) {
     console.error("Error fetching users:", error);
     throw error;